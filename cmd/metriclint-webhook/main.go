@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command metriclint-webhook is a Kubernetes validating admission webhook linting the metrics of the
+// targets of ServiceMonitor and PodMonitor resources on creation and update, so that clusters can
+// enforce metric hygiene at deploy time.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	// Register the presets, so that configs can refer to them in their bundles entry.
+	_ "github.com/promlint/promlint/pkg/metriclint/presets"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+)
+
+func main() {
+	listen := flag.String("listen", ":8443", "address to serve the webhook on")
+	certFile := flag.String("tls-cert-file", "", "TLS certificate, the webhook is served over plain HTTP if empty")
+	keyFile := flag.String("tls-key-file", "", "TLS private key")
+	configPath := flag.String("config", "", "path of the config, the default rules if empty")
+	deny := flag.Bool("deny", true, "deny the monitors whose targets have error issues, only warn about them if false")
+	timeout := flag.Duration("timeout", 8*time.Second, "timeout of scraping all the targets of a monitor, keep it below the timeoutSeconds of the webhook")
+	allowedTargets := flag.String("allowed-targets", "", "comma separated URL prefixes the targets annotation may list besides the targets of the monitor")
+	maxTargets := flag.Int("max-targets", 3, "maximum number of targets scraped per monitor")
+	flag.Parse()
+
+	config := &metriclint.Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = metriclint.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "metriclint-webhook: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	for _, warning := range config.Warnings() {
+		fmt.Fprintf(os.Stderr, "metriclint-webhook: warning: %v\n", warning)
+	}
+	linter, err := metriclint.NewLinterFromConfig(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "metriclint-webhook: %v\n", err)
+		os.Exit(1)
+	}
+	allowed, err := parseAllowedTargets(*allowedTargets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "metriclint-webhook: %v\n", err)
+		os.Exit(1)
+	}
+
+	wh := &webhook{
+		linter:     promadapter.NewLinter(linter),
+		scraper:    newScraper(),
+		deny:       *deny,
+		maxTargets: *maxTargets,
+		timeout:    *timeout,
+		allowed:    allowed,
+	}
+	if kube, err := inClusterClient(); err == nil {
+		wh.resolve = kube.targets
+	} else {
+		fmt.Fprintf(os.Stderr, "metriclint-webhook: only linting the targets of the %s annotation: %v\n", targetsAnnotation, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", wh)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+
+	if *certFile == "" {
+		err = http.ListenAndServe(*listen, mux)
+	} else {
+		err = http.ListenAndServeTLS(*listen, *certFile, *keyFile, mux)
+	}
+	fmt.Fprintf(os.Stderr, "metriclint-webhook: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+)
+
+const (
+	// targetsAnnotation lists, comma separated, the URLs to lint instead of the targets of the monitor,
+	// e.g. for a dry run before the selected pods exist. Only the targets of the monitor and the URLs
+	// under --allowed-targets are scraped, so that monitors can't make the webhook request any URL.
+	targetsAnnotation = "metriclint.promlint.io/targets"
+
+	// dryRunAnnotation set to "true" only warns about the issues of the monitor, whatever --deny.
+	dryRunAnnotation = "metriclint.promlint.io/dry-run"
+
+	// maxWarnings bounds the warnings of a response, the API server truncates long ones.
+	maxWarnings = 20
+
+	// maxScrapeBytes bounds the exposition read from a target.
+	maxScrapeBytes = 16 << 20
+)
+
+// newScraper returns the client scraping the targets. It doesn't follow redirects, which could point
+// it outside of the targets of the monitor and of --allowed-targets.
+func newScraper() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errors.New("redirects are not followed")
+		},
+	}
+}
+
+type admissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *admissionRequest  `json:"request,omitempty"`
+	Response   *admissionResponse `json:"response,omitempty"`
+}
+
+type admissionRequest struct {
+	UID  string `json:"uid"`
+	Kind struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"kind"`
+	Namespace string          `json:"namespace"`
+	Object    json.RawMessage `json:"object"`
+}
+
+type admissionResponse struct {
+	UID      string           `json:"uid"`
+	Allowed  bool             `json:"allowed"`
+	Status   *admissionStatus `json:"status,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+type admissionStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// webhook reviews ServiceMonitor and PodMonitor admissions by scraping their targets and linting the
+// scraped metrics. Issues failing the verdict of the linter deny the admission when deny is set, other
+// issues become warnings.
+type webhook struct {
+	linter     *promadapter.Linter
+	scraper    *http.Client
+	deny       bool
+	maxTargets int
+
+	// timeout bounds scraping all the targets of a monitor, it must be shorter than the admission timeout.
+	timeout time.Duration
+
+	// allowed are the URL prefixes the targets annotation may list besides the targets of the monitor.
+	allowed []*url.URL
+
+	// resolve returns the URLs of the targets of a monitor, nil if only the targets annotation is used.
+	resolve func(m *monitor) ([]string, error)
+}
+
+func (wh *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review admissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+		http.Error(w, "expected an AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.review(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// review lints the targets of the monitor of the request. Admissions of other kinds, and monitors whose
+// targets can't be scraped, are allowed: the targets may not be deployed yet.
+func (wh *webhook) review(req *admissionRequest) *admissionResponse {
+	resp := &admissionResponse{UID: req.UID, Allowed: true}
+	if req.Kind.Kind != serviceMonitorKind && req.Kind.Kind != podMonitorKind {
+		return resp
+	}
+
+	m := &monitor{}
+	if err := json.Unmarshal(req.Object, m); err != nil {
+		resp.Warnings = []string{fmt.Sprintf("metriclint: invalid %s: %v", req.Kind.Kind, err)}
+		return resp
+	}
+	m.Kind = req.Kind.Kind
+	// The targets are looked up in the namespace of the request, whatever the object claims.
+	if req.Namespace != "" {
+		m.Metadata.Namespace = req.Namespace
+	}
+
+	targets, err := wh.targets(m)
+	if err != nil {
+		resp.Warnings = []string{fmt.Sprintf("metriclint: %v", err)}
+		return resp
+	}
+	if len(targets) == 0 {
+		resp.Warnings = []string{"metriclint: no target to lint"}
+		return resp
+	}
+	if len(targets) > wh.maxTargets {
+		targets = targets[:wh.maxTargets]
+	}
+
+	var warnings, failing []string
+	for i, scrape := range wh.scrape(targets) {
+		if scrape.err != nil {
+			warnings = append(warnings, fmt.Sprintf("metriclint: %s: %v", targets[i], scrape.err))
+			continue
+		}
+		verdict := wh.linter.Judge(scrape.results)
+		for _, result := range verdict.Results {
+			for _, issue := range result.Findings {
+				finding := fmt.Sprintf("metriclint: %s: %s: %s", targets[i], result.MetricName, issue.Message)
+				if issue.Severity.AtLeast(verdict.FailOn) {
+					failing = append(failing, finding)
+				} else {
+					warnings = append(warnings, finding)
+				}
+			}
+		}
+	}
+
+	if len(failing) > 0 && wh.deny && m.Metadata.Annotations[dryRunAnnotation] != "true" {
+		resp.Allowed = false
+		resp.Status = &admissionStatus{
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("metriclint: %d failing issues, e.g. %s", len(failing), strings.TrimPrefix(failing[0], "metriclint: ")),
+		}
+	}
+	resp.Warnings = limitWarnings(append(failing, warnings...))
+
+	return resp
+}
+
+// targets returns the URLs of the targets annotation, or those resolved from the monitor. The annotation
+// may only list targets of the monitor, or URLs under an allowed prefix.
+func (wh *webhook) targets(m *monitor) ([]string, error) {
+	annotation := m.Metadata.Annotations[targetsAnnotation]
+	var resolved []string
+	if wh.resolve != nil {
+		var err error
+		// The annotation may list allowed URLs before the monitor has targets.
+		if resolved, err = wh.resolve(m); err != nil && annotation == "" {
+			return nil, err
+		}
+	}
+	if annotation == "" {
+		return resolved, nil
+	}
+	var targets []string
+	for _, target := range strings.Split(annotation, ",") {
+		if target = strings.TrimSpace(target); target == "" {
+			continue
+		}
+		if !contains(resolved, target) && !wh.allowedTarget(target) {
+			return nil, fmt.Errorf("%s %s is neither a target of the %s nor under --allowed-targets", targetsAnnotation, target, m.Kind)
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// allowedTarget reports whether target has the scheme and host of an allowed URL, and a path under its path.
+func (wh *webhook) allowedTarget(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || u.User != nil {
+		return false
+	}
+	for _, prefix := range wh.allowed {
+		if u.Scheme == prefix.Scheme && u.Host == prefix.Host && strings.HasPrefix(u.Path, prefix.Path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseAllowedTargets parses the comma separated URL prefixes of --allowed-targets.
+func parseAllowedTargets(value string) ([]*url.URL, error) {
+	var allowed []*url.URL
+	for _, prefix := range strings.Split(value, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix == "" {
+			continue
+		}
+		u, err := url.Parse(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("allowed target %q: expected an http(s) URL prefix", prefix)
+		}
+		allowed = append(allowed, u)
+	}
+
+	return allowed, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+type scrapeResult struct {
+	results []*metriclint.LintResult
+	err     error
+}
+
+// scrape lints the targets concurrently, within the timeout of the webhook, and returns their results in
+// the order of the targets.
+func (wh *webhook) scrape(targets []string) []scrapeResult {
+	ctx, cancel := context.WithTimeout(context.Background(), wh.timeout)
+	defer cancel()
+
+	scrapes := make([]scrapeResult, len(targets))
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			scrapes[i].results, scrapes[i].err = wh.lint(ctx, targets[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return scrapes
+}
+
+// lint scrapes the target in text format and lints the exposition.
+func (wh *webhook) lint(ctx context.Context, target string) ([]*metriclint.LintResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain;version=0.0.4")
+	resp, err := wh.scraper.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body := &io.LimitedReader{R: resp.Body, N: maxScrapeBytes + 1}
+	results, err := wh.linter.LintExposition(body)
+	if body.N == 0 {
+		return nil, fmt.Errorf("exposition larger than %d bytes", maxScrapeBytes)
+	}
+
+	return results, err
+}
+
+// limitWarnings keeps the first maxWarnings warnings and counts the others.
+func limitWarnings(warnings []string) []string {
+	if len(warnings) <= maxWarnings {
+		return warnings
+	}
+
+	return append(warnings[:maxWarnings:maxWarnings], fmt.Sprintf("metriclint: %d more issues", len(warnings)-maxWarnings))
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+)
+
+func TestWebhookReview(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/good":
+			fmt.Fprint(w, "# HELP jobs_total Number of jobs.\n# TYPE jobs_total counter\njobs_total 1\n")
+		case "/warning":
+			fmt.Fprint(w, "# HELP job_success_percent Share of successful jobs.\n# TYPE job_success_percent gauge\njob_success_percent 90\n")
+		case "/error":
+			fmt.Fprint(w, "# HELP jobDuration Duration of jobs.\n# TYPE jobDuration gauge\njobDuration 1\n")
+		case "/redirect":
+			http.Redirect(w, r, "/error", http.StatusFound)
+		case "/large":
+			comment := strings.Repeat("#", 1023) + "\n"
+			for i := 0; i <= maxScrapeBytes/len(comment); i++ {
+				fmt.Fprint(w, comment)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer target.Close()
+
+	var tests = []struct {
+		name        string
+		kind        string
+		annotations map[string]string
+		deny        bool
+		failOn      metriclint.Severity
+		resolved    []string
+		unlisted    bool
+		allowed     bool
+		warnings    int
+	}{
+		{
+			name:        "clean target",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/good"},
+			deny:        true,
+			allowed:     true,
+		},
+		{
+			name:        "warnings",
+			kind:        podMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/warning"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "denied",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/good, " + target.URL + "/error"},
+			deny:        true,
+			allowed:     false,
+			warnings:    1,
+		},
+		{
+			name:        "fail on warnings",
+			kind:        podMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/warning"},
+			deny:        true,
+			failOn:      metriclint.SeverityWarning,
+			allowed:     false,
+			warnings:    1,
+		},
+		{
+			name:        "errors as warnings",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/error"},
+			deny:        false,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "dry run",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/error", dryRunAnnotation: "true"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "unreachable target",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/missing"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "unlisted target",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: "http://169.254.169.254/latest/meta-data"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "target of the monitor",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/error"},
+			deny:        true,
+			resolved:    []string{target.URL + "/error"},
+			unlisted:    true,
+			allowed:     false,
+			warnings:    1,
+		},
+		{
+			name:     "resolved targets",
+			kind:     podMonitorKind,
+			deny:     true,
+			resolved: []string{target.URL + "/good", target.URL + "/warning"},
+			unlisted: true,
+			allowed:  true,
+			warnings: 1,
+		},
+		{
+			name:        "redirect",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/redirect"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:        "large exposition",
+			kind:        serviceMonitorKind,
+			annotations: map[string]string{targetsAnnotation: target.URL + "/large"},
+			deny:        true,
+			allowed:     true,
+			warnings:    1,
+		},
+		{
+			name:     "no target",
+			kind:     podMonitorKind,
+			deny:     true,
+			allowed:  true,
+			warnings: 1,
+		},
+		{
+			name:        "other kind",
+			kind:        "PrometheusRule",
+			annotations: map[string]string{targetsAnnotation: target.URL + "/error"},
+			deny:        true,
+			allowed:     true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			object, _ := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "jobs", "annotations": tc.annotations},
+			})
+			review := admissionReview{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview", Request: &admissionRequest{UID: "42", Object: object}}
+			review.Request.Kind.Kind = tc.kind
+			body, _ := json.Marshal(review)
+
+			wh := &webhook{linter: promadapter.NewLinter(metriclint.NewLinter(metriclint.FailOn(tc.failOn))), scraper: newScraper(), deny: tc.deny, maxTargets: 3, timeout: time.Second}
+			if !tc.unlisted {
+				wh.allowed, _ = parseAllowedTargets(target.URL + "/")
+			}
+			if tc.resolved != nil {
+				wh.resolve = func(*monitor) ([]string, error) { return tc.resolved, nil }
+			}
+			recorder := httptest.NewRecorder()
+			wh.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body)))
+
+			var got admissionReview
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Response == nil || got.Response.UID != "42" || got.APIVersion != review.APIVersion {
+				t.Fatalf("expected a response to the review, but got: %+v", got)
+			}
+			if got.Response.Allowed != tc.allowed {
+				t.Errorf("expected: %t, but got: %t", tc.allowed, got.Response.Allowed)
+			}
+			if !tc.allowed && (got.Response.Status == nil || got.Response.Status.Code != http.StatusForbidden) {
+				t.Errorf("expected: a forbidden status, but got: %+v", got.Response.Status)
+			}
+			if len(got.Response.Warnings) != tc.warnings {
+				t.Errorf("expected: %d, but got: %v", tc.warnings, got.Response.Warnings)
+			}
+			for _, warning := range got.Response.Warnings {
+				if !strings.HasPrefix(warning, "metriclint: ") {
+					t.Errorf("expected: a metriclint warning, but got: %s", warning)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookBadRequest(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	(&webhook{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader("{}")))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected: %d, but got: %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestLimitWarnings(t *testing.T) {
+	warnings := make([]string, maxWarnings+5)
+	limited := limitWarnings(warnings)
+	if len(limited) != maxWarnings+1 || limited[maxWarnings] != "metriclint: 5 more issues" {
+		t.Errorf("expected: %d warnings and a count, but got: %v", maxWarnings, limited)
+	}
+}
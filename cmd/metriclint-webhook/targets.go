@@ -0,0 +1,291 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	serviceMonitorKind = "ServiceMonitor"
+	podMonitorKind     = "PodMonitor"
+
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// monitor holds the fields of ServiceMonitor and PodMonitor resources selecting their targets.
+type monitor struct {
+	Kind     string `json:"-"`
+	Metadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Selector          labelSelector `json:"selector"`
+		NamespaceSelector struct {
+			Any        bool     `json:"any"`
+			MatchNames []string `json:"matchNames"`
+		} `json:"namespaceSelector"`
+
+		// Endpoints of a ServiceMonitor, PodMetricsEndpoints of a PodMonitor.
+		Endpoints           []monitorEndpoint `json:"endpoints"`
+		PodMetricsEndpoints []monitorEndpoint `json:"podMetricsEndpoints"`
+	} `json:"spec"`
+}
+
+type monitorEndpoint struct {
+	Port   string `json:"port"`
+	Path   string `json:"path"`
+	Scheme string `json:"scheme"`
+}
+
+// url returns the URL of the endpoint on the host and port. The scheme and the path come from the
+// monitor, they are checked so that they can't point the URL at another host, e.g. with a path
+// starting with "@".
+func (e monitorEndpoint) url(host string, port int) (string, error) {
+	scheme, path := e.Scheme, e.Path
+	if scheme == "" {
+		scheme = "http"
+	}
+	if path == "" {
+		path = "/metrics"
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("endpoint scheme %q is not http or https", e.Scheme)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("endpoint path %q does not start with /", e.Path)
+	}
+
+	u := url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(port)), Path: path}
+	return u.String(), nil
+}
+
+type labelSelector struct {
+	MatchLabels      map[string]string `json:"matchLabels"`
+	MatchExpressions []struct {
+		Key      string   `json:"key"`
+		Operator string   `json:"operator"`
+		Values   []string `json:"values"`
+	} `json:"matchExpressions"`
+}
+
+// String returns the selector in the syntax of the labelSelector parameter of the API.
+func (s labelSelector) String() string {
+	keys := make([]string, 0, len(s.MatchLabels))
+	for key := range s.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var requirements []string
+	for _, key := range keys {
+		requirements = append(requirements, key+"="+s.MatchLabels[key])
+	}
+	for _, e := range s.MatchExpressions {
+		switch e.Operator {
+		case "In":
+			requirements = append(requirements, fmt.Sprintf("%s in (%s)", e.Key, strings.Join(e.Values, ",")))
+		case "NotIn":
+			requirements = append(requirements, fmt.Sprintf("%s notin (%s)", e.Key, strings.Join(e.Values, ",")))
+		case "Exists":
+			requirements = append(requirements, e.Key)
+		case "DoesNotExist":
+			requirements = append(requirements, "!"+e.Key)
+		}
+	}
+
+	return strings.Join(requirements, ",")
+}
+
+// kubeClient lists the endpoints and pods selected by monitors from the Kubernetes API.
+type kubeClient struct {
+	host   string
+	token  string
+	client *http.Client
+}
+
+// inClusterClient returns a client authenticated with the service account of the pod.
+func inClusterClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes cluster")
+	}
+	token, err := ioutil.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, err
+	}
+	ca, err := ioutil.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid service account CA certificate")
+	}
+
+	return &kubeClient{
+		host:  "https://" + net.JoinHostPort(host, port),
+		token: strings.TrimSpace(string(token)),
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}, nil
+}
+
+// list decodes the items of a list of resources of the namespace matching the selector into v.
+func (k *kubeClient) list(namespace, resource string, selector labelSelector, v interface{}) error {
+	u := fmt.Sprintf("%s/api/v1/namespaces/%s/%s?labelSelector=%s", k.host, url.PathEscape(namespace), resource, url.QueryEscape(selector.String()))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if k.token != "" {
+		req.Header.Set("Authorization", "Bearer "+k.token)
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("listing %s in %s: unexpected status %s", resource, namespace, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// targets returns the URLs of the ready endpoints, for a ServiceMonitor, or of the running pods, for a
+// PodMonitor, selected by the monitor. Endpoints are matched by port name. Only the namespace of the
+// monitor is listed, so that a monitor can't read the findings of the targets of another namespace.
+func (k *kubeClient) targets(m *monitor) ([]string, error) {
+	if m.Spec.NamespaceSelector.Any {
+		return nil, errors.New("namespaceSelector.any is not supported, only the namespace of the monitor is linted")
+	}
+	for _, namespace := range m.Spec.NamespaceSelector.MatchNames {
+		if namespace != m.Metadata.Namespace {
+			return nil, fmt.Errorf("namespaceSelector.matchNames: namespace %s is not the namespace of the monitor, only the namespace of the monitor is linted", namespace)
+		}
+	}
+
+	if m.Kind == podMonitorKind {
+		return k.podTargets(m.Metadata.Namespace, m)
+	}
+
+	return k.endpointTargets(m.Metadata.Namespace, m)
+}
+
+func (k *kubeClient) endpointTargets(namespace string, m *monitor) ([]string, error) {
+	var list struct {
+		Items []struct {
+			Subsets []struct {
+				Addresses []struct {
+					IP string `json:"ip"`
+				} `json:"addresses"`
+				Ports []struct {
+					Name string `json:"name"`
+					Port int    `json:"port"`
+				} `json:"ports"`
+			} `json:"subsets"`
+		} `json:"items"`
+	}
+	if err := k.list(namespace, "endpoints", m.Spec.Selector, &list); err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, item := range list.Items {
+		for _, subset := range item.Subsets {
+			for _, endpoint := range m.Spec.Endpoints {
+				for _, port := range subset.Ports {
+					if port.Name != endpoint.Port {
+						continue
+					}
+					for _, address := range subset.Addresses {
+						target, err := endpoint.url(address.IP, port.Port)
+						if err != nil {
+							return nil, err
+						}
+						targets = append(targets, target)
+					}
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (k *kubeClient) podTargets(namespace string, m *monitor) ([]string, error) {
+	var list struct {
+		Items []struct {
+			Spec struct {
+				Containers []struct {
+					Ports []struct {
+						Name          string `json:"name"`
+						ContainerPort int    `json:"containerPort"`
+					} `json:"ports"`
+				} `json:"containers"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+				PodIP string `json:"podIP"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := k.list(namespace, "pods", m.Spec.Selector, &list); err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, pod := range list.Items {
+		if pod.Status.Phase != "Running" || pod.Status.PodIP == "" {
+			continue
+		}
+		for _, endpoint := range m.Spec.PodMetricsEndpoints {
+			for _, container := range pod.Spec.Containers {
+				for _, port := range container.Ports {
+					if port.Name != endpoint.Port {
+						continue
+					}
+					target, err := endpoint.url(pod.Status.PodIP, port.ContainerPort)
+					if err != nil {
+						return nil, err
+					}
+					targets = append(targets, target)
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLabelSelector(t *testing.T) {
+	var selector labelSelector
+	err := json.Unmarshal([]byte(`{
+		"matchLabels": {"team": "jobs", "app": "backup"},
+		"matchExpressions": [
+			{"key": "tier", "operator": "In", "values": ["batch", "cron"]},
+			{"key": "canary", "operator": "DoesNotExist"}
+		]
+	}`), &selector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "app=backup,team=jobs,tier in (batch,cron),!canary"
+	if got := selector.String(); got != expected {
+		t.Errorf("expected: %s, but got: %s", expected, got)
+	}
+}
+
+func TestKubeClientTargets(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if selector := r.URL.Query().Get("labelSelector"); selector != "app=backup" {
+			t.Errorf("expected: app=backup, but got: %s", selector)
+		}
+		switch r.URL.Path {
+		case "/api/v1/namespaces/jobs/endpoints":
+			fmt.Fprint(w, `{"items":[{"subsets":[{"addresses":[{"ip":"10.0.0.1"},{"ip":"10.0.0.2"}],"ports":[{"name":"web","port":8080},{"name":"metrics","port":9090}]}]}]}`)
+		case "/api/v1/namespaces/jobs/pods":
+			fmt.Fprint(w, `{"items":[
+				{"spec":{"containers":[{"ports":[{"name":"metrics","containerPort":9100}]}]},"status":{"phase":"Running","podIP":"10.0.1.1"}},
+				{"spec":{"containers":[{"ports":[{"name":"metrics","containerPort":9100}]}]},"status":{"phase":"Pending"}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+	kube := &kubeClient{host: api.URL, client: api.Client()}
+
+	var tests = []struct {
+		name     string
+		monitor  string
+		kind     string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "service monitor",
+			monitor:  `{"metadata":{"namespace":"jobs"},"spec":{"selector":{"matchLabels":{"app":"backup"}},"endpoints":[{"port":"metrics","path":"/stats"}]}}`,
+			kind:     serviceMonitorKind,
+			expected: []string{"http://10.0.0.1:9090/stats", "http://10.0.0.2:9090/stats"},
+		},
+		{
+			name:     "pod monitor",
+			monitor:  `{"metadata":{"namespace":"jobs"},"spec":{"selector":{"matchLabels":{"app":"backup"}},"namespaceSelector":{"matchNames":["jobs"]},"podMetricsEndpoints":[{"port":"metrics","scheme":"https"}]}}`,
+			kind:     podMonitorKind,
+			expected: []string{"https://10.0.1.1:9100/metrics"},
+		},
+		{
+			name:    "other namespace",
+			monitor: `{"metadata":{"namespace":"other"},"spec":{"selector":{"matchLabels":{"app":"backup"}},"namespaceSelector":{"matchNames":["jobs"]},"podMetricsEndpoints":[{"port":"metrics"}]}}`,
+			kind:    podMonitorKind,
+			err:     true,
+		},
+		{
+			name:    "path pointing at another host",
+			monitor: `{"metadata":{"namespace":"jobs"},"spec":{"selector":{"matchLabels":{"app":"backup"}},"endpoints":[{"port":"metrics","path":"@169.254.169.254/latest/meta-data"}]}}`,
+			kind:    serviceMonitorKind,
+			err:     true,
+		},
+		{
+			name:    "unsupported scheme",
+			monitor: `{"metadata":{"namespace":"jobs"},"spec":{"selector":{"matchLabels":{"app":"backup"}},"podMetricsEndpoints":[{"port":"metrics","scheme":"file"}]}}`,
+			kind:    podMonitorKind,
+			err:     true,
+		},
+		{
+			name:    "any namespace",
+			monitor: `{"metadata":{"namespace":"jobs"},"spec":{"namespaceSelector":{"any":true}}}`,
+			kind:    serviceMonitorKind,
+			err:     true,
+		},
+		{
+			name:    "forbidden namespace",
+			monitor: `{"metadata":{"namespace":"kube-system"},"spec":{"selector":{"matchLabels":{"app":"backup"}}}}`,
+			kind:    podMonitorKind,
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			m := &monitor{}
+			if err := json.Unmarshal([]byte(tc.monitor), m); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			m.Kind = tc.kind
+
+			targets, err := kube.targets(m)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(targets, tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, targets)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// The completion command is registered in init since it completes the commands map itself.
+func init() {
+	commands["completion"] = runCompletion
+}
+
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: metriclint completion %s\n", strings.Join(completionShells, "|"))
+		return 2
+	}
+
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "completion: %v\n", err)
+		return 2
+	}
+
+	return 0
+}
+
+// completionWords returns the words completed after each top level word.
+func completionWords() map[string][]string {
+	words := map[string][]string{
+		"completion": completionShells,
+	}
+	for _, rule := range metriclint.Rules() {
+		words["explain"] = append(words["explain"], rule.ID)
+	}
+
+	flagSets := map[string]func() *flag.FlagSet{
+		listRulesFlag: func() *flag.FlagSet {
+			fs, _ := listRulesFlagSet()
+			return fs
+		},
+	}
+	for name, flagSet := range commandFlagSets {
+		flagSets[name] = flagSet
+	}
+	for name, flagSet := range flagSets {
+		flagSet().VisitAll(func(f *flag.Flag) {
+			words[name] = append(words[name], "--"+f.Name)
+		})
+	}
+
+	for name := range commands {
+		if _, ok := words[name]; !ok {
+			words[name] = nil
+		}
+	}
+
+	return words
+}
+
+// writeCompletion writes the completion script of the shell.
+func writeCompletion(w io.Writer, shell string) error {
+	words := completionWords()
+	var top []string
+	for name := range words {
+		top = append(top, name)
+	}
+	sort.Strings(top)
+
+	var b strings.Builder
+	switch shell {
+	case "bash", "zsh":
+		if shell == "zsh" {
+			b.WriteString("autoload -U +X bashcompinit && bashcompinit\n")
+		}
+		b.WriteString("_metriclint() {\n")
+		b.WriteString("    local cur=${COMP_WORDS[COMP_CWORD]}\n")
+		fmt.Fprintf(&b, "    if [ \"$COMP_CWORD\" -eq 1 ]; then\n        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n        return\n    fi\n", strings.Join(top, " "))
+		b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+		for _, name := range top {
+			fmt.Fprintf(&b, "    %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", name, strings.Join(words[name], " "))
+		}
+		b.WriteString("    esac\n}\ncomplete -F _metriclint metriclint\n")
+	case "fish":
+		for _, name := range top {
+			if strings.HasPrefix(name, "--") {
+				fmt.Fprintf(&b, "complete -c metriclint -n __fish_use_subcommand -l %s\n", strings.TrimPrefix(name, "--"))
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c metriclint -f -n __fish_use_subcommand -a %s\n", name)
+		}
+		for _, name := range top {
+			for _, word := range words[name] {
+				condition := fmt.Sprintf("'__fish_seen_subcommand_from %s'", name)
+				if strings.HasPrefix(name, "--") {
+					condition = fmt.Sprintf("'__fish_contains_opt %s'", strings.TrimPrefix(name, "--"))
+				}
+				if strings.HasPrefix(word, "--") {
+					fmt.Fprintf(&b, "complete -c metriclint -n %s -l %s\n", condition, strings.TrimPrefix(word, "--"))
+				} else {
+					fmt.Fprintf(&b, "complete -c metriclint -f -n %s -a %s\n", condition, word)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported shell %q, expected one of %s", shell, strings.Join(completionShells, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
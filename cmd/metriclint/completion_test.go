@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	var tests = []struct {
+		shell    string
+		contains []string
+		err      bool
+	}{
+		{
+			shell:    "bash",
+			contains: []string{"complete -F _metriclint metriclint", "triage) COMPREPLY=($(compgen -W \"--baseline --report\"", "--list-rules) COMPREPLY=($(compgen -W \"--format\""},
+		},
+		{
+			shell:    "zsh",
+			contains: []string{"bashcompinit", "completion) COMPREPLY=($(compgen -W \"bash zsh fish\""},
+		},
+		{
+			shell:    "fish",
+			contains: []string{"-n __fish_use_subcommand -a triage", "-n '__fish_seen_subcommand_from triage' -l report", "-n __fish_use_subcommand -l list-rules"},
+		},
+		{
+			shell: "powershell",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.shell, func(t *testing.T) {
+			var b bytes.Buffer
+			err := writeCompletion(&b, tc.shell)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("expected script to contain: %s, but got:\n%s", s, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestListRules(t *testing.T) {
+	var b bytes.Buffer
+	if err := listRules(&b, "json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rules []metriclint.RuleInfo
+	if err := json.Unmarshal(b.Bytes(), &rules); err != nil {
+		t.Fatalf("failed to decode rules: %v", err)
+	}
+	if len(rules) != len(metriclint.Rules()) || rules[0] != metriclint.Rules()[0] {
+		t.Errorf("expected: %v, but got: %v", metriclint.Rules(), rules)
+	}
+
+	b.Reset()
+	if err := listRules(&b, "text"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "help-missing") {
+		t.Errorf("unexpected text output:\n%s", b.String())
+	}
+
+	if err := listRules(&b, "yaml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func runExplain(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: metriclint explain <rule-id>")
+		return 2
+	}
+
+	linter, err := metriclint.NewLinterFromConfig(&metriclint.Config{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "explain: %v\n", err)
+		return 1
+	}
+
+	text, err := linter.Explain(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "explain: %v, see metriclint --list-rules\n", err)
+		return 2
+	}
+	fmt.Print(text)
+
+	return 0
+}
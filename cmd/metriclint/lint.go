@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+	"github.com/promlint/promlint/pkg/metriclint/source"
+	"github.com/promlint/promlint/pkg/report"
+)
+
+// Exit codes of the lint command.
+const (
+	lintExitClean   = 0
+	lintExitIssues  = 1
+	lintExitFailure = 2
+)
+
+const openMetricsMedia = "application/openmetrics-text"
+
+// lintAccept prefers OpenMetrics, textAccept only accepts the text format.
+const (
+	lintAccept = openMetricsMedia + ";version=1.0.0,text/plain;version=0.0.4;q=0.5"
+	textAccept = "text/plain;version=0.0.4"
+)
+
+type lintFlags struct {
+	configPath string
+	baseline   string
+	profile    string
+	enable     string
+	disable    string
+	failOn     string
+	sortIssues bool
+	suggest    bool
+	format     string
+	timeout    time.Duration
+}
+
+func lintFlagSet() (fs *flag.FlagSet, flags *lintFlags) {
+	flags = &lintFlags{}
+	fs = flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.StringVar(&flags.configPath, "config", "", "path of the config, the default rules if empty")
+	fs.StringVar(&flags.baseline, "baseline", "", "path of the baseline written by triage, whose suppressed issues are not reported, the one of the config if empty")
+	fs.StringVar(&flags.profile, "profile", "", fmt.Sprintf("profile replacing the one of the config, one of %v", metriclint.Profiles()))
+	fs.StringVar(&flags.enable, "enable", "", "comma separated IDs of rules to enable in addition to the config")
+	fs.StringVar(&flags.disable, "disable", "", "comma separated IDs of rules to disable in addition to the config")
+	fs.StringVar(&flags.failOn, "fail-on", "", "severity from which issues fail the run, error or warning, the one of the config if empty")
+	fs.BoolVar(&flags.sortIssues, "sort-issues", false, "sort the issues of each metric by rule ID, for reproducible diffs")
+	fs.BoolVar(&flags.suggest, "suggest", false, "suggest a compliant name for the metrics with issues, written to stderr unless the format is text")
+	fs.StringVar(&flags.format, "format", "text", fmt.Sprintf("output format, one of %v", report.Formats()))
+	fs.DurationVar(&flags.timeout, "timeout", 10*time.Second, "timeout of scraping a URL")
+
+	return fs, flags
+}
+
+// runLint lints the exposition of a scrape endpoint or of a file, "-" for stdin. It exits with 0 if
+// no issue is at or above the fail-on severity, 1 otherwise, and 2 if the target can't be linted.
+func runLint(args []string) int {
+	fs, flags := lintFlagSet()
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: metriclint lint [flags] <url|file|file.go|->")
+		return lintExitFailure
+	}
+
+	linter, err := lintLinter(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+		return lintExitFailure
+	}
+
+	rep, specs, err := lintTarget(fs.Arg(0), linter, flags.timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+		return lintExitFailure
+	}
+
+	// Judge the report first, so that it renders the verdict.
+	code := lintExitCode(linter, rep)
+	if err := report.Write(os.Stdout, flags.format, rep); err != nil {
+		fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+		return lintExitFailure
+	}
+	if flags.suggest {
+		// Keep the machine readable formats parseable.
+		out := os.Stderr
+		if flags.format == "text" {
+			out = os.Stdout
+		}
+		writeSuggestions(out, rep, specs)
+	}
+
+	return code
+}
+
+// lintLinter returns the linter of the config, with the baseline, the profile, the fail-on severity and the issue order of
+// the flags and their rules enabled or disabled.
+func lintLinter(flags *lintFlags) (*metriclint.Linter, error) {
+	config := &metriclint.Config{}
+	if flags.configPath != "" {
+		var err error
+		if config, err = metriclint.LoadConfig(flags.configPath); err != nil {
+			return nil, err
+		}
+	}
+	if flags.baseline != "" {
+		config.Baseline = flags.baseline
+	}
+	if flags.profile != "" {
+		config.Profile = flags.profile
+	}
+	if flags.failOn != "" {
+		config.FailOn = metriclint.Severity(flags.failOn)
+	}
+	if flags.sortIssues {
+		config.SortIssues = true
+	}
+	config.Enable = append(config.Enable, splitRuleIDs(flags.enable)...)
+	config.Disable = append(config.Disable, splitRuleIDs(flags.disable)...)
+	printConfigWarnings(os.Stderr, config)
+
+	return metriclint.NewLinterFromConfig(config)
+}
+
+// printConfigWarnings writes the problems of the config which don't prevent linting, e.g. expired suppressions.
+func printConfigWarnings(w io.Writer, config *metriclint.Config) {
+	for _, warning := range config.Warnings() {
+		fmt.Fprintf(w, "warning: %v\n", warning)
+	}
+}
+
+func splitRuleIDs(s string) (ids []string) {
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// lintTarget reads the exposition of the target and lints it, returning the report and the specs of the
+// metrics. OpenMetrics payloads are recognized by their content type or by their "# EOF" line, other
+// payloads are parsed as text format. Go files are linted with the source analyzer, their issues are located
+// in the file but their specs aren't returned. The metrics tombstoned by the config are reported apart from
+// the results.
+func lintTarget(target string, linter *metriclint.Linter, timeout time.Duration) (*metriclint.Report, []metriclint.MetricSpec, error) {
+	data, contentType, err := readTarget(target, lintAccept, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []*metriclint.LintResult
+	var specs []metriclint.MetricSpec
+	if isGoFile(target) {
+		results, err = source.NewAnalyzer(linter).LintFile(target, data)
+	} else if strings.HasPrefix(contentType, openMetricsMedia) || bytes.HasSuffix(bytes.TrimRight(data, "\r\n"), []byte("# EOF")) {
+		results, err = linter.LintOpenMetrics(bytes.NewReader(data))
+		if err == nil {
+			specs, err = metriclint.OpenMetricsSpecs(bytes.NewReader(data))
+		}
+	} else {
+		results, err = promadapter.NewLinter(linter).LintExposition(bytes.NewReader(data))
+		if err == nil {
+			specs, err = promadapter.ExpositionSpecs(bytes.NewReader(data))
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", target, err)
+	}
+
+	live, tombstoned := linter.SplitTombstoned(results)
+	builder := metriclint.NewReportBuilder()
+	builder.Add(live...)
+	builder.AddTombstoned(tombstoned...)
+
+	return builder.Build(), specs, nil
+}
+
+// isGoFile reports whether the target is a Go source file rather than an exposition.
+func isGoFile(target string) bool {
+	return strings.HasSuffix(target, ".go") && !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://")
+}
+
+// writeSuggestions writes the name suggested by metriclint.SuggestName for each metric of the report with
+// issues, followed by the changes leading to it. Metrics whose name can't be improved are left out.
+func writeSuggestions(w io.Writer, rep *metriclint.Report, specs []metriclint.MetricSpec) {
+	withIssues := map[string]bool{}
+	for _, result := range rep.Results {
+		if len(result.Findings) > 0 || len(result.Issues) > 0 {
+			withIssues[result.MetricName] = true
+		}
+	}
+
+	for _, spec := range specs {
+		if !withIssues[spec.FQName()] {
+			continue
+		}
+		name, changes := metriclint.SuggestName(spec)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s: rename to %s\n", spec.FQName(), name)
+		for _, change := range changes {
+			fmt.Fprintf(w, "  %s: %s -> %s\n", change.Rule, change.From, change.To)
+		}
+	}
+}
+
+// readTarget reads a URL, with the accept header, a file or stdin, returning the content type of a URL.
+func readTarget(target, accept string, timeout time.Duration) (data []byte, contentType string, err error) {
+	switch {
+	case target == "-":
+		data, err = ioutil.ReadAll(os.Stdin)
+		return data, "", err
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		client := &http.Client{Timeout: timeout}
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set("Accept", accept)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			io.Copy(ioutil.Discard, resp.Body)
+			return nil, "", fmt.Errorf("%s: unexpected status %s", target, resp.Status)
+		}
+		data, err = ioutil.ReadAll(resp.Body)
+		return data, resp.Header.Get("Content-Type"), err
+	default:
+		data, err = ioutil.ReadFile(target)
+		return data, "", err
+	}
+}
+
+// lintExitCode judges the report and returns lintExitIssues if it has an issue at or above the fail-on severity of
+// the linter.
+func lintExitCode(linter *metriclint.Linter, rep *metriclint.Report) int {
+	if linter.JudgeReport(rep).Failed() {
+		return lintExitIssues
+	}
+
+	return lintExitClean
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestLintTarget(t *testing.T) {
+	text := "# HELP http_requests Number of requests.\n# TYPE http_requests counter\nhttp_requests 1\n"
+	openMetrics := "# TYPE http_requests counter\n# HELP http_requests Total number of requests.\nhttp_requests 1\n# EOF\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metrics":
+			w.Write([]byte(text))
+		case "/openmetrics":
+			w.Header().Set("Content-Type", openMetricsMedia+"; version=1.0.0; charset=utf-8")
+			w.Write([]byte(openMetrics))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "metriclint-lint")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "metrics.txt")
+	if err := ioutil.WriteFile(file, []byte(openMetrics), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	goFile := filepath.Join(dir, "metrics.go")
+	goSource := "package test\n\nimport \"github.com/prometheus/client_golang/prometheus\"\n\nvar c = prometheus.NewCounter(prometheus.CounterOpts{Name: \"http_requests\", Help: \"Number of requests.\"})\n"
+	if err := ioutil.WriteFile(goFile, []byte(goSource), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		target   string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "text format endpoint",
+			target:   server.URL + "/metrics",
+			expected: metriclint.RuleCounterTotalSuffix,
+		},
+		{
+			name:     "openmetrics endpoint",
+			target:   server.URL + "/openmetrics",
+			expected: metriclint.RuleOpenMetricsCounterTotal,
+		},
+		{
+			name:     "openmetrics file",
+			target:   file,
+			expected: metriclint.RuleOpenMetricsCounterTotal,
+		},
+		{
+			name:     "go file",
+			target:   goFile,
+			expected: metriclint.RuleCounterTotalSuffix,
+		},
+		{
+			name:   "missing endpoint",
+			target: server.URL + "/missing",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			rep, _, err := lintTarget(tc.target, metriclint.NewLinter(), time.Second)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.err {
+				return
+			}
+			if len(rep.Results) != 1 || len(rep.Results[0].Findings) != 1 || rep.Results[0].Findings[0].ID != tc.expected {
+				t.Fatalf("expected: %s, but got: %v", tc.expected, rep.Results)
+			}
+			if location := rep.Results[0].Findings[0].Location; (location != nil) != (tc.target == goFile) {
+				t.Errorf("unexpected location: %v", location)
+			}
+			if code := lintExitCode(metriclint.NewLinter(), rep); code != lintExitIssues {
+				t.Errorf("expected: %d, but got: %d", lintExitIssues, code)
+			}
+		})
+	}
+}
+
+func TestLintLinter(t *testing.T) {
+	linter, err := lintLinter(&lintFlags{enable: "unit-suffix, help-prefix", disable: "help-missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := linter.Lint(metriclint.MetricSpec{Name: "queue_depth", Type: metriclint.MetricTypeGauge})
+	if len(result.Findings) != 1 || result.Findings[0].ID != metriclint.RuleUnitSuffix {
+		t.Errorf("expected: %s, but got: %v", metriclint.RuleUnitSuffix, result.Findings)
+	}
+
+	if _, err := lintLinter(&lintFlags{disable: "no-such-rule"}); err == nil {
+		t.Errorf("expected an error for an unknown rule")
+	}
+}
+
+func TestLintLinterProfile(t *testing.T) {
+	linter, err := lintLinter(&lintFlags{profile: metriclint.ProfileLenient})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := linter.Lint(metriclint.MetricSpec{Name: "queue_depth", Type: metriclint.MetricTypeGauge})
+	if len(result.Findings) != 1 || result.Findings[0].Severity != metriclint.SeverityWarning {
+		t.Errorf("expected: %s warning, but got: %v", metriclint.RuleHelpMissing, result.Findings)
+	}
+
+	if _, err := lintLinter(&lintFlags{profile: "no-such-profile"}); err == nil {
+		t.Errorf("expected an error for an unknown profile")
+	}
+}
+
+func TestLintLinterFailOn(t *testing.T) {
+	linter, err := lintLinter(&lintFlags{failOn: "warning"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := linter.Lint(metriclint.MetricSpec{Name: "job_success_percent", Help: "Share of successful jobs.", Type: metriclint.MetricTypeGauge})
+	rep := &metriclint.Report{Results: []*metriclint.LintResult{result}}
+	if code := lintExitCode(linter, rep); code != lintExitIssues {
+		t.Errorf("expected: %d, but got: %d", lintExitIssues, code)
+	}
+	if code := lintExitCode(metriclint.NewLinter(), rep); code != lintExitClean {
+		t.Errorf("expected: %d, but got: %d", lintExitClean, code)
+	}
+
+	if _, err := lintLinter(&lintFlags{failOn: "fatal"}); err == nil {
+		t.Errorf("expected an error for an unknown severity")
+	}
+}
+
+func TestLintLinterSortIssues(t *testing.T) {
+	linter, err := lintLinter(&lintFlags{sortIssues: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := linter.Lint(metriclint.MetricSpec{Name: "lintRequests", Type: metriclint.MetricTypeCounter})
+
+	expected := []string{metriclint.RuleCounterTotalSuffix, metriclint.RuleHelpMissing, metriclint.RuleNameCamelCase}
+	var ids []string
+	for _, issue := range result.Findings {
+		ids = append(ids, issue.ID)
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected: %v, but got: %v", expected, ids)
+	}
+}
+
+func TestWriteSuggestions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint-lint")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "metrics.txt")
+	text := "# HELP http_requests Number of requests.\n# TYPE http_requests counter\nhttp_requests 1\n" +
+		"# HELP queue_length Length of the queue.\n# TYPE queue_length gauge\nqueue_length 1\n"
+	if err := ioutil.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	rep, specs, err := lintTarget(file, metriclint.NewLinter(), time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	writeSuggestions(&out, rep, specs)
+
+	expected := "http_requests: rename to http_requests_total\n  " + metriclint.RuleCounterTotalSuffix + ": http_requests -> http_requests_total\n"
+	if out.String() != expected {
+		t.Errorf("expected: %q, but got: %q", expected, out.String())
+	}
+}
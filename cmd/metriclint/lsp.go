@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/source"
+)
+
+func lspFlagSet() (fs *flag.FlagSet, configPath *string) {
+	fs = flag.NewFlagSet("lsp", flag.ExitOnError)
+	configPath = fs.String("config", "", "path of the config, the default rules if empty")
+
+	return fs, configPath
+}
+
+// runLSP serves Language Server Protocol diagnostics over stdin and stdout, linting with the rules of the
+// config. Every opened or changed Go document is analyzed again as a whole, other documents are left alone.
+func runLSP(args []string) int {
+	fs, configPath := lspFlagSet()
+	fs.Parse(args)
+
+	config := &metriclint.Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = metriclint.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "lsp: %v\n", err)
+			return 1
+		}
+	}
+	printConfigWarnings(os.Stderr, config)
+	linter, err := metriclint.NewLinterFromConfig(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lsp: %v\n", err)
+		return 1
+	}
+
+	if err := serveLSP(os.Stdin, os.Stdout, source.NewAnalyzer(linter)); err != nil {
+		fmt.Fprintf(os.Stderr, "lsp: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspDocumentParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Text *string `json:"text"`
+}
+
+const (
+	lspMethodNotFound    = -32601
+	lspSeverityError     = 1
+	lspSeverityWarning   = 2
+	lspTextDocumentFull  = 1
+	lspDiagnosticsMethod = "textDocument/publishDiagnostics"
+
+	// lspMaxContentLength bounds the body of the messages, so that a bogus header doesn't allocate
+	// arbitrary memory.
+	lspMaxContentLength = 64 << 20
+)
+
+// lspSeverities maps the severities of the issues to the ones of the diagnostics, issues without severity
+// are warnings.
+var lspSeverities = map[metriclint.Severity]int{
+	metriclint.SeverityError:   lspSeverityError,
+	metriclint.SeverityWarning: lspSeverityWarning,
+}
+
+// serveLSP handles LSP messages until the exit notification or the end of the input.
+func serveLSP(in io.Reader, out io.Writer, analyzer *source.Analyzer) error {
+	reader := textproto.NewReader(bufio.NewReader(in))
+	for {
+		msg, err := readLSPMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var params lspDocumentParams
+		switch msg.Method {
+		case "initialize":
+			err = writeLSPMessage(out, &lspMessage{ID: msg.ID, Result: map[string]interface{}{
+				"capabilities": map[string]interface{}{
+					"textDocumentSync": map[string]interface{}{
+						"openClose": true,
+						"change":    lspTextDocumentFull,
+						"save":      map[string]bool{"includeText": true},
+					},
+				},
+				"serverInfo": map[string]string{"name": "metriclint"},
+			}})
+		case "shutdown":
+			err = writeLSPMessage(out, &lspMessage{ID: msg.ID, Result: json.RawMessage("null")})
+		case "exit":
+			return nil
+		case "textDocument/didOpen", "textDocument/didChange", "textDocument/didSave", "textDocument/didClose":
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				return fmt.Errorf("failed to decode %s params: %v", msg.Method, err)
+			}
+			text, analyze := params.TextDocument.Text, true
+			switch msg.Method {
+			case "textDocument/didChange":
+				if len(params.ContentChanges) == 0 {
+					continue
+				}
+				text = params.ContentChanges[len(params.ContentChanges)-1].Text
+			case "textDocument/didSave":
+				if params.Text == nil {
+					continue
+				}
+				text = *params.Text
+			case "textDocument/didClose":
+				analyze = false
+			}
+			err = publishDiagnostics(out, analyzer, params.TextDocument.URI, text, analyze)
+		default:
+			if msg.ID != nil {
+				err = writeLSPMessage(out, &lspMessage{ID: msg.ID, Error: &lspError{Code: lspMethodNotFound, Message: "method not found: " + msg.Method}})
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// publishDiagnostics analyzes the document and publishes its diagnostics. Closed documents, documents
+// which aren't Go files and documents which don't parse get their diagnostics cleared.
+func publishDiagnostics(out io.Writer, analyzer *source.Analyzer, uri, text string, analyze bool) error {
+	diagnostics := []lspDiagnostic{}
+	if path, ok := lspDocumentPath(uri); analyze && ok && strings.HasSuffix(path, ".go") {
+		found, err := analyzer.AnalyzeFile(path, []byte(text))
+		if err != nil {
+			found = nil
+		}
+		lines := strings.Split(text, "\n")
+		for _, d := range found {
+			severity, ok := lspSeverities[d.Severity]
+			if !ok {
+				severity = lspSeverityWarning
+			}
+			diagnostics = append(diagnostics, lspDiagnostic{
+				Range: lspRange{
+					Start: lspPositionOf(lines, d.Pos.Line, d.Pos.Column),
+					End:   lspPositionOf(lines, d.End.Line, d.End.Column),
+				},
+				Severity: severity,
+				Source:   "metriclint",
+				Message:  fmt.Sprintf("%s: %s", d.Metric, d.Message),
+			})
+		}
+	}
+
+	params, err := json.Marshal(map[string]interface{}{"uri": uri, "diagnostics": diagnostics})
+	if err != nil {
+		return err
+	}
+
+	return writeLSPMessage(out, &lspMessage{Method: lspDiagnosticsMethod, Params: params})
+}
+
+// lspPositionOf converts a 1-based line and byte column to a 0-based line and UTF-16 character.
+// lspDocumentPath returns the path of the file a document URI refers to, decoding its escapes, ok is
+// false if the URI isn't a file URI.
+func lspDocumentPath(uri string) (path string, ok bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+
+	return u.Path, true
+}
+
+func lspPositionOf(lines []string, line, column int) lspPosition {
+	if line < 1 || line > len(lines) {
+		return lspPosition{}
+	}
+	text := lines[line-1]
+	if column-1 < len(text) {
+		text = text[:column-1]
+	}
+
+	return lspPosition{Line: line - 1, Character: len(utf16.Encode([]rune(text)))}
+}
+
+func readLSPMessage(reader *textproto.Reader) (*lspMessage, error) {
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %v", err)
+	}
+	if length < 0 || length > lspMaxContentLength {
+		return nil, fmt.Errorf("invalid Content-Length: %d is not between 0 and %d", length, lspMaxContentLength)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader.R, body); err != nil {
+		return nil, err
+	}
+
+	msg := &lspMessage{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %v", err)
+	}
+
+	return msg, nil
+}
+
+func writeLSPMessage(out io.Writer, msg *lspMessage) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/source"
+)
+
+func lspRequest(b *bytes.Buffer, id int, method string, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	body, _ := json.Marshal(msg)
+	fmt.Fprintf(b, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServeLSP(t *testing.T) {
+	src := "package test\n\nimport \"github.com/prometheus/client_golang/prometheus\"\n\nvar c = prometheus.NewCounter(prometheus.CounterOpts{Name: \"lint_requests\", Help: \"this is help message\"})\n"
+	uri := "file:///tmp/test.go"
+
+	var in bytes.Buffer
+	lspRequest(&in, 1, "initialize", map[string]interface{}{})
+	lspRequest(&in, 0, "initialized", map[string]interface{}{})
+	lspRequest(&in, 0, "textDocument/didOpen", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri, "text": src}})
+	lspRequest(&in, 0, "textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri},
+		"contentChanges": []map[string]string{{"text": strings.Replace(src, "lint_requests", "lint_requests_total", 1)}},
+	})
+	lspRequest(&in, 2, "textDocument/hover", map[string]interface{}{})
+	lspRequest(&in, 3, "shutdown", nil)
+	lspRequest(&in, 0, "exit", nil)
+
+	var out bytes.Buffer
+	if err := serveLSP(&in, &out, source.NewAnalyzer(metriclint.NewLinter())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(&out))
+	var messages []*lspMessage
+	for {
+		msg, err := readLSPMessage(reader)
+		if err != nil {
+			break
+		}
+		messages = append(messages, msg)
+	}
+	if len(messages) != 5 {
+		t.Fatalf("expected 5 messages, but got: %d", len(messages))
+	}
+
+	var published struct {
+		URI         string          `json:"uri"`
+		Diagnostics []lspDiagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(messages[1].Params, &published); err != nil {
+		t.Fatalf("failed to decode diagnostics: %v", err)
+	}
+	expected := lspDiagnostic{
+		Range:    lspRange{Start: lspPosition{Line: 4, Character: 59}, End: lspPosition{Line: 4, Character: 74}},
+		Severity: lspSeverityError,
+		Source:   "metriclint",
+		Message:  "lint_requests: " + metriclint.LintErrMsgCounterShouldHaveTotalSuffix,
+	}
+	if published.URI != uri || len(published.Diagnostics) != 1 || published.Diagnostics[0] != expected {
+		t.Errorf("expected: %v, but got: %v", expected, published)
+	}
+
+	// The fixed document clears the diagnostics.
+	if err := json.Unmarshal(messages[2].Params, &published); err != nil {
+		t.Fatalf("failed to decode diagnostics: %v", err)
+	}
+	if len(published.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostic, but got: %v", published.Diagnostics)
+	}
+
+	if messages[3].Error == nil || messages[3].Error.Code != lspMethodNotFound {
+		t.Errorf("expected method not found error, but got: %v", messages[3])
+	}
+}
+
+func TestPublishDiagnosticsSeverity(t *testing.T) {
+	src := "package test\n\nimport \"github.com/prometheus/client_golang/prometheus\"\n\nvar c = prometheus.NewCounter(prometheus.CounterOpts{Name: \"lint_requests\", Help: \"this is help message\"})\n"
+	linter := metriclint.NewLinter(metriclint.WithSeverities(map[string]metriclint.Severity{metriclint.RuleCounterTotalSuffix: metriclint.SeverityWarning}))
+
+	var out bytes.Buffer
+	if err := publishDiagnostics(&out, source.NewAnalyzer(linter), "file:///tmp/test.go", src, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := readLSPMessage(textproto.NewReader(bufio.NewReader(&out)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var published struct {
+		Diagnostics []lspDiagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(msg.Params, &published); err != nil {
+		t.Fatalf("failed to decode diagnostics: %v", err)
+	}
+	if len(published.Diagnostics) != 1 || published.Diagnostics[0].Severity != lspSeverityWarning {
+		t.Errorf("expected a single diagnostic with severity %d, but got: %v", lspSeverityWarning, published.Diagnostics)
+	}
+}
+
+func TestLSPPositionOf(t *testing.T) {
+	lines := []string{"var x = \"héllo\" // ä"}
+	if p := lspPositionOf(lines, 1, strings.Index(lines[0], "//")+1); p.Character != 16 {
+		t.Errorf("expected: %d, but got: %d", 16, p.Character)
+	}
+	if p := lspPositionOf(lines, 3, 1); p != (lspPosition{}) {
+		t.Errorf("expected: %v, but got: %v", lspPosition{}, p)
+	}
+}
+
+func TestReadLSPMessageContentLength(t *testing.T) {
+	var tests = []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "missing", header: "", expected: `invalid Content-Length: strconv.Atoi: parsing "": invalid syntax`},
+		{name: "negative", header: "Content-Length: -1\r\n", expected: "invalid Content-Length: -1 is not between 0 and 67108864"},
+		{name: "too large", header: "Content-Length: 1099511627776\r\n", expected: "invalid Content-Length: 1099511627776 is not between 0 and 67108864"},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			reader := textproto.NewReader(bufio.NewReader(strings.NewReader(tc.header + "Content-Type: application/json\r\n\r\n{}")))
+			_, err := readLSPMessage(reader)
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("expected: %s, but got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestLSPDocumentPath(t *testing.T) {
+	var tests = []struct {
+		uri  string
+		path string
+		ok   bool
+	}{
+		{uri: "file:///tmp/test.go", path: "/tmp/test.go", ok: true},
+		{uri: "file:///tmp/my%20module/test.go", path: "/tmp/my module/test.go", ok: true},
+		{uri: "file://localhost/tmp/test.go", path: "/tmp/test.go", ok: true},
+		{uri: "untitled:Untitled-1", path: "", ok: false},
+		{uri: "file:///tmp/%zz.go", path: "", ok: false},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.uri, func(t *testing.T) {
+			path, ok := lspDocumentPath(tc.uri)
+			if path != tc.path || ok != tc.ok {
+				t.Errorf("expected: %s/%v, but got: %s/%v", tc.path, tc.ok, path, ok)
+			}
+		})
+	}
+}
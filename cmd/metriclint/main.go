@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command metriclint lints Prometheus metrics.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	// Register the presets, so that configs can refer to them in their bundles entry.
+	_ "github.com/promlint/promlint/pkg/metriclint/presets"
+)
+
+// command is a metriclint sub command. It returns the process exit code.
+type command func(args []string) int
+
+var commands = map[string]command{
+	"explain":  runExplain,
+	"lint":     runLint,
+	"lsp":      runLSP,
+	"selftest": runSelfTest,
+	"triage":   runTriage,
+	"unused":   runUnused,
+	"watch":    runWatch,
+}
+
+// commandFlagSets returns the flags of the commands, for shell completion.
+var commandFlagSets = map[string]func() *flag.FlagSet{
+	"lint": func() *flag.FlagSet {
+		fs, _ := lintFlagSet()
+		return fs
+	},
+	"lsp": func() *flag.FlagSet {
+		fs, _ := lspFlagSet()
+		return fs
+	},
+	"selftest": func() *flag.FlagSet {
+		fs, _, _ := selfTestFlagSet()
+		return fs
+	},
+	"triage": func() *flag.FlagSet {
+		fs, _, _ := triageFlagSet()
+		return fs
+	},
+	"unused": func() *flag.FlagSet {
+		fs, _ := unusedFlagSet()
+		return fs
+	},
+	"watch": func() *flag.FlagSet {
+		fs, _ := watchFlagSet()
+		return fs
+	},
+}
+
+func usage() {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "usage: metriclint <command> [flags]\n       metriclint --list-rules [--format text|json]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", name)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	if os.Args[1] == listRulesFlag {
+		os.Exit(runListRules(os.Args[2:]))
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(cmd(os.Args[2:]))
+}
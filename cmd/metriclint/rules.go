@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const listRulesFlag = "--list-rules"
+
+func listRulesFlagSet() (fs *flag.FlagSet, format *string) {
+	fs = flag.NewFlagSet(listRulesFlag, flag.ExitOnError)
+	format = fs.String("format", "text", "output format, text or json")
+
+	return fs, format
+}
+
+func runListRules(args []string) int {
+	fs, format := listRulesFlagSet()
+	fs.Parse(args)
+
+	if err := listRules(os.Stdout, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "list-rules: %v\n", err)
+		return 2
+	}
+
+	return 0
+}
+
+// listRules writes the metadata of all rules in the given format.
+func listRules(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(metriclint.Rules())
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, rule := range metriclint.Rules() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", rule.ID, rule.Category, rule.Description)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
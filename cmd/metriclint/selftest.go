@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func selfTestFlagSet() (fs *flag.FlagSet, configPath, format *string) {
+	fs = flag.NewFlagSet("selftest", flag.ExitOnError)
+	configPath = fs.String("config", "", "path of the config to test, the default rules if empty")
+	format = fs.String("format", "text", "output format, text or json")
+
+	return fs, configPath, format
+}
+
+func runSelfTest(args []string) int {
+	fs, configPath, format := selfTestFlagSet()
+	fs.Parse(args)
+
+	config := &metriclint.Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = metriclint.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "selftest: %v\n", err)
+			return 1
+		}
+	}
+	printConfigWarnings(os.Stderr, config)
+
+	linter, err := metriclint.NewLinterFromConfig(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "selftest: %s: %v\n", *configPath, err)
+		return 1
+	}
+
+	results := linter.SelfTest()
+	if err := writeSelfTest(os.Stdout, *format, results); err != nil {
+		fmt.Fprintf(os.Stderr, "selftest: %v\n", err)
+		return 2
+	}
+
+	for _, result := range results {
+		if result.Status == metriclint.SelfTestMisconfigured {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// writeSelfTest writes the self test results in the given format.
+func writeSelfTest(w io.Writer, format string, results []metriclint.SelfTestResult) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, result := range results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Rule, result.Status, result.Detail)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
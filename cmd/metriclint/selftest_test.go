@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestWriteSelfTest(t *testing.T) {
+	results := []metriclint.SelfTestResult{
+		{Rule: metriclint.RuleHelpMissing, Status: metriclint.SelfTestDisabled},
+		{Rule: "no-legacy", Status: metriclint.SelfTestActive, Detail: "reports 0 of 5 known-good declarations"},
+	}
+
+	var tests = []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   "text",
+			expected: "help-missing  disabled  \nno-legacy     active    reports 0 of 5 known-good declarations\n",
+		},
+		{
+			format: "json",
+			expected: `[
+  {
+    "rule": "help-missing",
+    "status": "disabled"
+  },
+  {
+    "rule": "no-legacy",
+    "status": "active",
+    "detail": "reports 0 of 5 known-good declarations"
+  }
+]
+`,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeSelfTest(&out, tc.format, results); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("expected: %q, but got: %q", tc.expected, out.String())
+			}
+		})
+	}
+}
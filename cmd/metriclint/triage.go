@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// errTriageAborted is returned by triageScreen.run when the operator leaves without saving.
+var errTriageAborted = errors.New("aborted, the baseline is left unchanged")
+
+func triageFlagSet() (fs *flag.FlagSet, reportPath, baselinePath *string) {
+	fs = flag.NewFlagSet("triage", flag.ExitOnError)
+	reportPath = fs.String("report", "", "path of the JSON report to triage")
+	baselinePath = fs.String("baseline", "metriclint-baseline.json", "path of the baseline to update")
+
+	return fs, reportPath, baselinePath
+}
+
+// runTriage shows the findings of a report not decided in the baseline yet, in a full screen terminal UI,
+// and records the decisions in the baseline.
+func runTriage(args []string) int {
+	fs, reportPath, baselinePath := triageFlagSet()
+	fs.Parse(args)
+
+	if *reportPath == "" {
+		fmt.Fprintln(os.Stderr, "triage: --report is required")
+		return 2
+	}
+
+	data, err := ioutil.ReadFile(*reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "triage: %v\n", err)
+		return 1
+	}
+	report := &metriclint.Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		fmt.Fprintf(os.Stderr, "triage: failed to decode report: %v\n", err)
+		return 1
+	}
+
+	baseline, err := metriclint.LoadBaseline(*baselinePath)
+	if os.IsNotExist(err) {
+		baseline, err = &metriclint.Baseline{}, nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "triage: failed to load baseline: %v\n", err)
+		return 1
+	}
+
+	screen := newTriageScreen(report, baseline)
+	if len(screen.findings) == 0 {
+		fmt.Println("every finding of the report is already in the baseline")
+		return 0
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintln(os.Stderr, "triage: the standard input must be a terminal")
+		return 1
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "triage: %v\n", err)
+		return 1
+	}
+	screen.size = func() (int, int) {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			return 80, 24
+		}
+		return width, height
+	}
+	err = screen.run(os.Stdin, os.Stdout)
+	term.Restore(fd, state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "triage: %v\n", err)
+		return 1
+	}
+
+	if err := baseline.Save(*baselinePath); err != nil {
+		fmt.Fprintf(os.Stderr, "triage: failed to save baseline: %v\n", err)
+		return 1
+	}
+	fmt.Printf("baseline written to %s\n", *baselinePath)
+
+	return 0
+}
+
+// Keys of the triage screen, the arrow keys are decoded to keyUp and keyDown.
+const (
+	keyUp     = 'k'
+	keyDown   = 'j'
+	keyCtrlC  = 0x03
+	keyEscape = 0x1b
+)
+
+// triageActions maps the keys deciding the selected finding to the baseline actions.
+var triageActions = map[byte]string{
+	'f': metriclint.BaselineActionFix,
+	's': metriclint.BaselineActionSuppress,
+	'i': metriclint.BaselineActionIgnore,
+}
+
+// triageScreen lists the findings not decided in the baseline. The operator moves through the list and marks
+// the selected finding to be fixed, suppressed or ignored. The decisions are recorded in the baseline on quit,
+// so that decided findings, ignored ones included, are not shown again.
+type triageScreen struct {
+	baseline *metriclint.Baseline
+	// findings to decide, their Action is the decision taken on the screen, empty while undecided.
+	findings []metriclint.BaselineEntry
+	selected int
+	// first is the first finding shown, the list scrolls to keep the selected finding visible.
+	first int
+	// size returns the width and height of the terminal.
+	size func() (width, height int)
+}
+
+func newTriageScreen(report *metriclint.Report, baseline *metriclint.Baseline) *triageScreen {
+	s := &triageScreen{
+		baseline: baseline,
+		size:     func() (int, int) { return 80, 24 },
+	}
+	for _, result := range report.Results {
+		for _, issue := range result.Issues {
+			if !baseline.Has(result.MetricName, issue) {
+				s.findings = append(s.findings, metriclint.BaselineEntry{Metric: result.MetricName, Issue: issue})
+			}
+		}
+	}
+
+	return s
+}
+
+// run handles the keys read from in and draws the screen to out until the operator quits. Quitting with "q"
+// records the decisions in the baseline, leaving with Ctrl-C or Escape returns errTriageAborted.
+func (s *triageScreen) run(in io.Reader, out io.Writer) error {
+	// Switch to the alternate screen and hide the cursor, restoring both on exit.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	r := bufio.NewReader(in)
+	for {
+		s.draw(out)
+
+		key, err := readKey(r)
+		if err == io.EOF {
+			return errTriageAborted
+		}
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case keyUp:
+			s.move(-1)
+		case keyDown:
+			s.move(1)
+		case 'u':
+			s.findings[s.selected].Action = ""
+		case 'q':
+			s.record()
+			return nil
+		case keyCtrlC, keyEscape:
+			return errTriageAborted
+		default:
+			if action, ok := triageActions[key]; ok {
+				s.findings[s.selected].Action = action
+				s.move(1)
+			}
+		}
+	}
+}
+
+// readKey reads a key press, decoding the up and down arrow escape sequences.
+func readKey(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err != nil || b != keyEscape || r.Buffered() < 2 {
+		return b, err
+	}
+
+	seq, _ := r.Peek(2)
+	if seq[0] != '[' {
+		return b, nil
+	}
+	switch seq[1] {
+	case 'A':
+		r.Discard(2)
+		return keyUp, nil
+	case 'B':
+		r.Discard(2)
+		return keyDown, nil
+	}
+
+	return b, nil
+}
+
+// move moves the selection by delta findings, staying in the list.
+func (s *triageScreen) move(delta int) {
+	s.selected += delta
+	if s.selected < 0 {
+		s.selected = 0
+	}
+	if s.selected >= len(s.findings) {
+		s.selected = len(s.findings) - 1
+	}
+}
+
+// record adds the decided findings to the baseline.
+func (s *triageScreen) record() {
+	for _, finding := range s.findings {
+		if finding.Action != "" {
+			s.baseline.Add(finding.Metric, finding.Issue, finding.Action)
+		}
+	}
+}
+
+// draw draws the header, the visible part of the list, the selected finding and the keys.
+func (s *triageScreen) draw(out io.Writer) {
+	width, height := s.size()
+	// The header, the detail and the help lines take 5 rows.
+	rows := height - 5
+	if rows < 1 {
+		rows = 1
+	}
+	if s.selected < s.first {
+		s.first = s.selected
+	}
+	if s.selected >= s.first+rows {
+		s.first = s.selected - rows + 1
+	}
+
+	decided := 0
+	for _, finding := range s.findings {
+		if finding.Action != "" {
+			decided++
+		}
+	}
+
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	fmt.Fprintf(w, "\x1b[1mmetriclint triage\x1b[0m: %d findings, %d decided\r\n\r\n", len(s.findings), decided)
+	for i := s.first; i < len(s.findings) && i < s.first+rows; i++ {
+		finding := s.findings[i]
+		action := finding.Action
+		if action == "" {
+			action = "-"
+		}
+		line := truncate(fmt.Sprintf("  [%-8s] %s: %s", action, finding.Metric, finding.Issue), width)
+		if i == s.selected {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		fmt.Fprintf(w, "%s\r\n", line)
+	}
+
+	selected := s.findings[s.selected]
+	fmt.Fprintf(w, "\x1b[%d;1H%s\r\n", height-1, truncate(fmt.Sprintf("%s: %s", selected.Metric, selected.Issue), width))
+	fmt.Fprint(w, truncate("up/down move, f fix, s suppress, i ignore, u undo, q save and quit, ctrl-c abort", width))
+	w.Flush()
+}
+
+// truncate cuts s to width bytes, the findings are ASCII.
+func truncate(s string, width int) string {
+	if width > 0 && len(s) > width {
+		return s[:width]
+	}
+
+	return s
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestTriageScreen(t *testing.T) {
+	report := &metriclint.Report{
+		Results: []*metriclint.LintResult{
+			{MetricName: "lint_test", Issues: []string{metriclint.LintErrMsgNoHelp, metriclint.LintErrMsgCounterShouldHaveTotalSuffix}},
+			{MetricName: "lint_ms_total", Issues: []string{metriclint.LintErrMsgNameShouldNotHaveAbbr}},
+		},
+	}
+	baseline := &metriclint.Baseline{}
+
+	// Deciding moves to the next finding, the arrow keys and "u" change an earlier decision, unknown keys are
+	// ignored.
+	var out bytes.Buffer
+	if err := newTriageScreen(report, baseline).run(strings.NewReader("sfx\x1b[Au\x1b[Bi\x1b[A\x1b[Aiq"), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "3 findings, 2 decided") {
+		t.Errorf("expected the decision count in the screen, but got: %q", out.String())
+	}
+
+	expected := []metriclint.BaselineEntry{
+		{Metric: "lint_test", Issue: metriclint.LintErrMsgNoHelp, Action: metriclint.BaselineActionIgnore},
+		{Metric: "lint_ms_total", Issue: metriclint.LintErrMsgNameShouldNotHaveAbbr, Action: metriclint.BaselineActionIgnore},
+	}
+	if len(baseline.Entries) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, baseline.Entries)
+	}
+	for i := range expected {
+		if baseline.Entries[i] != expected[i] {
+			t.Errorf("expected: %v, but got: %v", expected[i], baseline.Entries[i])
+		}
+	}
+
+	// Decided findings, ignored ones included, are not shown again.
+	screen := newTriageScreen(report, baseline)
+	if len(screen.findings) != 1 || screen.findings[0].Issue != metriclint.LintErrMsgCounterShouldHaveTotalSuffix {
+		t.Fatalf("expected: %s, but got: %v", metriclint.LintErrMsgCounterShouldHaveTotalSuffix, screen.findings)
+	}
+
+	// Aborting leaves the baseline unchanged.
+	if err := screen.run(strings.NewReader("s\x03"), ioutil.Discard); err != errTriageAborted {
+		t.Fatalf("expected error: %v, but got: %v", errTriageAborted, err)
+	}
+	if len(baseline.Entries) != len(expected) {
+		t.Errorf("expected: %v, but got: %v", expected, baseline.Entries)
+	}
+
+	if err := newTriageScreen(report, baseline).run(strings.NewReader("sq"), ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	baseline.Filter(report.Results)
+	if got := report.Results[0].String(); got != "lint_test:"+metriclint.LintErrMsgNoHelp {
+		t.Errorf("suppressed issue should be filtered, got: %s", got)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+	"github.com/promlint/promlint/pkg/report"
+)
+
+type unusedFlags struct {
+	configPath string
+	prometheus string
+	window     time.Duration
+	format     string
+	timeout    time.Duration
+}
+
+func unusedFlagSet() (fs *flag.FlagSet, flags *unusedFlags) {
+	flags = &unusedFlags{}
+	fs = flag.NewFlagSet("unused", flag.ExitOnError)
+	fs.StringVar(&flags.configPath, "config", "", "path of the config, the default rules if empty")
+	fs.StringVar(&flags.prometheus, "prometheus", "", "URL of the Prometheus server scraping the target")
+	fs.DurationVar(&flags.window, "window", 7*24*time.Hour, "window over which the metrics should have changed")
+	fs.StringVar(&flags.format, "format", "text", fmt.Sprintf("output format, one of %v", report.Formats()))
+	fs.DurationVar(&flags.timeout, "timeout", 30*time.Second, "timeout of scraping the target and of each query")
+
+	return fs, flags
+}
+
+// runUnused reports the metrics of a scrape endpoint or of a file, "-" for stdin, which look unused on
+// a Prometheus server. Its findings are advisory, it exits with 0 unless the config raises their severity.
+func runUnused(args []string) int {
+	fs, flags := unusedFlagSet()
+	fs.Parse(args)
+
+	if fs.NArg() != 1 || flags.prometheus == "" {
+		fmt.Fprintln(os.Stderr, "usage: metriclint unused --prometheus <url> [flags] <url|file|->")
+		return lintExitFailure
+	}
+
+	linter, err := lintLinter(&lintFlags{configPath: flags.configPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unused: %v\n", err)
+		return lintExitFailure
+	}
+
+	api := &metriclint.PrometheusAPI{URL: flags.prometheus, Client: &http.Client{Timeout: flags.timeout}}
+	rep, err := unusedTarget(fs.Arg(0), linter, api, flags.window, flags.timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unused: %v\n", err)
+		return lintExitFailure
+	}
+
+	code := lintExitCode(linter, rep)
+	if err := report.Write(os.Stdout, flags.format, rep); err != nil {
+		fmt.Fprintf(os.Stderr, "unused: %v\n", err)
+		return lintExitFailure
+	}
+
+	return code
+}
+
+// unusedTarget reads the metrics of the target, in text format, and checks their usage on the server.
+func unusedTarget(target string, linter *metriclint.Linter, api *metriclint.PrometheusAPI, window, timeout time.Duration) (*metriclint.Report, error) {
+	data, _, err := readTarget(target, textAccept, timeout)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := promadapter.ExpositionSpecs(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", target, err)
+	}
+
+	results, err := linter.LintUsage(api, specs, window)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", api.URL, err)
+	}
+
+	builder := metriclint.NewReportBuilder()
+	builder.Add(results...)
+
+	return builder.Build(), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestUnusedTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metrics":
+			fmt.Fprint(w, "# HELP legacy_cache_hits_total Cache hits.\n# TYPE legacy_cache_hits_total counter\nlegacy_cache_hits_total 3\n")
+		case "/api/v1/rules":
+			fmt.Fprint(w, `{"status":"success","data":{"groups":[]}}`)
+		case "/api/v1/query":
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"0"]}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api := &metriclint.PrometheusAPI{URL: server.URL}
+	rep, err := unusedTarget(server.URL+"/metrics", metriclint.NewLinter(), api, time.Hour, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rep.Results) != 1 || len(rep.Results[0].Findings) != 1 || rep.Results[0].Findings[0].ID != metriclint.RulePossiblyUnusedMetric {
+		t.Fatalf("expected: %s, but got: %v", metriclint.RulePossiblyUnusedMetric, rep.Results)
+	}
+	if code := lintExitCode(metriclint.NewLinter(), rep); code != lintExitClean {
+		t.Errorf("expected: %d, but got: %d", lintExitClean, code)
+	}
+
+	if _, err := unusedTarget(server.URL+"/missing", metriclint.NewLinter(), api, time.Hour, time.Second); err == nil {
+		t.Errorf("expected an error for a missing target")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/promadapter"
+	"github.com/promlint/promlint/pkg/report"
+)
+
+type watchFlags struct {
+	configPath string
+	interval   time.Duration
+	format     string
+	timeout    time.Duration
+}
+
+func watchFlagSet() (fs *flag.FlagSet, flags *watchFlags) {
+	flags = &watchFlags{}
+	fs = flag.NewFlagSet("watch", flag.ExitOnError)
+	fs.StringVar(&flags.configPath, "config", "", "path of the config, the default rules if empty")
+	fs.DurationVar(&flags.interval, "interval", 30*time.Second, "interval between the two scrapes")
+	fs.StringVar(&flags.format, "format", "text", fmt.Sprintf("output format, one of %v", report.Formats()))
+	fs.DurationVar(&flags.timeout, "timeout", 10*time.Second, "timeout of scraping the URL")
+
+	return fs, flags
+}
+
+// runWatch scrapes an endpoint twice, an interval apart, and reports the counters which decreased
+// in between. It exits like runLint.
+func runWatch(args []string) int {
+	fs, flags := watchFlagSet()
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: metriclint watch [flags] <url>")
+		return lintExitFailure
+	}
+
+	linter, err := lintLinter(&lintFlags{configPath: flags.configPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		return lintExitFailure
+	}
+
+	rep, err := watchTarget(fs.Arg(0), linter, flags.interval, flags.timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		return lintExitFailure
+	}
+
+	code := lintExitCode(linter, rep)
+	if err := report.Write(os.Stdout, flags.format, rep); err != nil {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		return lintExitFailure
+	}
+
+	return code
+}
+
+// watchTarget scrapes the target twice, in text format since OpenMetrics counters are compared
+// by their _total samples, and compares both expositions.
+func watchTarget(target string, linter *metriclint.Linter, interval, timeout time.Duration) (*metriclint.Report, error) {
+	before, _, err := readTarget(target, textAccept, timeout)
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(interval)
+	after, _, err := readTarget(target, textAccept, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := promadapter.NewLinter(linter).CompareExpositions(bytes.NewReader(before), bytes.NewReader(after))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", target, err)
+	}
+
+	builder := metriclint.NewReportBuilder()
+	builder.Add(results...)
+
+	return builder.Build(), nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestWatchTarget(t *testing.T) {
+	var scrapes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != textAccept {
+			t.Errorf("expected: %s, but got: %s", textAccept, accept)
+		}
+		// The counter decreases on the second scrape.
+		value := 10 - 3*atomic.AddInt32(&scrapes, 1)
+		fmt.Fprintf(w, "# HELP queue_items_total Items queued.\n# TYPE queue_items_total counter\nqueue_items_total %d\n", value)
+	}))
+	defer server.Close()
+
+	rep, err := watchTarget(server.URL, metriclint.NewLinter(), time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rep.Results) != 1 || len(rep.Results[0].Findings) != 1 || rep.Results[0].Findings[0].ID != metriclint.RuleCounterDecreased {
+		t.Fatalf("expected: %s, but got: %v", metriclint.RuleCounterDecreased, rep.Results)
+	}
+	if code := lintExitCode(metriclint.NewLinter(), rep); code != lintExitIssues {
+		t.Errorf("expected: %d, but got: %d", lintExitIssues, code)
+	}
+
+	if _, err := watchTarget("missing.txt", metriclint.NewLinter(), time.Millisecond, time.Second); err == nil {
+		t.Errorf("expected an error for a missing target")
+	}
+}
@@ -1,8 +1,22 @@
 module github.com/promlint/promlint
 
-go 1.14
+go 1.17
 
 require (
+	github.com/klauspost/compress v1.15.15
 	github.com/prometheus/client_golang v1.6.0
 	github.com/prometheus/client_model v0.2.0
+	github.com/prometheus/common v0.9.1
+	golang.org/x/term v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.1.1 // indirect
+	github.com/golang/protobuf v1.4.0 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/procfs v0.0.11 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	google.golang.org/protobuf v1.21.0 // indirect
 )
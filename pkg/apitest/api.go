@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apitest guards the exported API of the metriclint packages.
+//
+// The exported surface of each package is dumped into a sorted list of declarations and
+// compared with a golden file under testdata, see docs/API.md for the stability policy.
+// Run `go test ./pkg/apitest -update` to accept an API change.
+package apitest
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Dump returns the exported declarations of the Go package in dir, one per line and sorted.
+// Test files are ignored.
+func Dump(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				lines = append(lines, dumpDecl(fset, decl)...)
+			}
+		}
+	}
+	sort.Strings(lines)
+
+	return lines, nil
+}
+
+func dumpDecl(fset *token.FileSet, decl ast.Decl) (lines []string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		if d.Recv != nil {
+			recv := expr(fset, d.Recv.List[0].Type)
+			if !ast.IsExported(strings.TrimPrefix(recv, "*")) {
+				return nil
+			}
+			return []string{fmt.Sprintf("method (%s) %s%s", recv, d.Name.Name, strings.TrimPrefix(expr(fset, d.Type), "func"))}
+		}
+		return []string{fmt.Sprintf("func %s%s", d.Name.Name, strings.TrimPrefix(expr(fset, d.Type), "func"))}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					lines = append(lines, dumpType(fset, s)...)
+				}
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if !name.IsExported() {
+						continue
+					}
+					line := fmt.Sprintf("%s %s", d.Tok, name.Name)
+					if s.Type != nil {
+						line += " " + expr(fset, s.Type)
+					}
+					lines = append(lines, line)
+				}
+			}
+		}
+	}
+
+	return lines
+}
+
+func dumpType(fset *token.FileSet, s *ast.TypeSpec) (lines []string) {
+	name := s.Name.Name
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		lines = append(lines, fmt.Sprintf("type %s struct", name))
+		for _, field := range t.Fields.List {
+			for _, n := range field.Names {
+				if n.IsExported() {
+					lines = append(lines, fmt.Sprintf("field %s.%s %s", name, n.Name, expr(fset, field.Type)))
+				}
+			}
+			if len(field.Names) == 0 {
+				lines = append(lines, fmt.Sprintf("embedded %s.%s", name, expr(fset, field.Type)))
+			}
+		}
+	case *ast.InterfaceType:
+		lines = append(lines, fmt.Sprintf("type %s interface", name))
+		for _, m := range t.Methods.List {
+			for _, n := range m.Names {
+				lines = append(lines, fmt.Sprintf("imethod %s.%s%s", name, n.Name, strings.TrimPrefix(expr(fset, m.Type), "func")))
+			}
+			if len(m.Names) == 0 {
+				lines = append(lines, fmt.Sprintf("embedded %s.%s", name, expr(fset, m.Type)))
+			}
+		}
+	default:
+		lines = append(lines, fmt.Sprintf("type %s %s", name, expr(fset, s.Type)))
+	}
+
+	return lines
+}
+
+func expr(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, e)
+	// Keep declarations on a single line.
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitest
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+var update = flag.Bool("update", false, "update the golden API files")
+
+// versionPrefix starts the first line of the golden files, which records the APIVersion they were written with.
+const versionPrefix = "# APIVersion "
+
+// packages maps the guarded packages to their golden files.
+var packages = map[string]string{
+	"../metriclint":             "testdata/metriclint.api",
+	"../metriclint/linttest":    "testdata/linttest.api",
+	"../metriclint/promadapter": "testdata/promadapter.api",
+	"../metriclint/source":      "testdata/source.api",
+	"../report":                 "testdata/report.api",
+}
+
+// readGolden returns the APIVersion recorded in the golden file and its declarations.
+func readGolden(path string) (version string, lines []string, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], versionPrefix) {
+		return "", nil, fmt.Errorf("%s doesn't start with %q", path, versionPrefix)
+	}
+
+	return strings.TrimPrefix(lines[0], versionPrefix), lines[1:], nil
+}
+
+// missing returns the lines of expected which are not in lines.
+func missing(expected, lines []string) []string {
+	current := map[string]bool{}
+	for _, l := range lines {
+		current[l] = true
+	}
+
+	var removed []string
+	for _, l := range expected {
+		if !current[l] {
+			removed = append(removed, l)
+		}
+	}
+
+	return removed
+}
+
+// TestAPICompatibility fails on the declarations removed or changed since the golden file was written. They
+// are breaking changes: -update only accepts them once metriclint.APIVersion differs from the version
+// recorded in the golden file.
+func TestAPICompatibility(t *testing.T) {
+	for dir, golden := range packages {
+		d, g := dir, golden
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			lines, err := Dump(d)
+			if err != nil {
+				t.Fatalf("failed to dump API: %v", err)
+			}
+
+			version, expected, err := readGolden(g)
+			if err != nil && !(*update && os.IsNotExist(err)) {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			removed := missing(expected, lines)
+
+			if *update {
+				if len(removed) > 0 && version == metriclint.APIVersion {
+					t.Fatalf("breaking changes need a new metriclint.APIVersion, removed or changed:\n%s", strings.Join(removed, "\n"))
+				}
+				data := versionPrefix + metriclint.APIVersion + "\n" + strings.Join(lines, "\n") + "\n"
+				if err := ioutil.WriteFile(g, []byte(data), 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+				return
+			}
+
+			for _, l := range removed {
+				if version == metriclint.APIVersion {
+					t.Errorf("breaking change without a new metriclint.APIVersion, removed or changed: %s", l)
+				} else {
+					t.Errorf("breaking change not recorded in %s (run with -update to accept): %s", g, l)
+				}
+			}
+			if version != metriclint.APIVersion {
+				t.Errorf("%s records API version %s instead of %s (run with -update)", g, version, metriclint.APIVersion)
+			}
+			for _, l := range missing(lines, expected) {
+				t.Errorf("new API not recorded in %s (run with -update to accept): %s", g, l)
+			}
+		})
+	}
+}
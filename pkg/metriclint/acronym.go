@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	LintErrMsgAcronymShouldBeLowercase = `acronym %q should be written as lowercase %q`
+	LintErrMsgAcronymMixedStyle        = `acronym %q is written as %q, but as %q in %s`
+)
+
+// DefaultAcronyms are the acronyms checked by an AcronymPolicy without explicit acronyms.
+var DefaultAcronyms = []string{
+	"api",
+	"cpu",
+	"dns",
+	"grpc",
+	"http",
+	"https",
+	"ip",
+	"rpc",
+	"tcp",
+	"tls",
+	"udp",
+	"uri",
+	"url",
+}
+
+// AcronymPolicy requires acronyms to be written as a single lowercase name segment,
+// e.g. "http" instead of "Http", "HTTP" or "h_t_t_p".
+type AcronymPolicy struct {
+	// Acronyms to check, DefaultAcronyms if empty.
+	Acronyms []string
+}
+
+func (p AcronymPolicy) acronyms() []string {
+	if len(p.Acronyms) == 0 {
+		return DefaultAcronyms
+	}
+
+	return p.Acronyms
+}
+
+// Lint checks the name segments of a single metric.
+func (p AcronymPolicy) Lint(name string) (issues []string) {
+	for _, segment := range strings.Split(name, "_") {
+		for _, acronym := range p.acronyms() {
+			if segment != acronym && strings.EqualFold(segment, acronym) {
+				issues = append(issues, fmt.Sprintf(LintErrMsgAcronymShouldBeLowercase, segment, acronym))
+			}
+		}
+	}
+
+	return issues
+}
+
+// LintBatch detects acronyms written as one segment in some metrics but split by "_" in others,
+// e.g. "grpc" and "g_rpc", and reports the split spellings.
+func (p AcronymPolicy) LintBatch(results []*LintResult) {
+	for _, acronym := range p.acronyms() {
+		var joined []string
+		for _, result := range results {
+			if hasSegments(strings.ToLower(result.MetricName), acronym) {
+				joined = append(joined, fmt.Sprintf("%q", result.MetricName))
+			}
+		}
+		if len(joined) == 0 {
+			continue
+		}
+		sort.Strings(joined)
+
+		for _, split := range splitSpellings(acronym) {
+			for _, result := range results {
+				if hasSegments(strings.ToLower(result.MetricName), split) {
+					result.AddRuleMessages(RuleAcronymMixedStyle, fmt.Sprintf(LintErrMsgAcronymMixedStyle, acronym, split, acronym, strings.Join(joined, ", ")))
+				}
+			}
+		}
+	}
+}
+
+// hasSegments reports whether segments, a "_" separated sequence of name segments, is part of name.
+func hasSegments(name, segments string) bool {
+	return name == segments ||
+		strings.HasPrefix(name, segments+"_") ||
+		strings.HasSuffix(name, "_"+segments) ||
+		strings.Contains(name, "_"+segments+"_")
+}
+
+// splitSpellings returns the spellings of an acronym with a single "_" inserted, e.g. "g_rpc" for "grpc".
+func splitSpellings(acronym string) (spellings []string) {
+	for i := 1; i < len(acronym); i++ {
+		spellings = append(spellings, acronym[:i]+"_"+acronym[i:])
+	}
+
+	return spellings
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAcronymPolicyLint(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         AcronymPolicy
+		metric         string
+		expectedResult string
+	}{
+		{
+			name:   "lowercase acronym",
+			metric: "http_requests_total",
+		},
+		{
+			name:           "capitalized acronym",
+			metric:         "Http_requests_total",
+			expectedResult: fmt.Sprintf(LintErrMsgAcronymShouldBeLowercase, "Http", "http"),
+		},
+		{
+			name:           "custom acronym",
+			policy:         AcronymPolicy{Acronyms: []string{"etcd"}},
+			metric:         "ETCD_requests_total",
+			expectedResult: fmt.Sprintf(LintErrMsgAcronymShouldBeLowercase, "ETCD", "etcd"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.policy.Lint(tc.metric), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestAcronymPolicyLintBatch(t *testing.T) {
+	results := []*LintResult{
+		{MetricName: "grpc_server_handled_total"},
+		{MetricName: "component_g_rpc_requests_total"},
+	}
+
+	AcronymPolicy{}.LintBatch(results)
+
+	expected := []string{
+		"grpc_server_handled_total:",
+		"component_g_rpc_requests_total:" + fmt.Sprintf(LintErrMsgAcronymMixedStyle, "grpc", "g_rpc", "grpc", `"grpc_server_handled_total"`),
+	}
+	for i, result := range results {
+		if result.String() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], result.String())
+		}
+	}
+}
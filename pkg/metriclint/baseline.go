@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+)
+
+// Triage decisions recorded in a Baseline.
+const (
+	// BaselineActionFix marks an issue to be fixed, it keeps being reported.
+	BaselineActionFix = "fix"
+	// BaselineActionSuppress marks an accepted issue, it won't be reported anymore.
+	BaselineActionSuppress = "suppress"
+	// BaselineActionIgnore marks an issue left as is, it keeps being reported but isn't triaged again.
+	BaselineActionIgnore = "ignore"
+)
+
+// BaselineEntry is the triage decision for an issue of a metric.
+type BaselineEntry struct {
+	Metric string `json:"metric"`
+	Issue  string `json:"issue"`
+	Action string `json:"action"`
+}
+
+// Baseline records triage decisions, so that a linter can be rolled out to an existing
+// code base without fixing every pre-existing issue first.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// LoadBaseline reads a baseline written by Baseline.Save.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, err
+	}
+
+	return baseline, nil
+}
+
+// Save writes the baseline as JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Add records a decision, replacing an earlier decision for the same issue.
+func (b *Baseline) Add(metric, issue, action string) {
+	for i, e := range b.Entries {
+		if e.Metric == metric && e.Issue == issue {
+			b.Entries[i].Action = action
+			return
+		}
+	}
+	b.Entries = append(b.Entries, BaselineEntry{Metric: metric, Issue: issue, Action: action})
+}
+
+// Has reports whether a decision has been recorded for the issue of the metric.
+func (b *Baseline) Has(metric, issue string) bool {
+	for _, e := range b.Entries {
+		if e.Metric == metric && e.Issue == issue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suppressed reports whether the issue of the metric is suppressed.
+func (b *Baseline) Suppressed(metric, issue string) bool {
+	for _, e := range b.Entries {
+		if e.Metric == metric && e.Issue == issue && e.Action == BaselineActionSuppress {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithBaseline suppresses the issues marked with BaselineActionSuppress in the baseline, see
+// Config.Baseline.
+func WithBaseline(baseline *Baseline) Option {
+	return func(l *Linter) {
+		for _, e := range baseline.Entries {
+			if e.Action == BaselineActionSuppress {
+				l.suppressions = append(l.suppressions, suppression{
+					metric:  regexp.MustCompile("^" + regexp.QuoteMeta(e.Metric) + "$"),
+					message: e.Issue,
+				})
+			}
+		}
+	}
+}
+
+// Filter removes suppressed issues from the results.
+func (b *Baseline) Filter(results []*LintResult) {
+	for _, result := range results {
+		findings := result.findings()
+		result.Findings, result.Issues = nil, nil
+		for _, finding := range findings {
+			if !b.Suppressed(result.MetricName, finding.Message) {
+				result.AddIssues(finding)
+			}
+		}
+	}
+}
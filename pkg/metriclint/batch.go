@@ -0,0 +1,197 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Batch rules look at a set of metrics at once, e.g. all metrics of a component,
+// and append their issues to the results of the affected metrics.
+
+const (
+	LintErrMsgSynonymName             = `metric name is nearly identical to %s, consider consolidating`
+	LintErrMsgErrorWithoutTotal       = `error counter has no matching counter of all attempts (%s), error ratios can't be computed`
+	LintErrMsgErrorTotalLabelMismatch = `error counter labels %v don't match labels %v of %q, error ratios can't be computed`
+	LintErrMsgExporterPrefix          = `metric name has the prefix %q of a widely deployed exporter, dashboards may conflate both sources`
+)
+
+// ErrorRatioRule is an advisory batch rule: an error counter like "foo_errors_total" should come with
+// a counter of all attempts like "foo_total" having the same labels, otherwise error ratios can't be computed.
+type ErrorRatioRule struct {
+	// Name suffixes of error counters, e.g. "_errors_total".
+	ErrorSuffixes []string
+
+	// Suffixes replacing the error suffix to name the counter of all attempts, e.g. "_attempts_total".
+	TotalSuffixes []string
+}
+
+// DefaultErrorRatioRule pairs the commonly used error and attempt counter names.
+var DefaultErrorRatioRule = ErrorRatioRule{
+	ErrorSuffixes: []string{"_errors_total", "_failures_total"},
+	TotalSuffixes: []string{"_total", "_attempts_total", "_requests_total"},
+}
+
+// Lint checks the declared metrics and returns the results of the error counters without a usable pair.
+func (r ErrorRatioRule) Lint(metrics []InventoryEntry) (results []*LintResult) {
+	byName := make(map[string]InventoryEntry, len(metrics))
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+
+	for _, m := range metrics {
+		for _, errSuffix := range r.ErrorSuffixes {
+			if !strings.HasSuffix(m.Name, errSuffix) {
+				continue
+			}
+			base := strings.TrimSuffix(m.Name, errSuffix)
+
+			var candidates []string
+			var issue string
+			for _, totalSuffix := range r.TotalSuffixes {
+				total, ok := byName[base+totalSuffix]
+				if !ok || total.Name == m.Name {
+					candidates = append(candidates, fmt.Sprintf("%q", base+totalSuffix))
+					continue
+				}
+				if sameLabels(m.Labels, total.Labels) {
+					issue = ""
+					break
+				}
+				issue = fmt.Sprintf(LintErrMsgErrorTotalLabelMismatch, CanonicalLabelNames(nil, m.Labels), CanonicalLabelNames(nil, total.Labels), total.Name)
+			}
+			if issue == "" && len(candidates) == len(r.TotalSuffixes) {
+				issue = fmt.Sprintf(LintErrMsgErrorWithoutTotal, strings.Join(candidates, ", "))
+			}
+			if issue != "" {
+				result := &LintResult{MetricName: m.Name}
+				result.AddRuleMessages(RuleErrorRatio, issue)
+				results = append(results, result)
+			}
+			break
+		}
+	}
+
+	return results
+}
+
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa, sb := CanonicalLabelNames(nil, a), CanonicalLabelNames(nil, b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DefaultExporterPrefixes are the name prefixes of widely deployed exporters.
+var DefaultExporterPrefixes = []string{"node_", "kube_", "container_", "nginx_"}
+
+// ExporterPrefixRule is a batch rule flagging metrics sharing the name prefix of a widely deployed exporter,
+// such as "node_" of the node exporter, since queries and dashboards would mix both sources.
+type ExporterPrefixRule struct {
+	// Prefixes of the exporters, DefaultExporterPrefixes if empty.
+	Prefixes []string
+
+	// Identity is the name of the exporter the linted binary is, e.g. "node" for the node exporter.
+	// Metrics with the prefix of the identity, "node_", are fine.
+	Identity string
+}
+
+// Lint appends an issue to the results whose metric name has an exporter prefix.
+func (r ExporterPrefixRule) Lint(results []*LintResult) {
+	prefixes := r.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultExporterPrefixes
+	}
+
+	for _, result := range results {
+		for _, prefix := range prefixes {
+			if r.Identity != "" && prefix == r.Identity+"_" {
+				continue
+			}
+			if strings.HasPrefix(result.MetricName, prefix) {
+				result.AddRuleMessages(RuleExporterPrefix, fmt.Sprintf(LintErrMsgExporterPrefix, prefix))
+				break
+			}
+		}
+	}
+}
+
+// LintSynonyms detects metrics whose names consist of the same tokens modulo plural forms and order,
+// e.g. "http_request_duration_seconds" and "http_requests_duration_seconds", which almost always
+// indicates accidental parallel instrumentation.
+func LintSynonyms(results []*LintResult) {
+	groups := map[string][]*LintResult{}
+	var keys []string
+	for _, result := range results {
+		key := synonymKey(result.MetricName)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], result)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		for _, result := range group {
+			var others []string
+			for _, other := range group {
+				if other.MetricName != result.MetricName {
+					others = append(others, fmt.Sprintf("%q", other.MetricName))
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			sort.Strings(others)
+			result.AddRuleMessages(RuleSynonymNames, fmt.Sprintf(LintErrMsgSynonymName, strings.Join(others, ", ")))
+		}
+	}
+}
+
+// synonymKey normalizes a metric name into its sorted set of singular tokens.
+func synonymKey(name string) string {
+	var tokens []string
+	seen := map[string]bool{}
+	for _, token := range strings.Split(strings.ToLower(name), "_") {
+		if len(token) > 3 && strings.HasSuffix(token, "s") {
+			token = strings.TrimSuffix(token, "s")
+		}
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	return strings.Join(tokens, "_")
+}
@@ -0,0 +1,177 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLintSynonyms(t *testing.T) {
+	results := []*LintResult{
+		{MetricName: "http_request_duration_seconds"},
+		{MetricName: "http_requests_duration_seconds"},
+		{MetricName: "http_response_size_bytes"},
+	}
+
+	LintSynonyms(results)
+
+	expected := []string{
+		fmt.Sprintf("http_request_duration_seconds:"+LintErrMsgSynonymName, `"http_requests_duration_seconds"`),
+		fmt.Sprintf("http_requests_duration_seconds:"+LintErrMsgSynonymName, `"http_request_duration_seconds"`),
+		"http_response_size_bytes:",
+	}
+	for i, result := range results {
+		if result.String() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], result.String())
+		}
+	}
+}
+
+func TestExporterPrefixRule(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rule     ExporterPrefixRule
+		expected []string
+	}{
+		{
+			name: "default prefixes",
+			expected: []string{
+				fmt.Sprintf("node_cpu_seconds_total:"+LintErrMsgExporterPrefix, "node_"),
+				fmt.Sprintf("kube_pod_info:"+LintErrMsgExporterPrefix, "kube_"),
+				"kubelet_pods:",
+			},
+		},
+		{
+			name: "binary is the node exporter",
+			rule: ExporterPrefixRule{Identity: "node"},
+			expected: []string{
+				"node_cpu_seconds_total:",
+				fmt.Sprintf("kube_pod_info:"+LintErrMsgExporterPrefix, "kube_"),
+				"kubelet_pods:",
+			},
+		},
+		{
+			name: "custom prefixes",
+			rule: ExporterPrefixRule{Prefixes: []string{"kubelet_"}},
+			expected: []string{
+				"node_cpu_seconds_total:",
+				"kube_pod_info:",
+				fmt.Sprintf("kubelet_pods:"+LintErrMsgExporterPrefix, "kubelet_"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results := []*LintResult{
+				{MetricName: "node_cpu_seconds_total"},
+				{MetricName: "kube_pod_info"},
+				{MetricName: "kubelet_pods"},
+			}
+			tc.rule.Lint(results)
+			for i, result := range results {
+				if result.String() != tc.expected[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expected[i], result.String())
+				}
+			}
+		})
+	}
+}
+
+func TestErrorRatioRule(t *testing.T) {
+	tests := []struct {
+		name            string
+		metrics         []InventoryEntry
+		expectedResults []string
+	}{
+		{
+			name: "paired with total",
+			metrics: []InventoryEntry{
+				{Name: "rpc_errors_total", Labels: []string{"method"}},
+				{Name: "rpc_total", Labels: []string{"method"}},
+			},
+		},
+		{
+			name: "paired with attempts",
+			metrics: []InventoryEntry{
+				{Name: "rpc_failures_total"},
+				{Name: "rpc_attempts_total"},
+			},
+		},
+		{
+			name: "no pair",
+			metrics: []InventoryEntry{
+				{Name: "rpc_errors_total"},
+			},
+			expectedResults: []string{
+				"rpc_errors_total:" + fmt.Sprintf(LintErrMsgErrorWithoutTotal, `"rpc_total", "rpc_attempts_total", "rpc_requests_total"`),
+			},
+		},
+		{
+			name: "label mismatch",
+			metrics: []InventoryEntry{
+				{Name: "rpc_errors_total", Labels: []string{"method", "code"}},
+				{Name: "rpc_total", Labels: []string{"method"}},
+			},
+			expectedResults: []string{
+				"rpc_errors_total:" + fmt.Sprintf(LintErrMsgErrorTotalLabelMismatch, []string{"code", "method"}, []string{"method"}, "rpc_total"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results := DefaultErrorRatioRule.Lint(tc.metrics)
+			if len(results) != len(tc.expectedResults) {
+				t.Fatalf("expected: %v, but got: %v", tc.expectedResults, results)
+			}
+			for i, result := range results {
+				if result.String() != tc.expectedResults[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expectedResults[i], result.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBatchFindingsConsistentWithIssues(t *testing.T) {
+	results := []*LintResult{
+		LintSpec(MetricSpec{Name: "lint_test_total", Type: MetricTypeCounter}),
+		LintSpec(MetricSpec{Name: "lint_tests_total", Type: MetricTypeCounter}),
+		LintSpec(MetricSpec{Name: "lint_g_rpc_total", Type: MetricTypeCounter}),
+		LintSpec(MetricSpec{Name: "lint_grpc_total", Type: MetricTypeCounter}),
+	}
+
+	LintSynonyms(results)
+	AcronymPolicy{}.LintBatch(results)
+	for _, result := range results {
+		assertConsistent(t, result)
+	}
+
+	baseline := &Baseline{}
+	baseline.Add("lint_test_total", LintErrMsgNoHelp, BaselineActionSuppress)
+	baseline.Filter(results)
+	for _, result := range results {
+		assertConsistent(t, result)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"sort"
+	"strings"
+)
+
+// CanonicalLabelNames returns the names of the const and variable labels sorted and without duplicates,
+// the order the linter compares label sets in.
+func CanonicalLabelNames(constLabels map[string]string, variableLabels []string) []string {
+	names := make([]string, 0, len(constLabels)+len(variableLabels))
+	for name := range constLabels {
+		names = append(names, name)
+	}
+	names = append(names, variableLabels...)
+	sort.Strings(names)
+
+	canonical := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			canonical = append(canonical, name)
+		}
+	}
+
+	return canonical
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// SeriesID returns the canonical identity of a series, formatted like the text exposition format
+// with the labels sorted by name, e.g. `http_requests_total{code="200",method="GET"}`.
+// Two series are the same series if and only if their identities are equal.
+func SeriesID(metric string, labels map[string]string) string {
+	var b strings.Builder
+	b.WriteString(metric)
+	if len(labels) == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('{')
+	for i, name := range CanonicalLabelNames(labels, nil) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelValueReplacer.Replace(labels[name]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "testing"
+
+func TestCanonicalLabelNames(t *testing.T) {
+	names := CanonicalLabelNames(map[string]string{"zone": "a", "code": "x"}, []string{"method", "code"})
+	expected := []string{"code", "method", "zone"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected: %v, but got: %v", expected, names)
+		}
+	}
+
+	if names := CanonicalLabelNames(nil, nil); len(names) != 0 {
+		t.Errorf("expected no names, but got: %v", names)
+	}
+}
+
+func TestSeriesID(t *testing.T) {
+	var tests = []struct {
+		name     string
+		metric   string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			name:     "no labels",
+			metric:   "up",
+			expected: "up",
+		},
+		{
+			name:     "sorted labels",
+			metric:   "http_requests_total",
+			labels:   map[string]string{"method": "GET", "code": "200"},
+			expected: `http_requests_total{code="200",method="GET"}`,
+		},
+		{
+			name:     "escaped values",
+			metric:   "lint_test",
+			labels:   map[string]string{"path": "a\"b\\c\nd"},
+			expected: `lint_test{path="a\"b\\c\nd"}`,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			if id := SeriesID(tc.metric, tc.labels); id != tc.expected {
+				t.Errorf("expected: %s, but got: %s", tc.expected, id)
+			}
+		})
+	}
+}
@@ -19,10 +19,9 @@ package metriclint
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/prometheus/client_golang/prometheus"
-	dto "github.com/prometheus/client_model/go"
 )
 
 // Units and their possible prefixes recognized by this library.  More can be
@@ -86,27 +85,34 @@ var (
 		"pebi",
 	}
 
-	// Common abbreviations that we'd like to discourage.
-	unitAbbreviations = []string{
-		"s",
-		"ms",
-		"us",
-		"ns",
-		"sec",
-		"b",
-		"kb",
-		"mb",
-		"gb",
-		"tb",
-		"pb",
-		"m",
-		"h",
-		"d",
+	// Common abbreviations that we'd like to discourage, mapped to the base units they likely stand for.
+	unitAbbreviations = map[string][]string{
+		"s":   {"seconds"},
+		"ms":  {"seconds"},
+		"us":  {"seconds"},
+		"ns":  {"seconds"},
+		"sec": {"seconds"},
+		"b":   {"bytes"},
+		"kb":  {"bytes"},
+		"mb":  {"bytes"},
+		"gb":  {"bytes"},
+		"tb":  {"bytes"},
+		"pb":  {"bytes"},
+		"m":   {"meters", "seconds"}, // Meters or minutes.
+		"h":   {"seconds"},
+		"d":   {"seconds"},
 	}
 )
 
 var camelCase = regexp.MustCompile(`[a-z][A-Z]`)
 
+// The same as model.MetricNameRE and model.LabelNameRE of prometheus/common, which is not imported
+// to keep this package free of dependencies.
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
 const (
 	NameSuffixSum = "_sum"
 
@@ -128,9 +134,28 @@ const (
 	LintErrMsgNoReservedChars = `metric names should not contain ':'`
 	LintErrMsgNameShouldBeSnakeCase = `metric names should be written in 'snake_case' not 'camelCase'`
 	LintErrMsgLabelShouldBeSnakeCase = `label names should be written in 'snake_case' not 'camelCase'`
+	// The abbreviated unit message of promtool and of earlier versions.
 	LintErrMsgNameShouldNotHaveAbbr = `metric names should not contain abbreviated units`
+	LintErrMsgAbbreviatedUnit = `metric name contains abbreviated unit %q, use %s`
+	LintErrMsgEmptyName = `metric name should not be empty`
+	LintErrMsgNamespaceEqualsSubsystem = `namespace and subsystem should not be the same`
+	LintErrMsgFQNamePartDoubleUnderscore = `%s %q produces "__" when joined into the metric name`
+	LintErrMsgLabelShadowsConstLabel = `variable label %q shadows the const label with value %q`
+	LintErrMsgLabelDuplicate = `variable label %q at index %d repeats the label at index %d`
+	LintErrMsgLabelReservedPrefix = `label %q should not start with "__", which is reserved for Prometheus internal use`
+	LintErrMsgInvalidMetricName = `metric name %q is invalid, it should match %s`
+	LintErrMsgInvalidLabelName = `label name %q is invalid, it should match %s`
+	LintErrMsgLabelRepeatsMetricName = `label %q repeats the metric name, use %q instead`
+	LintErrMsgUnknownUnit = `metric name should end with a unit, "_total", "_info", "_ratio" or an allowed noun, not %q`
+	LintErrMsgDeclaredUnitSuffix = `metric name should end with its declared unit %q`
+	LintErrMsgDeclaredUnitUnknown = `declared unit %q is not a recognized unit`
+	LintErrMsgDeclaredUnitNotBase = `declared unit %q is not a base unit, use %q`
+	LintErrMsgUntyped = `untyped metric, prefer a counter, gauge, histogram or summary`
 )
 
+// Name suffixes which are accepted in place of a unit by LintUnitSuffix.
+var unitlessSuffixes = []string{"total", "info", "ratio"}
+
 func lintHelp(help string) (issues []string) {
 	if len(help) == 0 {
 		issues = append(issues, "no help text")
@@ -159,32 +184,35 @@ func lintNonCounterNoTotal(name string) (issues []string) {
 	return issues
 }
 
-// metricUnits attempts to detect known unit types used as part of a metric name,
-// e.g. "foo_bytes_total" or "bar_baz_milligrams".
-func getMetricUnit(m string) (unit string, base string, ok bool) {
-	ss := strings.Split(m, "_")
-
-	for unit, base := range units {
-		// Also check for "no prefix".
-		for _, p := range append(unitPrefixes, "") {
-			for _, s := range ss {
-				// Attempt to explicitly match a known unit with a known prefix,
-				// as some words may look like "units" when matching suffix.
-				//
-				// As an example, "thermometers" should not match "meters", but
-				// "kilometers" should.
-				if s == p+unit {
-					return p + unit, base, true
-				}
-			}
+// DetectUnit returns the first "_" separated segment of the metric name which is a known unit, with an
+// optional known prefix, and the base unit it should be expressed in, e.g. "milliseconds" and "seconds"
+// for "http_request_duration_milliseconds". ok is false if the name has no known unit.
+func DetectUnit(name string) (unit string, base string, ok bool) {
+	return defaultUnits.detect(name)
+}
+
+// LintUnitSuffix is an opt-in rule which requires the last segment of the metric name to be a known unit,
+// "_total", "_info", "_ratio" or one of the given nouns, e.g. "requests". It's not part of the default
+// rules because plenty of valid metrics are unitless, but some teams mandate explicit units everywhere.
+func LintUnitSuffix(name string, nouns ...string) (issues []string) {
+	suffix := name[strings.LastIndex(name, "_")+1:]
+
+	if defaultUnits.isUnit(suffix) {
+		return nil
+	}
+	for _, s := range append(unitlessSuffixes, nouns...) {
+		if suffix == s {
+			return nil
 		}
 	}
 
-	return "", "", false
+	issues = append(issues, fmt.Sprintf(LintErrMsgUnknownUnit, suffix))
+
+	return issues
 }
 
-func lintMetricUnit(name string) (issues []string) {
-	unit, base, ok := getMetricUnit(name)
+func lintMetricUnit(name string, units *unitMatcher) (issues []string) {
+	unit, base, ok := units.detect(name)
 	if !ok {
 		// No known units detected.
 		return nil
@@ -232,7 +260,7 @@ func lintNonHistogramSummaryNoSum(name string) (issues []string) {
 	return issues
 }
 
-func lintNonHistogramNoLabelLe(constLabels prometheus.Labels, labelNames []string) (issues []string) {
+func lintNonHistogramNoLabelLe(constLabels map[string]string, labelNames []string) (issues []string) {
 	for ln, _ := range constLabels {
 		if ln == LabelLe {
 			issues = append(issues, `non-histogram metrics should not have "le" label`)
@@ -248,7 +276,7 @@ func lintNonHistogramNoLabelLe(constLabels prometheus.Labels, labelNames []strin
 	return issues
 }
 
-func lintNonSummaryNoLabelQuantile(constLabels prometheus.Labels, labelNames []string) (issues []string) {
+func lintNonSummaryNoLabelQuantile(constLabels map[string]string, labelNames []string) (issues []string) {
 	for ln, _ := range constLabels {
 		if ln == LabelQuantile {
 			issues = append(issues, LintErrMsgNonSummaryShouldNotHaveQuantileLabel)
@@ -267,12 +295,8 @@ func lintNonSummaryNoLabelQuantile(constLabels prometheus.Labels, labelNames []s
 func lintNoMetricTypeInName(name string) (issues []string) {
 	n := strings.ToLower(name)
 
-	for i, t := range dto.MetricType_name {
-		if i == int32(dto.MetricType_UNTYPED) {
-			continue
-		}
-
-		typename := strings.ToLower(t)
+	for _, t := range typedMetricTypes {
+		typename := string(t)
 		if strings.Contains(n, "_"+typename+"_") || strings.HasSuffix(n, "_"+typename) {
 			issues = append(issues, fmt.Sprintf(`metric name should not include type '%s'`, typename))
 		}
@@ -298,64 +322,230 @@ func lintNameCamelCase(name string) (issues []string) {
 	return issues
 }
 
+// lintLabelNameShadowsConstLabel detects variable labels which duplicate const labels.
+// client_golang only reports "duplicate label names" at registration time without naming the label,
+// and the Desc no longer carries the const labels by then, so this has to be checked against the opts.
+func lintLabelNameShadowsConstLabel(constLabels map[string]string, labelNames []string) (issues []string) {
+	for _, ln := range labelNames {
+		if _, ok := constLabels[ln]; ok {
+			issues = append(issues, fmt.Sprintf(LintErrMsgLabelShadowsConstLabel, ln, constLabels[ln]))
+		}
+	}
+
+	return issues
+}
+
+// lintDuplicateLabelNames detects variable labels listed more than once, which client_golang
+// reports as "duplicate label names" at registration time without naming the label.
+func lintDuplicateLabelNames(labelNames []string) (issues []string) {
+	seen := map[string]int{}
+	for i, ln := range labelNames {
+		if first, ok := seen[ln]; ok {
+			issues = append(issues, fmt.Sprintf(LintErrMsgLabelDuplicate, ln, i, first))
+			continue
+		}
+		seen[ln] = i
+	}
+
+	return issues
+}
+
+// lintLabelNameReservedPrefix detects const and variable label names starting with "__".
+func lintLabelNameReservedPrefix(constLabels map[string]string, labelNames []string) (issues []string) {
+	for _, ln := range append(sortedLabelNames(constLabels), labelNames...) {
+		if strings.HasPrefix(ln, "__") {
+			issues = append(issues, fmt.Sprintf(LintErrMsgLabelReservedPrefix, ln))
+		}
+	}
+
+	return issues
+}
+
+// lintInvalidNames detects metric and label names rejected by Prometheus, e.g. starting with a digit
+// or containing "-", rather than stylistically wrong. Empty names are reported by other rules.
+func lintInvalidNames(name string, constLabels map[string]string, labelNames []string) (issues []string) {
+	if name != "" && !metricNameRE.MatchString(name) {
+		issues = append(issues, fmt.Sprintf(LintErrMsgInvalidMetricName, name, metricNameRE))
+	}
+	for _, ln := range append(sortedLabelNames(constLabels), labelNames...) {
+		if ln != "" && !labelNameRE.MatchString(ln) {
+			issues = append(issues, fmt.Sprintf(LintErrMsgInvalidLabelName, ln, labelNameRE))
+		}
+	}
+
+	return issues
+}
+
+// lintLabelNameRepeatsMetricName detects label names starting with segments of the metric name,
+// e.g. "http_method" on "http_requests_total", and suggests the shortened label name.
+func lintLabelNameRepeatsMetricName(name string, constLabels map[string]string, labelNames []string) (issues []string) {
+	segments := map[string]bool{}
+	for _, s := range strings.Split(name, "_") {
+		segments[s] = true
+	}
+
+	check := func(ln string) {
+		parts := strings.Split(ln, "_")
+		i := 0
+		for i < len(parts)-1 && segments[parts[i]] {
+			i++
+		}
+		if i > 0 {
+			issues = append(issues, fmt.Sprintf(LintErrMsgLabelRepeatsMetricName, ln, strings.Join(parts[i:], "_")))
+		}
+	}
+
+	for _, ln := range sortedLabelNames(constLabels) {
+		check(ln)
+	}
+	for _, ln := range labelNames {
+		check(ln)
+	}
+
+	return issues
+}
+
+// sortedLabelNames returns the names of the labels in a stable order.
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for ln := range labels {
+		names = append(names, ln)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // TODO(RainbowMango): Should check label value? Check with promlint guys.
-func lintLabelNameCamelCase(constLabels prometheus.Labels, labelNames []string) (issues []string) {
-	for ln, _ := range constLabels {
+func lintLabelNameCamelCase(constLabels map[string]string, labelNames []string) (issues []string) {
+	for range camelCaseLabels(constLabels, labelNames) {
+		issues = append(issues, "label names should be written in 'snake_case' not 'camelCase'")
+	}
+
+	return issues
+}
+
+// camelCaseLabels returns the camelCase const label names, sorted, followed by the camelCase variable label names.
+func camelCaseLabels(constLabels map[string]string, labelNames []string) (labels []string) {
+	for ln := range constLabels {
 		if camelCase.FindString(ln) != "" {
-			issues = append(issues, "label names should be written in 'snake_case' not 'camelCase'")
+			labels = append(labels, ln)
 		}
 	}
+	sort.Strings(labels)
 
 	for _, ln := range labelNames {
 		if camelCase.FindString(ln) != "" {
-			issues = append(issues, "label names should be written in 'snake_case' not 'camelCase'")
+			labels = append(labels, ln)
 		}
 	}
 
+	return labels
+}
+
+// lintDeclaredUnit checks that the declared unit, if any, is a recognized base unit and the suffix
+// of the metric name, before the "_total" suffix of counters.
+func lintDeclaredUnit(name, unit string) (issues []string) {
+	if unit == "" {
+		return nil
+	}
+
+	if !strings.HasSuffix(strings.TrimSuffix(name, "_total"), "_"+unit) {
+		issues = append(issues, fmt.Sprintf(LintErrMsgDeclaredUnitSuffix, unit))
+	}
+	if detected, base, ok := DetectUnit(unit); !ok || detected != unit {
+		issues = append(issues, fmt.Sprintf(LintErrMsgDeclaredUnitUnknown, unit))
+	} else if base != unit {
+		issues = append(issues, fmt.Sprintf(LintErrMsgDeclaredUnitNotBase, unit, base))
+	}
+
 	return issues
 }
 
 // lintUnitAbbreviations detects abbreviated units in the metric name.
-// TODO(RainbowMango): It'd be better to return which abbreviated unit contains in name. Check with promlint guys.
+// Every abbreviation is reported once, in the order of the name segments, with the base units it likely stands for.
+// The first segment isn't checked, it's usually a namespace.
 func lintUnitAbbreviations(name string) (issues []string) {
-	n := strings.ToLower(name)
-	for _, s := range unitAbbreviations {
-		if strings.Contains(n, "_"+s+"_") || strings.HasSuffix(n, "_"+s) {
-			issues = append(issues, "metric names should not contain abbreviated units")
+	segments := strings.Split(strings.ToLower(name), "_")
+	reported := map[string]bool{}
+	for _, s := range segments[1:] {
+		bases, ok := unitAbbreviations[s]
+		if !ok || reported[s] {
+			continue
 		}
+		reported[s] = true
+
+		quoted := make([]string, 0, len(bases))
+		for _, base := range bases {
+			quoted = append(quoted, strconv.Quote(base))
+		}
+		issues = append(issues, fmt.Sprintf(LintErrMsgAbbreviatedUnit, s, strings.Join(quoted, " or ")))
+	}
+
+	return issues
+}
+
+// lintEmptyName checks the metric has a name at all, the other name part rules are skipped otherwise.
+func lintEmptyName(name string) (issues []string) {
+	if len(name) == 0 {
+		issues = append(issues, LintErrMsgEmptyName)
+	}
+
+	return issues
+}
+
+func lintNamespaceEqualsSubsystem(namespace, subsystem string) (issues []string) {
+	if len(namespace) != 0 && namespace == subsystem {
+		issues = append(issues, LintErrMsgNamespaceEqualsSubsystem)
+	}
+
+	return issues
+}
+
+// lintFQNameParts checks the pieces of a metric name before they are joined by MetricSpec.FQName,
+// so that the issue points at the offending piece instead of the joined name.
+func lintFQNameParts(namespace, subsystem, name string) (issues []string) {
+	// A piece produces "__" if it starts with "_" and has a piece before it,
+	// or ends with "_" and has a piece after it.
+	if len(namespace) != 0 && strings.HasSuffix(namespace, "_") {
+		issues = append(issues, fmt.Sprintf(LintErrMsgFQNamePartDoubleUnderscore, "namespace", namespace))
+	}
+	if len(subsystem) != 0 &&
+		((len(namespace) != 0 && strings.HasPrefix(subsystem, "_")) || strings.HasSuffix(subsystem, "_")) {
+		issues = append(issues, fmt.Sprintf(LintErrMsgFQNamePartDoubleUnderscore, "subsystem", subsystem))
+	}
+	if (len(namespace) != 0 || len(subsystem) != 0) && strings.HasPrefix(name, "_") {
+		issues = append(issues, fmt.Sprintf(LintErrMsgFQNamePartDoubleUnderscore, "name", name))
 	}
 
 	return issues
 }
 
 // commonLint checks the common rules for all types of metric.
-func commonLint(opts interface{}) (issues []string) {
-	var fqName string
-	var help string
-
-	switch opts.(type) {
-	case prometheus.Opts: // prometheus.CounterOpts and prometheus.GaugeOpts share the type.
-		counterGagueOpts := opts.(prometheus.Opts)
-		fqName = prometheus.BuildFQName(counterGagueOpts.Namespace, counterGagueOpts.Subsystem, counterGagueOpts.Name)
-		help = counterGagueOpts.Help
-	case prometheus.HistogramOpts:
-		histogramOpts := opts.(prometheus.HistogramOpts)
-		fqName = prometheus.BuildFQName(histogramOpts.Namespace, histogramOpts.Subsystem, histogramOpts.Name)
-		help = histogramOpts.Help
-	case prometheus.SummaryOpts:
-		summaryOpts := opts.(prometheus.SummaryOpts)
-		fqName = prometheus.BuildFQName(summaryOpts.Namespace, summaryOpts.Subsystem, summaryOpts.Name)
-		help = summaryOpts.Help
-	default:
-		panic(fmt.Sprintf("unknow metric type: %T", opts))
-	}
-
-	issues = append(issues, lintHelp(help)...) // metrics should contains help.
-	issues = append(issues, lintMetricUnit(fqName)...) // name should use standard units.
-	issues = append(issues, lintNoMetricTypeInName(fqName)...) // metric name should not include metric type
-	issues = append(issues, lintReservedChars(fqName)...) // metric names should not contain ':'
-	issues = append(issues, lintNameCamelCase(fqName)...) // metric names should be written in 'snake_case' not 'camelCase'
-	issues = append(issues, lintUnitAbbreviations(fqName)...) // metric names should not contain abbreviated units
+func commonLint(spec MetricSpec) (issues []Issue) {
+	fqName := spec.FQName()
+
+	if empty := lintEmptyName(spec.Name); len(empty) != 0 {
+		issues = append(issues, ruleIssues(RuleNameEmpty, empty)...)
+	} else {
+		issues = append(issues, ruleIssues(RuleNamespaceEqualsSubsystem, lintNamespaceEqualsSubsystem(spec.Namespace, spec.Subsystem))...)
+		issues = append(issues, ruleIssues(RuleNameDoubleUnderscore, lintFQNameParts(spec.Namespace, spec.Subsystem, spec.Name))...) // name pieces should join into a sane name.
+	}
+	issues = append(issues, ruleIssues(RuleHelpMissing, lintHelp(spec.Help))...) // metrics should contains help.
+	issues = append(issues, ruleIssues(RuleNonBaseUnit, lintMetricUnit(fqName, defaultUnits))...) // name should use standard units.
+	issues = append(issues, ruleIssues(RuleNameHasType, lintNoMetricTypeInName(fqName))...) // metric name should not include metric type
+	issues = append(issues, ruleIssues(RuleNameReservedChars, lintReservedChars(fqName))...) // metric names should not contain ':'
+	issues = append(issues, ruleIssues(RuleNameCamelCase, lintNameCamelCase(fqName))...) // metric names should be written in 'snake_case' not 'camelCase'
+	issues = append(issues, ruleIssues(RuleNameAbbreviatedUnit, lintUnitAbbreviations(fqName))...) // metric names should not contain abbreviated units
+	issues = append(issues, ruleIssues(RuleNameSuffixTypo, lintSuffixTypo(fqName))...) // metric names should not contain typos of units and suffixes
+	issues = append(issues, ruleIssues(RuleDeclaredUnit, lintDeclaredUnit(fqName, spec.Unit))...) // declared unit should be a base unit ending the name
+	issues = append(issues, ruleIssues(RuleInvalidName, lintInvalidNames(fqName, spec.ConstLabels, spec.VariableLabels))...) // names should be valid Prometheus names
+	issues = append(issues, ruleIssues(RuleHighCardinalityLabel, CardinalityLabelRule{}.Lint(spec.ConstLabels, spec.VariableLabels))...) // label names should not be usually unbounded
+	issues = append(issues, ruleIssues(RuleRatio, lintRatio(fqName, spec.Type))...) // ratios should be unitless gauges
+	issues = append(issues, ruleIssues(RulePercentName, lintPercentName(fqName))...) // ratios should not be percentages
+	issues = append(issues, ruleIssues(RuleInfoMetric, lintInfo(fqName, spec.Type, spec.Unit))...) // "_info" metrics should be unitless gauges
+	issues = append(issues, ruleIssues(RuleInfoName, lintInfoName(fqName, spec.Type, spec.ConstLabels, spec.VariableLabels))...) // build info gauges should be "_info" metrics
+	issues = append(issues, ruleIssues(RuleTimestampName, lintTimestamp(fqName, spec.Type))...) // timestamps should be in seconds since the epoch
 
 	return issues
 }
\ No newline at end of file
@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintUnitSuffix(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		nouns          []string
+		expectedResult string
+	}{
+		{
+			name:   "base unit",
+			metric: "lint_test_seconds",
+		},
+		{
+			name:   "prefixed unit",
+			metric: "lint_test_kilobytes",
+		},
+		{
+			name:   "total suffix",
+			metric: "lint_test_total",
+		},
+		{
+			name:   "ratio suffix",
+			metric: "lint_test_ratio",
+		},
+		{
+			name:   "allowed noun",
+			metric: "lint_test_requests",
+			nouns:  []string{"requests"},
+		},
+		{
+			name:           "unknown suffix",
+			metric:         "lint_test_requests",
+			expectedResult: fmt.Sprintf(LintErrMsgUnknownUnit, "requests"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(LintUnitSuffix(tc.metric, tc.nouns...), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLintDeclaredUnit(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		unit           string
+		expectedResult string
+	}{
+		{
+			name:   "no declared unit",
+			metric: "lint_test_duration",
+		},
+		{
+			name:   "base unit suffix",
+			metric: "lint_test_duration_seconds",
+			unit:   "seconds",
+		},
+		{
+			name:   "base unit before total suffix",
+			metric: "lint_test_sent_bytes_total",
+			unit:   "bytes",
+		},
+		{
+			name:           "unit missing from name",
+			metric:         "lint_test_duration",
+			unit:           "seconds",
+			expectedResult: fmt.Sprintf(LintErrMsgDeclaredUnitSuffix, "seconds"),
+		},
+		{
+			name:           "prefixed unit",
+			metric:         "lint_test_duration_milliseconds",
+			unit:           "milliseconds",
+			expectedResult: fmt.Sprintf(LintErrMsgDeclaredUnitNotBase, "milliseconds", "seconds"),
+		},
+		{
+			name:           "unknown unit",
+			metric:         "lint_test_size_blocks",
+			unit:           "blocks",
+			expectedResult: fmt.Sprintf(LintErrMsgDeclaredUnitUnknown, "blocks"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(lintDeclaredUnit(tc.metric, tc.unit), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLintInvalidNames(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		constLabels    map[string]string
+		labelNames     []string
+		expectedResult string
+	}{
+		{
+			name:        "valid names",
+			metric:      "lint_test:rate5m",
+			constLabels: map[string]string{"_zone": "a"},
+			labelNames:  []string{"code"},
+		},
+		{
+			name:           "metric name starting with a digit",
+			metric:         "5xx_errors_total",
+			expectedResult: fmt.Sprintf(LintErrMsgInvalidMetricName, "5xx_errors_total", metricNameRE),
+		},
+		{
+			name:        "label names with dash or colon",
+			metric:      "lint_test_total",
+			constLabels: map[string]string{"zone-name": "a"},
+			labelNames:  []string{"code:class", ""},
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgInvalidLabelName, "zone-name", labelNameRE),
+				fmt.Sprintf(LintErrMsgInvalidLabelName, "code:class", labelNameRE),
+			}, ","),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(lintInvalidNames(tc.metric, tc.constLabels, tc.labelNames), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLintUnitAbbreviations(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		expectedResult string
+	}{
+		{
+			name:   "no abbreviation",
+			metric: "lint_request_duration_seconds",
+		},
+		{
+			name:           "abbreviated unit",
+			metric:         "lint_request_duration_ms",
+			expectedResult: fmt.Sprintf(LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name:           "ambiguous abbreviation",
+			metric:         "lint_uptime_m",
+			expectedResult: fmt.Sprintf(LintErrMsgAbbreviatedUnit, "m", `"meters" or "seconds"`),
+		},
+		{
+			name:   "every abbreviation once in name order",
+			metric: "lint_kb_per_s_kb",
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgAbbreviatedUnit, "kb", `"bytes"`),
+				fmt.Sprintf(LintErrMsgAbbreviatedUnit, "s", `"seconds"`),
+			}, ","),
+		},
+		{
+			name:   "first segment",
+			metric: "ms_request_duration_seconds",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintUnitAbbreviations(tc.metric), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestDetectUnit(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric string
+		unit   string
+		base   string
+		ok     bool
+	}{
+		{
+			name:   "base unit",
+			metric: "lint_test_seconds",
+			unit:   "seconds",
+			base:   "seconds",
+			ok:     true,
+		},
+		{
+			name:   "prefixed unit",
+			metric: "lint_test_milliseconds",
+			unit:   "milliseconds",
+			base:   "seconds",
+			ok:     true,
+		},
+		{
+			name:   "first unit wins",
+			metric: "lint_test_hours_bytes",
+			unit:   "hours",
+			base:   "seconds",
+			ok:     true,
+		},
+		{
+			name:   "word ending like a unit",
+			metric: "lint_test_thermometers",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			unit, base, ok := DetectUnit(tc.metric)
+			if unit != tc.unit || base != tc.base || ok != tc.ok {
+				t.Errorf("expected: %s %s %v, but got: %s %s %v", tc.unit, tc.base, tc.ok, unit, base, ok)
+			}
+		})
+	}
+}
+
+func TestSuggestBaseUnitName(t *testing.T) {
+	tests := []struct {
+		metric   string
+		expected string
+	}{
+		{metric: "lint_test_milliseconds", expected: "lint_test_seconds"},
+		{metric: "lint_test_hours_total", expected: "lint_test_seconds_total"},
+		{metric: "lint_test_seconds", expected: "lint_test_seconds"},
+		{metric: "lint_test_requests", expected: "lint_test_requests"},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.metric, func(t *testing.T) {
+			if name := SuggestBaseUnitName(tc.metric); name != tc.expected {
+				t.Errorf("expected: %s, but got: %s", tc.expected, name)
+			}
+		})
+	}
+}
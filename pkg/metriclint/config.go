@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "time"
+
+// Config is the lint policy of a project, usually kept in a file next to the code.
+type Config struct {
+	// Name of the built-in profile the config starts from, see Profiles. ProfileDefault if empty.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// IDs of the rules to enable and to disable. A rule can't be in both.
+	Enable  []string `json:"enable,omitempty" yaml:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty" yaml:"disable,omitempty"`
+
+	// Severities replacing the default severity of rules, keyed by rule ID.
+	Severities map[string]Severity `json:"severities,omitempty" yaml:"severities,omitempty"`
+
+	// Severity from which issues fail the lint run, see FailOn. SeverityError if empty.
+	FailOn Severity `json:"failOn,omitempty" yaml:"failOn,omitempty"`
+
+	// Whether the issues of each result are sorted by rule ID, see SortIssues.
+	SortIssues bool `json:"sortIssues,omitempty" yaml:"sortIssues,omitempty"`
+
+	// Regular expressions, anchored to the whole metric name, of the metrics not to lint at all.
+	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+
+	// Issues not to report for some metrics.
+	Suppressions []Suppression `json:"suppressions,omitempty" yaml:"suppressions,omitempty"`
+
+	// Namespace and subsystem the metrics should have, enables RuleNamespacePolicy.
+	Namespace *NamespacePolicy `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Units recognized in addition to the built-in ones, mapped to their base unit,
+	// e.g. "kibibytes": "bytes". A base unit maps to itself.
+	Units map[string]string `json:"units,omitempty" yaml:"units,omitempty"`
+
+	// Scrape interval of the metrics, e.g. "30s", see WithScrapeInterval. DefaultScrapeInterval if zero.
+	ScrapeInterval time.Duration `json:"scrapeInterval,omitempty" yaml:"scrapeInterval,omitempty"`
+
+	// Groups of label names meaning the same concept, compared by Linter.LintLabelSchema.
+	// DefaultLabelSynonyms if empty.
+	LabelSynonyms [][]string `json:"labelSynonyms,omitempty" yaml:"labelSynonyms,omitempty"`
+
+	// User defined rules run in addition to the built-in ones.
+	Rules []DeclarativeRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+
+	// Names of the registered policy bundles to apply, see RegisterPolicyBundle.
+	Bundles []string `json:"bundles,omitempty" yaml:"bundles,omitempty"`
+
+	// Path of the baseline written by "metriclint triage", the issues it suppresses are not reported.
+	// LoadConfig resolves a relative path against the directory of the config file.
+	Baseline string `json:"baseline,omitempty" yaml:"baseline,omitempty"`
+
+	// Metrics scheduled for removal, see Tombstone.
+	Tombstones []Tombstone `json:"tombstones,omitempty" yaml:"tombstones,omitempty"`
+
+	// Positions of the config entries in the file, keyed by path such as "rules[0].pattern".
+	// Only set by ParseConfig.
+	positions map[string]ConfigPosition
+}
+
+// Suppression silences rules for the metrics matching a pattern, until it expires.
+type Suppression struct {
+	// Regular expression, anchored to the whole metric name.
+	Metric string `json:"metric" yaml:"metric"`
+
+	// IDs of the suppressed rules.
+	Rules []string `json:"rules" yaml:"rules"`
+
+	// Why the issues are suppressed.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// The suppression is invalid after this time, it never expires if zero.
+	Expires time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+}
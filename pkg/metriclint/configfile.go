@@ -0,0 +1,96 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads and decodes the YAML or JSON config file at path, see ParseConfig.
+// Errors are prefixed with the path. The path of the baseline is made relative to the config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if config.Baseline != "" && !filepath.IsAbs(config.Baseline) {
+		config.Baseline = filepath.Join(filepath.Dir(path), config.Baseline)
+	}
+
+	return config, nil
+}
+
+// ParseConfig decodes a YAML config. JSON is accepted as well, being a subset of YAML.
+// Unknown fields are rejected so that typos don't silently disable a rule.
+// The positions of the entries are kept, so that Validate can locate its errors.
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+	config.positions = map[string]ConfigPosition{}
+	collectPositions(&root, "", config.positions)
+
+	return config, nil
+}
+
+// collectPositions records the position of every node below n by its path.
+func collectPositions(n *yaml.Node, path string, positions map[string]ConfigPosition) {
+	if path != "" {
+		positions[path] = ConfigPosition{Line: n.Line, Column: n.Column}
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			collectPositions(c, path, positions)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			collectPositions(c, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			collectPositions(n.Content[i+1], key, positions)
+		}
+	}
+}
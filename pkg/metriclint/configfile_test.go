@@ -0,0 +1,223 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	data := []byte(`
+rules:
+  - name: component-label
+    target: label
+    pattern: component
+    mode: required
+    severity: warning
+`)
+	config, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := DeclarativeRule{Name: "component-label", Target: "label", Pattern: "component", Mode: "required", Severity: SeverityWarning}
+	if len(config.Rules) != 1 || config.Rules[0] != expected {
+		t.Errorf("expected: %v, but got: %v", expected, config.Rules)
+	}
+
+	if _, err := ParseConfig([]byte(`{"rules": [{"name": "r", "target": "name", "pattren": "x"}]}`)); err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+
+	config, err = ParseConfig([]byte(`{"scrapeInterval": "1m"}`))
+	if err != nil || config.ScrapeInterval != time.Minute {
+		t.Errorf("expected: a 1m scrape interval, but got: %v, %v", config, err)
+	}
+
+	config, err = ParseConfig(nil)
+	if err != nil || len(config.Rules) != 0 {
+		t.Errorf("expected empty config, but got: %v, %v", config, err)
+	}
+}
+
+func TestParseConfigPositions(t *testing.T) {
+	data := []byte(`enable:
+  - unit-suffix
+disable:
+  - unit-suffix
+rules:
+  - name: broken
+    target: name
+    pattern: "["
+    mode: required
+  - name: no-mode
+    target: name
+    pattern: x
+`)
+	config, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = NewLinterFromConfig(config)
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("expected ConfigErrors, but got: %v", err)
+	}
+
+	expected := []string{
+		"8:14: rules[0].pattern: malformed regular expression: error parsing regexp: missing closing ]: `[)$`",
+		// The missing mode is located at its rule.
+		`10:5: rules[1].mode: unknown mode ""`,
+		`4:5: disable[0]: rule "unit-suffix" is both enabled and disabled`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, errs)
+	}
+	for i := range expected {
+		if errs[i].Error() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], errs[i].Error())
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var tests = []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "yaml",
+			file: "metriclint.yaml",
+			data: `enable:
+  - unit-suffix
+severities:
+  unit-suffix: error
+ignore:
+  - legacy_.*
+units:
+  kibibytes: bytes
+  widgets: widgets
+`,
+		},
+		{
+			name: "json",
+			file: "metriclint.json",
+			data: `{"enable": ["unit-suffix"], "severities": {"unit-suffix": "error"}, "ignore": ["legacy_.*"],
+"units": {"kibibytes": "bytes", "widgets": "widgets"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.file)
+			if err := ioutil.WriteFile(path, []byte(tc.data), 0644); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			config, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			linter, err := NewLinterFromConfig(config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result := linter.Lint(MetricSpec{Name: "queue_capacity_kibibytes", Help: "Capacity of the queue.", Type: MetricTypeGauge})
+			if len(result.Findings) != 1 || result.Findings[0].ID != RuleNonBaseUnit {
+				t.Errorf("expected: %s, but got: %v", RuleNonBaseUnit, result.Findings)
+			}
+			result = linter.Lint(MetricSpec{Name: "queue_depth", Help: "Depth of the queue.", Type: MetricTypeGauge})
+			if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityError {
+				t.Errorf("expected: %s %s, but got: %v", RuleUnitSuffix, SeverityError, result.Findings)
+			}
+			if result := linter.Lint(MetricSpec{Name: "queue_widgets", Help: "Widgets in the queue.", Type: MetricTypeGauge}); len(result.Findings) != 0 {
+				t.Errorf("expected no issue, but got: %v", result.Findings)
+			}
+			if result := linter.Lint(MetricSpec{Name: "legacy_queue_depth", Type: MetricTypeGauge}); len(result.Findings) != 0 {
+				t.Errorf("expected no issue, but got: %v", result.Findings)
+			}
+		})
+	}
+
+	missing := filepath.Join(dir, "missing.yaml")
+	if _, err := LoadConfig(missing); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	broken := filepath.Join(dir, "broken.yaml")
+	if err := ioutil.WriteFile(broken, []byte("enable: {"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := LoadConfig(broken); err == nil || !strings.HasPrefix(err.Error(), broken+": ") {
+		t.Errorf("expected error prefixed with %s, but got: %v", broken, err)
+	}
+}
+
+func TestLoadConfigBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	baseline := &Baseline{}
+	baseline.Add("lint_test", LintErrMsgNoHelp, BaselineActionSuppress)
+	baseline.Add("lint_test", LintErrMsgCounterShouldHaveTotalSuffix, BaselineActionIgnore)
+	if err := baseline.Save(filepath.Join(dir, "baseline.json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "metriclint.yaml")
+	if err := ioutil.WriteFile(path, []byte("baseline: baseline.json\n"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	linter, err := NewLinterFromConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the suppressed issue is dropped, the ignored one keeps being reported.
+	result := linter.Lint(MetricSpec{Name: "lint_test", Type: MetricTypeCounter})
+	if len(result.Findings) != 1 || result.Findings[0].Message != LintErrMsgCounterShouldHaveTotalSuffix {
+		t.Errorf("expected: %s, but got: %v", LintErrMsgCounterShouldHaveTotalSuffix, result.Findings)
+	}
+
+	config.Baseline = filepath.Join(dir, "missing.json")
+	if _, err := NewLinterFromConfig(config); err == nil {
+		t.Errorf("expected an error for a missing baseline")
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unitRE matches a single lowercase segment of a metric name, the form of the units.
+var unitRE = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// ConfigPosition is a location in a config file, 1-based.
+type ConfigPosition struct {
+	Line   int
+	Column int
+}
+
+// ConfigError is a problem of a config entry.
+type ConfigError struct {
+	// Path of the entry, e.g. "rules[0].pattern".
+	Path string
+
+	// Position of the entry, zero if the config wasn't parsed from a file.
+	Position ConfigPosition
+
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	if e.Position.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+
+	return fmt.Sprintf("%d:%d: %s: %s", e.Position.Line, e.Position.Column, e.Path, e.Message)
+}
+
+// ConfigErrors are all the problems of a config.
+type ConfigErrors []ConfigError
+
+func (errs ConfigErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return "invalid config:\n" + strings.Join(messages, "\n")
+}
+
+// Validate reports unknown rule IDs, severities, profiles and policy bundles, malformed regular expressions, invalid
+// declarative rules, including unnamed ones and names taken by another rule, rules both enabled and disabled, units
+// not mapped to a base unit, a negative scrape interval, invalid label synonyms and duplicate tombstones. It returns ConfigErrors locating every problem, or nil if the
+// config is valid.
+func (c *Config) Validate() error {
+	var errs ConfigErrors
+	report := func(path, format string, args ...interface{}) {
+		errs = append(errs, ConfigError{Path: path, Position: c.position(path), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if _, ok := profiles[c.Profile]; c.Profile != "" && !ok {
+		report("profile", "unknown profile %q", c.Profile)
+	}
+
+	known := map[string]bool{}
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+	declared := map[string]bool{}
+	for i, rule := range c.Rules {
+		if _, err := rule.compile(); err != nil {
+			fieldErr := err.(*ruleFieldError)
+			report(fmt.Sprintf("rules[%d].%s", i, fieldErr.field), "%s", fieldErr.message)
+		} else if declared[rule.Name] {
+			report(fmt.Sprintf("rules[%d].name", i), "rule %q is defined twice", rule.Name)
+		}
+		known[rule.Name] = true
+		declared[rule.Name] = true
+	}
+	for i, name := range c.Bundles {
+		bundle, ok := lookupPolicyBundle(name)
+		if !ok {
+			report(fmt.Sprintf("bundles[%d]", i), "unknown policy bundle %q", name)
+			continue
+		}
+		for _, rule := range bundle.Config.Rules {
+			if declared[rule.Name] {
+				report(fmt.Sprintf("bundles[%d]", i), "rule %q of the bundle is defined twice", rule.Name)
+			}
+			known[rule.Name] = true
+			declared[rule.Name] = true
+		}
+	}
+
+	enabled := map[string]bool{}
+	for i, id := range c.Enable {
+		if !known[id] {
+			report(fmt.Sprintf("enable[%d]", i), "unknown rule %q", id)
+		}
+		enabled[id] = true
+	}
+	for i, id := range c.Disable {
+		if !known[id] {
+			report(fmt.Sprintf("disable[%d]", i), "unknown rule %q", id)
+		}
+		if enabled[id] {
+			report(fmt.Sprintf("disable[%d]", i), "rule %q is both enabled and disabled", id)
+		}
+	}
+
+	// Map entries are reported in key order, to keep the errors stable.
+	var ids []string
+	for id := range c.Severities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		path := "severities." + id
+		if !known[id] {
+			report(path, "unknown rule %q", id)
+		}
+		if err := c.Severities[id].validate(); err != nil {
+			report(path, "%v", err)
+		}
+	}
+
+	if c.FailOn != "" {
+		if err := c.FailOn.validate(); err != nil {
+			report("failOn", "%v", err)
+		}
+	}
+
+	for i, pattern := range c.Ignore {
+		if _, err := regexp.Compile(pattern); err != nil {
+			report(fmt.Sprintf("ignore[%d]", i), "malformed regular expression: %v", err)
+		}
+	}
+
+	for i, s := range c.Suppressions {
+		path := fmt.Sprintf("suppressions[%d]", i)
+		if _, err := regexp.Compile(s.Metric); err != nil {
+			report(path+".metric", "malformed regular expression: %v", err)
+		}
+		for j, id := range s.Rules {
+			if !known[id] {
+				report(fmt.Sprintf("%s.rules[%d]", path, j), "unknown rule %q", id)
+			}
+		}
+	}
+
+	if c.Namespace != nil {
+		if _, err := c.Namespace.compile(); err != nil {
+			report("namespace.pattern", "%v", err)
+		}
+	}
+
+	var customUnits []string
+	for unit := range c.Units {
+		customUnits = append(customUnits, unit)
+	}
+	sort.Strings(customUnits)
+	for _, unit := range customUnits {
+		path := "units." + unit
+		base := c.Units[unit]
+		switch {
+		case !unitRE.MatchString(unit):
+			report(path, "unit %q should be a lowercase name segment", unit)
+		case base != unit && units[base] != base && c.Units[base] != base:
+			report(path, "unit %q maps to %q, which is not a base unit", unit, base)
+		}
+	}
+
+	if c.ScrapeInterval < 0 {
+		report("scrapeInterval", "negative scrape interval %s", c.ScrapeInterval)
+	}
+
+	grouped := map[string]bool{}
+	for i, names := range c.LabelSynonyms {
+		if len(names) < 2 {
+			report(fmt.Sprintf("labelSynonyms[%d]", i), "synonym group with less than two label names")
+		}
+		for j, name := range names {
+			path := fmt.Sprintf("labelSynonyms[%d][%d]", i, j)
+			switch {
+			case !labelNameRE.MatchString(name):
+				report(path, "invalid label name %q", name)
+			case grouped[name]:
+				report(path, "label %q is in several synonym groups", name)
+			}
+			grouped[name] = true
+		}
+	}
+
+	tombstoned := map[string]bool{}
+	for i, t := range c.Tombstones {
+		path := fmt.Sprintf("tombstones[%d]", i)
+		switch {
+		case t.Metric == "":
+			report(path+".metric", "missing metric name")
+		case tombstoned[t.Metric]:
+			report(path+".metric", "metric %q is tombstoned twice", t.Metric)
+		case t.Metric == t.Replacement:
+			report(path+".replacement", "metric %q replaces itself", t.Metric)
+		}
+		tombstoned[t.Metric] = true
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Warnings reports the problems which don't make the config invalid, the expired suppressions. An expired
+// suppression no longer applies, so the issues it silenced are reported again.
+func (c *Config) Warnings() ConfigErrors {
+	return c.warnings(time.Now())
+}
+
+func (c *Config) warnings(now time.Time) (warnings ConfigErrors) {
+	for i, s := range c.Suppressions {
+		if !s.Expires.IsZero() && s.Expires.Before(now) {
+			path := fmt.Sprintf("suppressions[%d].expires", i)
+			warnings = append(warnings, ConfigError{
+				Path:     path,
+				Position: c.position(path),
+				Message:  fmt.Sprintf("suppression expired on %s", s.Expires.Format("2006-01-02")),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// position returns the position of the entry at path, or of its closest parent if the entry
+// is missing from the file.
+func (c *Config) position(path string) ConfigPosition {
+	for path != "" {
+		if p, ok := c.positions[path]; ok {
+			return p
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+
+	return ConfigPosition{}
+}
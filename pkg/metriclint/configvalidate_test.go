@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name     string
+		config   Config
+		expected []string
+	}{
+		{
+			name: "valid",
+			config: Config{
+				Enable:       []string{"unit-suffix", "component-label"},
+				Disable:      []string{"help-missing"},
+				Suppressions: []Suppression{{Metric: "legacy_.*", Rules: []string{"counter-total-suffix"}, Expires: now.Add(time.Hour)}},
+				Rules:        []DeclarativeRule{{Name: "component-label", Target: DeclarativeTargetLabel, Pattern: "component", Mode: DeclarativeModeRequired}},
+			},
+		},
+		{
+			name: "all problems",
+			config: Config{
+				Enable:  []string{"unit-sufix", "help-missing"},
+				Disable: []string{"help-missing"},
+				Suppressions: []Suppression{
+					{Metric: "legacy_(", Rules: []string{"ML007"}, Expires: now.Add(-24 * time.Hour)},
+				},
+				Rules: []DeclarativeRule{{Name: "broken", Target: DeclarativeTargetName, Pattern: "[", Mode: DeclarativeModeRequired}},
+			},
+			expected: []string{
+				"rules[0].pattern: malformed regular expression: error parsing regexp: missing closing ]: `[)$`",
+				"enable[0]: unknown rule \"unit-sufix\"",
+				"disable[0]: rule \"help-missing\" is both enabled and disabled",
+				"suppressions[0].metric: malformed regular expression: error parsing regexp: missing closing ): `legacy_(`",
+				"suppressions[0].rules[0]: unknown rule \"ML007\"",
+			},
+		},
+		{
+			name: "invalid severities, ignores and units",
+			config: Config{
+				Severities: map[string]Severity{"help-missing": "fatal", "unit-sufix": SeverityError, "unit-suffix": SeverityError},
+				Ignore:     []string{"legacy_.*", "legacy_("},
+				Units:      map[string]string{"kibibytes": "bytes", "percent": "ratio", "Requests": "requests", "ratio": "ratio"},
+			},
+			expected: []string{
+				"severities.help-missing: unknown severity \"fatal\"",
+				"severities.unit-sufix: unknown rule \"unit-sufix\"",
+				"ignore[1]: malformed regular expression: error parsing regexp: missing closing ): `legacy_(`",
+				"units.Requests: unit \"Requests\" should be a lowercase name segment",
+			},
+		},
+		{
+			name: "invalid rule names",
+			config: Config{
+				Rules: []DeclarativeRule{
+					{Target: DeclarativeTargetName, Pattern: "team_.*", Mode: DeclarativeModeRequired},
+					{Name: "help-missing", Target: DeclarativeTargetHelp, Pattern: ".+", Mode: DeclarativeModeRequired},
+					{Name: "team-prefix", Target: DeclarativeTargetName, Pattern: "team_.*", Mode: DeclarativeModeRequired},
+					{Name: "team-prefix", Target: DeclarativeTargetName, Pattern: "org_.*", Mode: DeclarativeModeRequired},
+				},
+			},
+			expected: []string{
+				"rules[0].name: rule without name",
+				`rules[1].name: name "help-missing" is taken by a built-in rule`,
+				`rules[3].name: rule "team-prefix" is defined twice`,
+			},
+		},
+		{
+			name: "invalid label synonyms",
+			config: Config{
+				LabelSynonyms: [][]string{{"tenant", "customer"}, {"region"}, {"zone", "customer", "availability-zone"}},
+			},
+			expected: []string{
+				"labelSynonyms[1]: synonym group with less than two label names",
+				`labelSynonyms[2][1]: label "customer" is in several synonym groups`,
+				`labelSynonyms[2][2]: invalid label name "availability-zone"`,
+			},
+		},
+		{
+			name:   "invalid namespace policy",
+			config: Config{Namespace: &NamespacePolicy{Pattern: "kube("}},
+			expected: []string{
+				"namespace.pattern: malformed regular expression: error parsing regexp: missing closing ): `^(?:kube()$`",
+			},
+		},
+		{
+			name:     "negative scrape interval",
+			config:   Config{ScrapeInterval: -time.Second},
+			expected: []string{`scrapeInterval: negative scrape interval -1s`},
+		},
+		{
+			name:     "invalid fail on",
+			config:   Config{FailOn: "fatal"},
+			expected: []string{`failOn: unknown severity "fatal"`},
+		},
+		{
+			name: "invalid tombstones",
+			config: Config{
+				Tombstones: []Tombstone{
+					{Metric: "legacy_requests", Replacement: "http_requests_total"},
+					{Metric: "legacy_requests"},
+					{Replacement: "http_requests_total"},
+					{Metric: "http_requests_total", Replacement: "http_requests_total"},
+				},
+			},
+			expected: []string{
+				"tombstones[1].metric: metric \"legacy_requests\" is tombstoned twice",
+				"tombstones[2].metric: missing metric name",
+				"tombstones[3].replacement: metric \"http_requests_total\" replaces itself",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			errs, ok := err.(ConfigErrors)
+			if !ok || len(errs) != len(tc.expected) {
+				t.Fatalf("expected: %v, but got: %v", tc.expected, err)
+			}
+			for i := range tc.expected {
+				if errs[i].Error() != tc.expected[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expected[i], errs[i].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestConfigWarnings(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		Suppressions: []Suppression{
+			{Metric: "legacy_.*", Rules: []string{"counter-total-suffix"}, Expires: now.Add(-24 * time.Hour)},
+			{Metric: "old_.*", Rules: []string{"counter-total-suffix"}, Expires: now.Add(time.Hour)},
+			{Metric: "older_.*", Rules: []string{"counter-total-suffix"}},
+		},
+	}
+
+	// An expired suppression doesn't make the config invalid.
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "suppressions[0].expires: suppression expired on 2020-05-31"
+	warnings := config.warnings(now)
+	if len(warnings) != 1 || warnings[0].Error() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, warnings)
+	}
+}
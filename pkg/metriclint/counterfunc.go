@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LintErrMsgCounterFuncDecreasing = `counter func name segment %q suggests a value which can decrease, use a GaugeFunc`
+)
+
+// DefaultDecreasingSegments are name segments of values which go down as well as up, such as the current
+// size of a queue, which a CounterFunc must not report.
+var DefaultDecreasingSegments = []string{
+	"active",
+	"available",
+	"current",
+	"free",
+	"inflight",
+	"last",
+	"length",
+	"pending",
+	"queued",
+	"size",
+	"temperature",
+	"usage",
+	"used",
+}
+
+// CounterFuncRule flags CounterFunc names which suggest a value that can decrease. The function of a
+// CounterFunc must never return less than before, rate() treats every drop as a counter reset.
+type CounterFuncRule struct {
+	// Segments which suggest a decreasing value, DefaultDecreasingSegments if nil.
+	Segments []string
+}
+
+// Lint checks the segments of a CounterFunc name.
+func (r CounterFuncRule) Lint(name string) (issues []string) {
+	segments := r.Segments
+	if segments == nil {
+		segments = DefaultDecreasingSegments
+	}
+
+	for _, segment := range strings.Split(strings.ToLower(name), "_") {
+		for _, s := range segments {
+			if segment == s {
+				issues = append(issues, fmt.Sprintf(LintErrMsgCounterFuncDecreasing, segment))
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// LintCounterFunc lints a counter whose value is computed by a function, like Lint, additionally
+// reporting names which suggest a value that can decrease.
+func (l *Linter) LintCounterFunc(spec MetricSpec) *LintResult {
+	spec.Type = MetricTypeCounter
+	result := LintSpec(spec)
+	result.AddRuleMessages(RuleCounterFuncDecreasing, CounterFuncRule{}.Lint(spec.FQName())...)
+	l.lintExtra(spec, result)
+
+	return result
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCounterFuncRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           CounterFuncRule
+		metric         string
+		expectedResult string
+	}{
+		{
+			name:   "monotonic value",
+			metric: "lint_process_cpu_seconds_total",
+		},
+		{
+			name:           "current value",
+			metric:         "lint_queue_length_total",
+			expectedResult: fmt.Sprintf(LintErrMsgCounterFuncDecreasing, "length"),
+		},
+		{
+			name:   "several segments",
+			metric: "lint_active_pending_jobs_total",
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgCounterFuncDecreasing, "active"),
+				fmt.Sprintf(LintErrMsgCounterFuncDecreasing, "pending"),
+			}, ","),
+		},
+		{
+			name:           "custom segments",
+			rule:           CounterFuncRule{Segments: []string{"open"}},
+			metric:         "lint_open_length_total",
+			expectedResult: fmt.Sprintf(LintErrMsgCounterFuncDecreasing, "open"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(tc.rule.Lint(tc.metric), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestLinterLintCounterFunc(t *testing.T) {
+	spec := MetricSpec{Name: "lint_queue_length_total", Help: "Current length of the queue.", Type: MetricTypeGauge}
+
+	result := NewLinter().LintCounterFunc(spec)
+	if len(result.Findings) != 1 || result.Findings[0].ID != RuleCounterFuncDecreasing {
+		t.Errorf("expected: %s, but got: %v", RuleCounterFuncDecreasing, result.Findings)
+	}
+
+	result = NewLinter(DisableRules(RuleCounterFuncDecreasing)).LintCounterFunc(spec)
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+}
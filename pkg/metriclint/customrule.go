@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "fmt"
+
+// Rule is a rule defined outside of this package, such as an organization-specific convention
+// requiring a component const label.
+type Rule interface {
+	// Name is the ID of the rule, used to disable and to suppress it like the built-in rules.
+	Name() string
+
+	// Check returns the issues of the metric. Issues without ID get the rule name,
+	// issues without severity are warnings.
+	Check(spec MetricSpec) []Issue
+}
+
+// WithRules registers custom rules, see Linter.RegisterRule. Unlike RegisterRule, the rules
+// can be disabled by the other options.
+func WithRules(rules ...Rule) Option {
+	return func(l *Linter) {
+		for _, r := range rules {
+			l.RegisterRule(r)
+		}
+	}
+}
+
+// RegisterRule adds a custom rule, run by Lint and LintVector after the built-in rules. It must not
+// be called while the linter is in use. It panics if the rule has no name or the name is already
+// taken by a built-in, declarative or custom rule.
+func (l *Linter) RegisterRule(rule Rule) {
+	name := rule.Name()
+	if name == "" {
+		panic("metriclint: custom rule without name")
+	}
+	if l.knows(name) {
+		panic(fmt.Sprintf("metriclint: rule %q registered twice", name))
+	}
+
+	l.custom = append(l.custom, rule)
+}
+
+// checkCustom runs a custom rule, filling the ID and the severity of its issues.
+func checkCustom(rule Rule, spec MetricSpec) []Issue {
+	issues := rule.Check(spec)
+	for i := range issues {
+		if issues[i].ID == "" {
+			issues[i].ID = rule.Name()
+		}
+		if issues[i].Severity == "" {
+			issues[i].Severity = SeverityWarning
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+// componentLabelRule requires a component const label.
+type componentLabelRule struct{}
+
+func (componentLabelRule) Name() string { return "component-label" }
+
+func (componentLabelRule) Check(spec MetricSpec) []Issue {
+	if _, ok := spec.ConstLabels["component"]; ok {
+		return nil
+	}
+
+	return []Issue{{Message: "metric should have a component const label"}}
+}
+
+func TestLinterRegisterRule(t *testing.T) {
+	spec := MetricSpec{Name: "lint_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter}
+
+	linter := NewLinter()
+	linter.RegisterRule(componentLabelRule{})
+
+	result := linter.Lint(spec)
+	expected := Issue{ID: "component-label", Message: "metric should have a component const label", Severity: SeverityWarning}
+	if len(result.Findings) != 1 || result.Findings[0] != expected {
+		t.Errorf("expected: %v, but got: %v", expected, result.Findings)
+	}
+	assertConsistent(t, result)
+
+	spec.ConstLabels = map[string]string{"component": "apiserver"}
+	if result := linter.Lint(spec); len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+
+	disabled := NewLinter(WithRules(componentLabelRule{}), DisableRules("component-label"))
+	if result := disabled.Lint(MetricSpec{Name: "lint_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter}); len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+}
+
+func TestLinterRegisterRuleTwice(t *testing.T) {
+	defer func() {
+		expected := fmt.Sprintf("metriclint: rule %q registered twice", "component-label")
+		if r := recover(); r != expected {
+			t.Errorf("expected: %s, but got: %v", expected, r)
+		}
+	}()
+
+	NewLinter(WithRules(componentLabelRule{}, componentLabelRule{}))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Targets of a DeclarativeRule.
+const (
+	DeclarativeTargetName  = "name"
+	DeclarativeTargetLabel = "label"
+	DeclarativeTargetHelp  = "help"
+)
+
+// Modes of a DeclarativeRule.
+const (
+	DeclarativeModeRequired  = "required"
+	DeclarativeModeForbidden = "forbidden"
+)
+
+const (
+	LintErrMsgDeclarativeRequired  = `%s should match %q`
+	LintErrMsgDeclarativeForbidden = `%s should not match %q`
+)
+
+// DeclarativeRule is a user defined rule matching a regular expression against the metric name,
+// the label names or the help text, so that simple org specific checks need no Go code.
+type DeclarativeRule struct {
+	// Name of the rule, used in error messages.
+	Name string `json:"name" yaml:"name"`
+
+	// What the pattern is matched against: "name", "label" or "help".
+	// A required label pattern is satisfied by any label of the metric, a forbidden one by none.
+	Target string `json:"target" yaml:"target"`
+
+	// Regular expression, anchored to the whole target.
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// Either "required" or "forbidden".
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Message of the issues reported by the rule, a default message is used if empty.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Severity of the issues reported by the rule, SeverityError if empty.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// compiledRule is a DeclarativeRule ready to lint.
+type compiledRule struct {
+	DeclarativeRule
+	re *regexp.Regexp
+}
+
+// compile checks the rule and compiles its pattern. The name identifies the issues of the rule, like
+// the ID of a built-in rule, so it must be set and differ from the IDs of the built-in rules.
+func (r DeclarativeRule) compile() (*compiledRule, error) {
+	if r.Name == "" {
+		return nil, &ruleFieldError{rule: r.Name, field: "name", message: "rule without name"}
+	}
+	if _, ok := RuleByID(r.Name); ok {
+		return nil, &ruleFieldError{rule: r.Name, field: "name", message: fmt.Sprintf("name %q is taken by a built-in rule", r.Name)}
+	}
+	switch r.Target {
+	case DeclarativeTargetName, DeclarativeTargetLabel, DeclarativeTargetHelp:
+	default:
+		return nil, &ruleFieldError{rule: r.Name, field: "target", message: fmt.Sprintf("unknown target %q", r.Target)}
+	}
+	switch r.Mode {
+	case DeclarativeModeRequired, DeclarativeModeForbidden:
+	default:
+		return nil, &ruleFieldError{rule: r.Name, field: "mode", message: fmt.Sprintf("unknown mode %q", r.Mode)}
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityError
+	}
+	if err := r.Severity.validate(); err != nil {
+		return nil, &ruleFieldError{rule: r.Name, field: "severity", message: err.Error()}
+	}
+
+	re, err := regexp.Compile("^(?:" + r.Pattern + ")$")
+	if err != nil {
+		return nil, &ruleFieldError{rule: r.Name, field: "pattern", message: fmt.Sprintf("malformed regular expression: %v", err)}
+	}
+
+	return &compiledRule{DeclarativeRule: r, re: re}, nil
+}
+
+// ruleFieldError is an invalid field of a declarative rule.
+type ruleFieldError struct {
+	rule    string
+	field   string
+	message string
+}
+
+func (e *ruleFieldError) Error() string {
+	return fmt.Sprintf("rule %q: %s", e.rule, e.message)
+}
+
+// info returns the metadata of the rule.
+func (r *compiledRule) info() RuleInfo {
+	description := r.Message
+	if description == "" {
+		format := LintErrMsgDeclarativeRequired
+		if r.Mode == DeclarativeModeForbidden {
+			format = LintErrMsgDeclarativeForbidden
+		}
+		description = fmt.Sprintf(format, r.Target, r.Pattern)
+	}
+
+	return RuleInfo{ID: r.Name, Category: RuleCategoryDeclarative, Description: description}
+}
+
+// Lint returns the issue of the spec violating the rule, if any.
+func (r *compiledRule) Lint(spec MetricSpec) []Issue {
+	var targets []string
+	switch r.Target {
+	case DeclarativeTargetName:
+		targets = []string{spec.FQName()}
+	case DeclarativeTargetHelp:
+		targets = []string{spec.Help}
+	case DeclarativeTargetLabel:
+		targets = append(sortedLabelNames(spec.ConstLabels), spec.VariableLabels...)
+	}
+
+	matched := false
+	for _, t := range targets {
+		if r.re.MatchString(t) {
+			matched = true
+			break
+		}
+	}
+	if matched == (r.Mode == DeclarativeModeRequired) {
+		return nil
+	}
+
+	return []Issue{{ID: r.Name, Message: r.info().Description, Severity: r.Severity}}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeclarativeRules(t *testing.T) {
+	config := &Config{Rules: []DeclarativeRule{
+		{Name: "component-label", Target: DeclarativeTargetLabel, Pattern: "component", Mode: DeclarativeModeRequired, Message: "component const label is required"},
+		{Name: "no-legacy", Target: DeclarativeTargetName, Pattern: "legacy_.*", Mode: DeclarativeModeForbidden, Severity: SeverityWarning},
+		{Name: "help-sentence", Target: DeclarativeTargetHelp, Pattern: "[A-Z].*\\.", Mode: DeclarativeModeRequired},
+	}}
+	linter, err := NewLinterFromConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		spec     MetricSpec
+		expected []Issue
+	}{
+		{
+			name: "compliant",
+			spec: MetricSpec{Name: "lint_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter,
+				ConstLabels: map[string]string{"component": "apiserver"}},
+		},
+		{
+			name: "all rules violated",
+			spec: MetricSpec{Name: "legacy_requests_total", Help: "total number of requests", Type: MetricTypeCounter,
+				VariableLabels: []string{"code"}},
+			expected: []Issue{
+				{ID: "component-label", Message: "component const label is required", Severity: SeverityError},
+				{ID: "no-legacy", Message: fmt.Sprintf(LintErrMsgDeclarativeForbidden, "name", "legacy_.*"), Severity: SeverityWarning},
+				{ID: "help-sentence", Message: fmt.Sprintf(LintErrMsgDeclarativeRequired, "help", "[A-Z].*\\."), Severity: SeverityError},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := linter.Lint(tc.spec)
+			if len(result.Findings) != len(tc.expected) {
+				t.Fatalf("expected: %v, but got: %v", tc.expected, result.Findings)
+			}
+			for i := range tc.expected {
+				if result.Findings[i] != tc.expected[i] {
+					t.Errorf("expected: %v, but got: %v", tc.expected[i], result.Findings[i])
+				}
+			}
+			assertConsistent(t, result)
+		})
+	}
+}
+
+func TestNewLinterFromConfigInvalidRule(t *testing.T) {
+	var tests = []struct {
+		name string
+		rule DeclarativeRule
+	}{
+		{name: "unknown target", rule: DeclarativeRule{Name: "r", Target: "type", Pattern: "x", Mode: DeclarativeModeRequired}},
+		{name: "unknown mode", rule: DeclarativeRule{Name: "r", Target: DeclarativeTargetName, Pattern: "x", Mode: "maybe"}},
+		{name: "malformed regex", rule: DeclarativeRule{Name: "r", Target: DeclarativeTargetName, Pattern: "(", Mode: DeclarativeModeRequired}},
+		{name: "unknown severity", rule: DeclarativeRule{Name: "r", Target: DeclarativeTargetName, Pattern: "x", Mode: DeclarativeModeRequired, Severity: "fatal"}},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewLinterFromConfig(&Config{Rules: []DeclarativeRule{tc.rule}}); err == nil {
+				t.Errorf("expected error for %+v", tc.rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// digestTopRules is the number of rules included in a report digest.
+const digestTopRules = 3
+
+// IssueCount returns the total number of issues in the report.
+func (r *Report) IssueCount() int {
+	count := 0
+	for _, result := range r.Results {
+		count += len(result.findings())
+	}
+
+	return count
+}
+
+// ErrorCount returns the number of issues with SeverityError in the report.
+func (r *Report) ErrorCount() int {
+	count := 0
+	for _, result := range r.Results {
+		for _, issue := range result.findings() {
+			if issue.Severity == SeverityError {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// JudgeReport judges the results of the report like Judge and records the verdict in the report, so
+// that its output formats render it.
+func (l *Linter) JudgeReport(report *Report) Verdict {
+	v := l.Judge(report.Results)
+	report.Verdict = &v
+
+	return v
+}
+
+// Digest returns a short deterministic summary of the report, suitable for alert annotations.
+// It holds the issue and metric counts, the most frequent rules and a hash of all rule counts,
+// so two reports with the same findings have the same digest regardless of result order.
+func (r *Report) Digest() string {
+	byRule := map[string]int{}
+	metrics := map[string]struct{}{}
+	for _, result := range r.Results {
+		for _, issue := range result.findings() {
+			byRule[issue.ruleKey()]++
+			metrics[result.MetricName] = struct{}{}
+		}
+	}
+
+	rules := sortedKeys(byRule)
+	h := sha256.New()
+	for _, rule := range rules {
+		fmt.Fprintf(h, "%s\x00%d\n", rule, byRule[rule])
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool { return byRule[rules[i]] > byRule[rules[j]] })
+	if len(rules) > digestTopRules {
+		rules = rules[:digestTopRules]
+	}
+	top := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		top = append(top, fmt.Sprintf("%q x%d", rule, byRule[rule]))
+	}
+
+	return fmt.Sprintf("%d issues in %d metrics [%x] top: %s", r.IssueCount(), len(metrics), h.Sum(nil)[:6], strings.Join(top, ", "))
+}
@@ -0,0 +1,57 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReportDigest(t *testing.T) {
+	report := &Report{
+		Results: []*LintResult{
+			{MetricName: "lint_a_total", Issues: []string{LintErrMsgNoHelp, LintErrMsgNameShouldNotHaveAbbr}},
+			{MetricName: "lint_b_total", Issues: []string{LintErrMsgNoHelp}},
+			{MetricName: "lint_c_total"},
+		},
+	}
+	reordered := &Report{Results: []*LintResult{report.Results[1], report.Results[2], report.Results[0]}}
+
+	if report.IssueCount() != 3 {
+		t.Errorf("expected: %d, but got: %d", 3, report.IssueCount())
+	}
+
+	digest := report.Digest()
+	if digest != reordered.Digest() {
+		t.Errorf("expected: %s, but got: %s", digest, reordered.Digest())
+	}
+	if !strings.HasPrefix(digest, "3 issues in 2 metrics [") {
+		t.Errorf("unexpected digest counts: %s", digest)
+	}
+	if !strings.Contains(digest, fmt.Sprintf("top: %q x2, ", LintErrMsgNoHelp)) {
+		t.Errorf("expected most frequent rule first, but got: %s", digest)
+	}
+
+	empty := (&Report{}).Digest()
+	if !strings.HasPrefix(empty, "0 issues in 0 metrics [") || empty == digest {
+		t.Errorf("unexpected empty digest: %s", empty)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const LintErrMsgDuplicateMetric = `metric is declared more than once with a different %s`
+
+// LintDuplicates detects metrics declared more than once under the same fully-qualified name with a
+// different help, type or label names, which a registry rejects and a scrape can't tell apart. results[i]
+// is the result of specs[i], and each declaration differing from the first one of its name gets an issue.
+// Several specs may share a result, e.g. the label sets of a gathered family.
+func LintDuplicates(specs []MetricSpec, results []*LintResult) {
+	first := map[string]MetricSpec{}
+	for i, spec := range specs {
+		name := spec.FQName()
+		if name == "" {
+			continue
+		}
+		declared, ok := first[name]
+		if !ok {
+			first[name] = spec
+			continue
+		}
+
+		var diffs []string
+		if spec.Help != declared.Help {
+			diffs = append(diffs, "help")
+		}
+		if spec.Type != declared.Type {
+			diffs = append(diffs, "type")
+		}
+		if !reflect.DeepEqual(CanonicalLabelNames(spec.ConstLabels, spec.VariableLabels), CanonicalLabelNames(declared.ConstLabels, declared.VariableLabels)) {
+			diffs = append(diffs, "label names")
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf(LintErrMsgDuplicateMetric, strings.Join(diffs, ", "))
+		if !hasIssue(results[i], RuleDuplicateMetric, message) {
+			results[i].AddRuleMessages(RuleDuplicateMetric, message)
+		}
+	}
+}
+
+// LintDuplicates runs the package level LintDuplicates, then drops the issues of the ignored metrics and of
+// the disabled and suppressed rules, and applies the severity overrides, like Lint.
+func (l *Linter) LintDuplicates(specs []MetricSpec, results []*LintResult) {
+	LintDuplicates(specs, results)
+
+	for _, result := range results {
+		if l.ignored(result.MetricName) {
+			result.Findings = nil
+			result.Issues = nil
+			continue
+		}
+		l.applyPolicy(result)
+	}
+}
+
+// hasIssue reports whether the result has an issue of the rule with the message.
+func hasIssue(result *LintResult, id, message string) bool {
+	for _, issue := range result.Findings {
+		if issue.ID == id && issue.Message == message {
+			return true
+		}
+	}
+
+	return false
+}
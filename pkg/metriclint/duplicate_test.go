@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLintDuplicates(t *testing.T) {
+	queue := MetricSpec{Name: "queue_length", Help: "Number of items in the queue.", Type: MetricTypeGauge, VariableLabels: []string{"queue"}}
+
+	var tests = []struct {
+		name     string
+		specs    []MetricSpec
+		expected [][]string
+	}{
+		{
+			name:     "same declaration twice",
+			specs:    []MetricSpec{queue, queue},
+			expected: [][]string{nil, nil},
+		},
+		{
+			name: "different help, type and label names",
+			specs: []MetricSpec{
+				queue,
+				{Name: "queue_length", Help: "Length of the queue.", Type: MetricTypeCounter},
+			},
+			expected: [][]string{nil, {fmt.Sprintf(LintErrMsgDuplicateMetric, "help, type, label names")}},
+		},
+		{
+			name: "same label names as const and variable labels",
+			specs: []MetricSpec{
+				queue,
+				{Name: "queue_length", Help: "Number of items in the queue.", Type: MetricTypeGauge, ConstLabels: map[string]string{"queue": "a"}},
+			},
+			expected: [][]string{nil, nil},
+		},
+		{
+			name: "same fully-qualified name",
+			specs: []MetricSpec{
+				queue,
+				{Subsystem: "queue", Name: "length", Help: "Number of items in the queue.", Type: MetricTypeGauge},
+				{Name: "queue_size", Help: "Number of items in the queue.", Type: MetricTypeGauge},
+			},
+			expected: [][]string{nil, {fmt.Sprintf(LintErrMsgDuplicateMetric, "label names")}, nil},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results := make([]*LintResult, len(tc.specs))
+			for i, spec := range tc.specs {
+				results[i] = &LintResult{MetricName: spec.FQName()}
+			}
+			LintDuplicates(tc.specs, results)
+
+			for i, result := range results {
+				if !reflect.DeepEqual(result.Issues, tc.expected[i]) {
+					t.Errorf("%d: expected: %v, but got: %v", i, tc.expected[i], result.Issues)
+				}
+			}
+		})
+	}
+}
+
+func TestLintAllDuplicates(t *testing.T) {
+	specs := []MetricSpec{
+		{Name: "up", Help: "Whether the target is up.", Type: MetricTypeGauge},
+		{Name: "up", Help: "Whether the target is up.", Type: MetricTypeCounter},
+	}
+
+	results, err := LintAll(specs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := issueIDs(results[1]); !reflect.DeepEqual(ids, []string{RuleCounterTotalSuffix, RuleDuplicateMetric}) {
+		t.Errorf("expected: %v, but got: %v", []string{RuleCounterTotalSuffix, RuleDuplicateMetric}, ids)
+	}
+
+	results, err = NewLinter(DisableRules(RuleDuplicateMetric)).LintAll(specs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := issueIDs(results[1]); !reflect.DeepEqual(ids, []string{RuleCounterTotalSuffix}) {
+		t.Errorf("expected: %v, but got: %v", []string{RuleCounterTotalSuffix}, ids)
+	}
+}
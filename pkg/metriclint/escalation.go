@@ -0,0 +1,85 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "time"
+
+// EscalationPolicy escalates warnings to errors for metrics which keep violating the same rule,
+// so that cleanup is encouraged steadily without breaking builds at once.
+type EscalationPolicy struct {
+	// Store holding the reports of the previous runs.
+	Store Store
+
+	// Number of consecutive stored reports a metric must have violated a rule in before the
+	// warning is escalated.
+	Reports int
+}
+
+// Escalate sets the severity of the warnings in results to SeverityError if the metric had the same
+// issue in each of the last Reports stored reports. Issues are matched by rule ID, or by message for
+// issues without ID. Issues without severity count as warnings.
+// Nothing is escalated while the store holds fewer reports.
+func (p *EscalationPolicy) Escalate(results []*LintResult) error {
+	if p.Reports <= 0 {
+		return nil
+	}
+
+	reports, err := p.Store.LoadSince(time.Time{})
+	if err != nil {
+		return err
+	}
+	if len(reports) < p.Reports {
+		return nil
+	}
+	reports = reports[len(reports)-p.Reports:]
+
+	// Number of the recent reports each metric and issue showed up in.
+	seen := map[string]map[string]int{}
+	for _, report := range reports {
+		for _, result := range report.Results {
+			issues := seen[result.MetricName]
+			if issues == nil {
+				issues = map[string]int{}
+				seen[result.MetricName] = issues
+			}
+			reported := map[string]bool{}
+			for _, issue := range result.findings() {
+				if !reported[issue.ruleKey()] {
+					reported[issue.ruleKey()] = true
+					issues[issue.ruleKey()]++
+				}
+			}
+		}
+	}
+
+	for _, result := range results {
+		for i, issue := range result.Findings {
+			if issue.Severity != "" && issue.Severity != SeverityWarning {
+				continue
+			}
+			if seen[result.MetricName][issue.ruleKey()] == p.Reports {
+				debugf("escalating %q of %s after %d reports", issue.ruleKey(), result.MetricName, p.Reports)
+				result.Findings[i].Severity = SeverityError
+			}
+		}
+	}
+
+	return nil
+}
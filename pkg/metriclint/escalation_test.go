@@ -0,0 +1,95 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEscalationPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint-escalation")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore(dir)
+	policy := &EscalationPolicy{Store: store, Reports: 2}
+
+	base := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	stored := [][]*LintResult{
+		{
+			{MetricName: "lint_a_total", Issues: []string{LintErrMsgNoHelp}},
+		},
+		{
+			{MetricName: "lint_a_total", Issues: []string{LintErrMsgNoHelp, LintErrMsgNameShouldNotHaveAbbr}},
+			{MetricName: "lint_b_total", Issues: []string{LintErrMsgNoHelp}},
+		},
+		{
+			{MetricName: "lint_a_total", Issues: []string{LintErrMsgNoHelp, LintErrMsgNameShouldNotHaveAbbr}},
+		},
+	}
+
+	current := func() []*LintResult {
+		a := &LintResult{MetricName: "lint_a_total"}
+		a.AddIssues(Issue{Message: LintErrMsgNoHelp}, Issue{Message: LintErrMsgNameShouldNotHaveAbbr, Severity: SeverityWarning})
+		b := &LintResult{MetricName: "lint_b_total"}
+		b.AddMessages(LintErrMsgNoHelp)
+		return []*LintResult{a, b}
+	}
+
+	// Too few reports to escalate anything.
+	if err := store.Save(&Report{Timestamp: base, Results: stored[0]}); err != nil {
+		t.Fatalf("failed to save report: %v", err)
+	}
+	results := current()
+	if err := policy.Escalate(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Findings[0].Severity != "" {
+		t.Errorf("expected no escalation, but got: %v", results[0].Findings)
+	}
+
+	for i, r := range stored[1:] {
+		if err := store.Save(&Report{Timestamp: base.Add(time.Duration(i+1) * time.Hour), Results: r}); err != nil {
+			t.Fatalf("failed to save report: %v", err)
+		}
+	}
+	results = current()
+	if err := policy.Escalate(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]Severity{
+		{SeverityError, SeverityError},
+		// lint_b_total is missing from the last report, its streak is broken.
+		{""},
+	}
+	for i := range expected {
+		for j, severity := range expected[i] {
+			if results[i].Findings[j].Severity != severity {
+				t.Errorf("expected: %q, but got: %q for %v", severity, results[i].Findings[j].Severity, results[i].Findings[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LintErrMsgHelpPrefix        = `help text of %s metrics should start with %q`
+	LintErrMsgLabelUndocumented = `help text should mention the labels %s`
+)
+
+// HelpPrefixPolicy maps a metric type to the prefix its help text must start with.
+// Types without an entry are not checked. Prefixes are matched case-insensitively.
+type HelpPrefixPolicy map[MetricType]string
+
+// DefaultHelpPrefixPolicy is a commonly used help text style.
+var DefaultHelpPrefixPolicy = HelpPrefixPolicy{
+	MetricTypeCounter:   "Total number of",
+	MetricTypeHistogram: "Distribution of",
+	MetricTypeSummary:   "Distribution of",
+}
+
+// Lint checks the help text of a metric of the given type.
+// Missing help text is reported by the common rules, so it is not reported again.
+func (p HelpPrefixPolicy) Lint(metricType MetricType, help string) (issues []string) {
+	prefix, ok := p[metricType]
+	if !ok || len(prefix) == 0 || len(help) == 0 {
+		return nil
+	}
+
+	if !strings.HasPrefix(strings.ToLower(help), strings.ToLower(prefix)) {
+		issues = append(issues, fmt.Sprintf(LintErrMsgHelpPrefix, metricType, prefix))
+	}
+
+	return issues
+}
+
+// LabelDocRule is an opt-in rule requiring the help text of a vector to mention its label names,
+// so that dashboard authors know which dimensions exist. A label is mentioned by the word itself,
+// case-insensitively, e.g. "by status code" doesn't mention "status_code".
+type LabelDocRule struct {
+	// Label names which must be mentioned when the vector has them, all of its labels if empty.
+	Labels []string
+}
+
+// Lint checks that the help text mentions the label names of a vector.
+// Missing help text is reported by the common rules, so it is not reported again.
+func (r LabelDocRule) Lint(help string, labelNames []string) (issues []string) {
+	if len(help) == 0 {
+		return nil
+	}
+
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(help), func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_')
+	}) {
+		words[w] = true
+	}
+
+	var undocumented []string
+	for _, ln := range labelNames {
+		if r.required(ln) && !words[strings.ToLower(ln)] {
+			undocumented = append(undocumented, fmt.Sprintf("%q", ln))
+		}
+	}
+	if len(undocumented) > 0 {
+		issues = append(issues, fmt.Sprintf(LintErrMsgLabelUndocumented, strings.Join(undocumented, ", ")))
+	}
+
+	return issues
+}
+
+func (r LabelDocRule) required(labelName string) bool {
+	if len(r.Labels) == 0 {
+		return true
+	}
+	for _, l := range r.Labels {
+		if l == labelName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHelpPrefixPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         HelpPrefixPolicy
+		metricType     MetricType
+		help           string
+		expectedResult string
+	}{
+		{
+			name:       "counter with prefix",
+			policy:     DefaultHelpPrefixPolicy,
+			metricType: MetricTypeCounter,
+			help:       "total number of requests",
+		},
+		{
+			name:           "counter without prefix",
+			policy:         DefaultHelpPrefixPolicy,
+			metricType:     MetricTypeCounter,
+			help:           "requests",
+			expectedResult: fmt.Sprintf(LintErrMsgHelpPrefix, "counter", "Total number of"),
+		},
+		{
+			name:       "type without template",
+			policy:     DefaultHelpPrefixPolicy,
+			metricType: MetricTypeGauge,
+			help:       "anything",
+		},
+		{
+			name:           "custom template",
+			policy:         HelpPrefixPolicy{MetricTypeGauge: "Current"},
+			metricType:     MetricTypeGauge,
+			help:           "Number of in-flight requests",
+			expectedResult: fmt.Sprintf(LintErrMsgHelpPrefix, "gauge", "Current"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.policy.Lint(tc.metricType, tc.help), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLabelDocRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           LabelDocRule
+		help           string
+		labelNames     []string
+		expectedResult string
+	}{
+		{
+			name:       "all labels mentioned",
+			help:       "Total number of requests by code and method.",
+			labelNames: []string{"code", "method"},
+		},
+		{
+			name:       "mention is case insensitive",
+			help:       "Requests by Code.",
+			labelNames: []string{"code"},
+		},
+		{
+			name:           "undocumented labels",
+			help:           "Total number of requests by code.",
+			labelNames:     []string{"code", "method", "status_code"},
+			expectedResult: fmt.Sprintf(LintErrMsgLabelUndocumented, `"method", "status_code"`),
+		},
+		{
+			name:           "partial word is not a mention",
+			help:           "Total number of requests by status code.",
+			labelNames:     []string{"status_code"},
+			expectedResult: fmt.Sprintf(LintErrMsgLabelUndocumented, `"status_code"`),
+		},
+		{
+			name:       "only configured labels are required",
+			rule:       LabelDocRule{Labels: []string{"code"}},
+			help:       "Total number of requests by code.",
+			labelNames: []string{"code", "method"},
+		},
+		{
+			name:       "missing help is not reported",
+			labelNames: []string{"code"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.rule.Lint(tc.help, tc.labelNames), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
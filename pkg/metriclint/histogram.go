@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	LintErrMsgNativeHistogramBucketFactor          = `native histogram bucket factor %v should be greater than 1, native histograms are disabled otherwise`
+	LintErrMsgNativeHistogramNegativeZeroThreshold = `native histogram zero threshold %v should not be negative`
+	LintErrMsgNativeHistogramMaxZeroThreshold      = `native histogram max zero threshold %v should not be lower than zero threshold %v`
+	LintErrMsgNativeHistogramMinResetDuration      = `native histogram min reset duration %v should not be shorter than the scrape interval %v`
+	LintErrMsgBucketsNotIncreasing                 = `histogram bucket %v should be greater than the previous bucket %v`
+	LintErrMsgBucketsEmpty                         = `histogram buckets should not be empty, the default buckets are used instead`
+	LintErrMsgBucketsSingle                        = `histogram should have more than the single bucket %v`
+	LintErrMsgBucketsInf                           = `histogram buckets should not include +Inf, it's always added`
+	LintErrMsgBucketsTooMany                       = `histogram has %d buckets, more than %d, every bucket is a series`
+	LintErrMsgBucketScaleSeconds                   = `histogram buckets start at %v, which looks like milliseconds rather than seconds`
+	LintErrMsgBucketScaleBytes                     = `histogram bucket %v is a fraction of a byte, which looks like a larger unit than bytes`
+	LintErrMsgBucketScaleRatio                     = `histogram bucket %v is greater than 1, which looks like a percentage rather than a ratio`
+)
+
+// DefaultMaxHistogramBuckets is the number of buckets above which BucketsRule reports a histogram.
+const DefaultMaxHistogramBuckets = 50
+
+// NativeHistogramZeroThresholdZero is the zero threshold value which client_golang uses to
+// request a zero threshold of exactly zero, it's the only valid negative threshold.
+const NativeHistogramZeroThresholdZero = -1
+
+// DefaultScrapeInterval is the scrape interval assumed when none is configured.
+const DefaultScrapeInterval = 15 * time.Second
+
+// NativeHistogramSpec holds the native histogram options of a histogram.
+type NativeHistogramSpec struct {
+	BucketFactor     float64
+	ZeroThreshold    float64
+	MaxZeroThreshold float64
+	MaxBucketNumber  uint32
+	MinResetDuration time.Duration
+}
+
+// NativeHistogramRule checks native histogram options, whose misconfigurations silently degrade accuracy.
+type NativeHistogramRule struct {
+	// The scrape interval of the histogram, DefaultScrapeInterval if zero.
+	// Resetting the histogram more often than it's scraped loses observations.
+	ScrapeInterval time.Duration
+}
+
+// Lint checks the native histogram options of the spec, if any.
+func (r NativeHistogramRule) Lint(spec MetricSpec) (issues []string) {
+	return IssueMessages(r.issues(spec))
+}
+
+// issues checks the native histogram options of the spec and reports the issues with their rule IDs.
+func (r NativeHistogramRule) issues(spec MetricSpec) (issues []Issue) {
+	nh := spec.NativeHistogram
+	if nh == nil {
+		return nil
+	}
+
+	if nh.BucketFactor != 0 && nh.BucketFactor <= 1 {
+		issues = append(issues, ruleIssues(RuleNativeHistogramBucketFactor, []string{fmt.Sprintf(LintErrMsgNativeHistogramBucketFactor, nh.BucketFactor)})...)
+	}
+
+	if nh.ZeroThreshold < 0 && nh.ZeroThreshold != NativeHistogramZeroThresholdZero {
+		issues = append(issues, ruleIssues(RuleNativeHistogramZeroThreshold, []string{fmt.Sprintf(LintErrMsgNativeHistogramNegativeZeroThreshold, nh.ZeroThreshold)})...)
+	}
+
+	if nh.MaxZeroThreshold > 0 && nh.MaxZeroThreshold < nh.ZeroThreshold {
+		issues = append(issues, ruleIssues(RuleNativeHistogramMaxZeroThreshold, []string{fmt.Sprintf(LintErrMsgNativeHistogramMaxZeroThreshold, nh.MaxZeroThreshold, nh.ZeroThreshold)})...)
+	}
+
+	issues = append(issues, ruleIssues(RuleNativeHistogramMinResetDuration, r.lintMinResetDuration(spec))...)
+
+	return issues
+}
+
+// lintMinResetDuration checks that the native histogram of the spec, if any, isn't reset more often than it's scraped.
+func (r NativeHistogramRule) lintMinResetDuration(spec MetricSpec) []string {
+	nh := spec.NativeHistogram
+	if nh == nil {
+		return nil
+	}
+
+	scrapeInterval := r.ScrapeInterval
+	if scrapeInterval == 0 {
+		scrapeInterval = DefaultScrapeInterval
+	}
+	if nh.MinResetDuration > 0 && nh.MinResetDuration < scrapeInterval {
+		return []string{fmt.Sprintf(LintErrMsgNativeHistogramMinResetDuration, nh.MinResetDuration, scrapeInterval)}
+	}
+
+	return nil
+}
+
+// BucketsRule checks the explicit buckets of a histogram. Buckets which are not increasing panic at
+// registration, the other problems are silently tolerated by client_golang.
+type BucketsRule struct {
+	// The number of buckets above which the histogram is reported, DefaultMaxHistogramBuckets if zero.
+	MaxBuckets int
+}
+
+// Lint checks the buckets of the spec, if any.
+func (r BucketsRule) Lint(spec MetricSpec) (issues []string) {
+	return IssueMessages(r.issues(spec))
+}
+
+// issues checks the buckets of the spec and reports the issues with their rule IDs.
+// Nil buckets are the default ones and are not checked.
+func (r BucketsRule) issues(spec MetricSpec) (issues []Issue) {
+	buckets := spec.Buckets
+	if buckets == nil {
+		return nil
+	}
+	if len(buckets) == 0 {
+		return ruleIssues(RuleHistogramBuckets, []string{LintErrMsgBucketsEmpty})
+	}
+
+	var order []string
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			order = append(order, fmt.Sprintf(LintErrMsgBucketsNotIncreasing, buckets[i], buckets[i-1]))
+		}
+	}
+	issues = append(issues, ruleIssues(RuleHistogramBucketsOrder, order)...)
+
+	var messages []string
+	if math.IsInf(buckets[len(buckets)-1], 1) {
+		messages = append(messages, LintErrMsgBucketsInf)
+		buckets = buckets[:len(buckets)-1]
+	}
+
+	maxBuckets := r.MaxBuckets
+	if maxBuckets == 0 {
+		maxBuckets = DefaultMaxHistogramBuckets
+	}
+	switch {
+	case len(buckets) == 1:
+		messages = append(messages, fmt.Sprintf(LintErrMsgBucketsSingle, buckets[0]))
+	case len(buckets) > maxBuckets:
+		messages = append(messages, fmt.Sprintf(LintErrMsgBucketsTooMany, len(buckets), maxBuckets))
+	}
+
+	return append(issues, ruleIssues(RuleHistogramBuckets, messages)...)
+}
+
+// lintBucketScale checks that the buckets of a histogram fit the unit of its name, or its declared unit:
+// buckets of seconds starting at 10 or more look like milliseconds, fractions of bytes look like a larger
+// unit and ratios above 1 look like percentages.
+func lintBucketScale(name, unit string, buckets []float64) (issues []string) {
+	if unit == "" {
+		unit = name[strings.LastIndex(name, "_")+1:]
+	}
+
+	var finite []float64
+	for _, b := range buckets {
+		if !math.IsInf(b, 0) && !math.IsNaN(b) {
+			finite = append(finite, b)
+		}
+	}
+	if len(finite) == 0 {
+		return nil
+	}
+
+	switch unit {
+	case "seconds":
+		min := finite[0]
+		for _, b := range finite[1:] {
+			min = math.Min(min, b)
+		}
+		if min >= 10 {
+			issues = append(issues, fmt.Sprintf(LintErrMsgBucketScaleSeconds, min))
+		}
+	case "bytes":
+		for _, b := range finite {
+			if b > 0 && b != math.Trunc(b) {
+				issues = append(issues, fmt.Sprintf(LintErrMsgBucketScaleBytes, b))
+				break
+			}
+		}
+	case "ratio":
+		for _, b := range finite {
+			if b > 1 {
+				issues = append(issues, fmt.Sprintf(LintErrMsgBucketScaleRatio, b))
+				break
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNativeHistogramRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           NativeHistogramRule
+		nh             *NativeHistogramSpec
+		expectedResult string
+	}{
+		{
+			name: "no native histogram",
+		},
+		{
+			name: "valid native histogram",
+			nh:   &NativeHistogramSpec{BucketFactor: 1.1, ZeroThreshold: NativeHistogramZeroThresholdZero, MinResetDuration: time.Hour},
+		},
+		{
+			name:           "bucket factor",
+			nh:             &NativeHistogramSpec{BucketFactor: 0.5},
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramBucketFactor, 0.5),
+		},
+		{
+			name:           "negative zero threshold",
+			nh:             &NativeHistogramSpec{BucketFactor: 1.1, ZeroThreshold: -0.5},
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramNegativeZeroThreshold, -0.5),
+		},
+		{
+			name:           "max zero threshold",
+			nh:             &NativeHistogramSpec{BucketFactor: 1.1, ZeroThreshold: 0.01, MaxZeroThreshold: 0.001},
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramMaxZeroThreshold, 0.001, 0.01),
+		},
+		{
+			name:           "min reset duration",
+			rule:           NativeHistogramRule{ScrapeInterval: time.Minute},
+			nh:             &NativeHistogramSpec{BucketFactor: 1.1, MinResetDuration: 30 * time.Second},
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramMinResetDuration, 30*time.Second, time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			spec := MetricSpec{Name: "lint_test_seconds", Type: MetricTypeHistogram, NativeHistogram: tc.nh}
+			issues := strings.Join(tc.rule.Lint(spec), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLinterScrapeInterval(t *testing.T) {
+	spec := MetricSpec{
+		Name:            "lint_test_seconds",
+		Help:            "this is help message",
+		Type:            MetricTypeHistogram,
+		NativeHistogram: &NativeHistogramSpec{BucketFactor: 1.1, MinResetDuration: 30 * time.Second},
+	}
+
+	fromConfig, err := NewLinterFromConfig(&Config{ScrapeInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		linter         *Linter
+		expectedResult string
+	}{
+		{
+			name:   "default scrape interval",
+			linter: NewLinter(),
+		},
+		{
+			name:           "scrape interval option",
+			linter:         NewLinter(WithScrapeInterval(time.Minute)),
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramMinResetDuration, 30*time.Second, time.Minute),
+		},
+		{
+			name:           "scrape interval of the config",
+			linter:         fromConfig,
+			expectedResult: fmt.Sprintf(LintErrMsgNativeHistogramMinResetDuration, 30*time.Second, time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.linter.Lint(spec).Issues, ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestBucketsRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           BucketsRule
+		buckets        []float64
+		expectedResult string
+	}{
+		{
+			name: "default buckets",
+		},
+		{
+			name:    "increasing buckets",
+			buckets: []float64{0.1, 0.5, 1},
+		},
+		{
+			name:           "empty buckets",
+			buckets:        []float64{},
+			expectedResult: LintErrMsgBucketsEmpty,
+		},
+		{
+			name:    "unsorted and duplicate buckets",
+			buckets: []float64{1, 0.5, 0.5, 2},
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgBucketsNotIncreasing, 0.5, 1),
+				fmt.Sprintf(LintErrMsgBucketsNotIncreasing, 0.5, 0.5),
+			}, ","),
+		},
+		{
+			name:           "single bucket",
+			buckets:        []float64{1},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketsSingle, 1),
+		},
+		{
+			name:           "explicit +Inf bucket",
+			buckets:        []float64{0.5, 1, math.Inf(1)},
+			expectedResult: LintErrMsgBucketsInf,
+		},
+		{
+			name:           "too many buckets",
+			rule:           BucketsRule{MaxBuckets: 3},
+			buckets:        []float64{1, 2, 3, 4},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketsTooMany, 4, 3),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			spec := MetricSpec{Name: "lint_test_seconds", Type: MetricTypeHistogram, Buckets: tc.buckets}
+			issues := strings.Join(tc.rule.Lint(spec), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLintBucketScale(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		unit           string
+		buckets        []float64
+		expectedResult string
+	}{
+		{
+			name:    "default buckets",
+			metric:  "lint_request_duration_seconds",
+			buckets: nil,
+		},
+		{
+			name:    "seconds",
+			metric:  "lint_request_duration_seconds",
+			buckets: []float64{0.1, 0.5, 1, 5},
+		},
+		{
+			name:           "milliseconds in seconds",
+			metric:         "lint_request_duration_seconds",
+			buckets:        []float64{100, 500, 1000, math.Inf(1)},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketScaleSeconds, 100),
+		},
+		{
+			name:           "declared unit",
+			metric:         "lint_request_duration",
+			unit:           "seconds",
+			buckets:        []float64{10, 50},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketScaleSeconds, 10),
+		},
+		{
+			name:           "fraction of bytes",
+			metric:         "lint_response_size_bytes",
+			buckets:        []float64{0.5, 1, 2},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketScaleBytes, 0.5),
+		},
+		{
+			name:           "percentage in ratio",
+			metric:         "lint_cache_hit_ratio",
+			buckets:        []float64{0.5, 50, 90},
+			expectedResult: fmt.Sprintf(LintErrMsgBucketScaleRatio, 50),
+		},
+		{
+			name:    "unknown unit",
+			metric:  "lint_batch_size",
+			buckets: []float64{100, 500, 1000},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(lintBucketScale(tc.metric, tc.unit, tc.buckets), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
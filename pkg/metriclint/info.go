@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	LintErrMsgInfoType = `"_info" metrics should be gauges with the constant value 1, not %s`
+	LintErrMsgInfoUnit = `"_info" metrics should not have the unit %q`
+	LintErrMsgInfoName = `gauge with only the identity labels %v should be named with the "_info" suffix`
+)
+
+// DefaultIdentityLabels are label names describing the identity of a build or a component rather than a
+// dimension of a measurement.
+var DefaultIdentityLabels = []string{
+	"branch",
+	"build_date",
+	"build_user",
+	"commit",
+	"goversion",
+	"revision",
+	"version",
+}
+
+// lintInfo checks that metrics with the "_info" suffix are gauges, whose value is always 1, without unit.
+// The value itself is only known to the OpenMetrics rules.
+func lintInfo(name string, metricType MetricType, unit string) (issues []string) {
+	if !strings.HasSuffix(name, "_info") {
+		return nil
+	}
+
+	if metricType != MetricTypeGauge && metricType != MetricTypeUntyped {
+		issues = append(issues, fmt.Sprintf(LintErrMsgInfoType, metricType))
+	}
+	if unit == "" {
+		unit, _, _ = DetectUnit(name)
+	}
+	if unit != "" {
+		issues = append(issues, fmt.Sprintf(LintErrMsgInfoUnit, unit))
+	}
+
+	return issues
+}
+
+// lintInfoName checks that gauges carrying only identity labels, such as the version of a build, are named
+// with the "_info" suffix.
+func lintInfoName(name string, metricType MetricType, constLabels map[string]string, labelNames []string) (issues []string) {
+	if metricType != MetricTypeGauge || strings.HasSuffix(name, "_info") {
+		return nil
+	}
+
+	var labels []string
+	for ln := range constLabels {
+		labels = append(labels, ln)
+	}
+	labels = append(labels, labelNames...)
+	if len(labels) == 0 {
+		return nil
+	}
+	for _, ln := range labels {
+		if !isIdentityLabel(ln) {
+			return nil
+		}
+	}
+	sort.Strings(labels)
+
+	return []string{fmt.Sprintf(LintErrMsgInfoName, labels)}
+}
+
+func isIdentityLabel(name string) bool {
+	for _, ln := range DefaultIdentityLabels {
+		if name == ln {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		metricType     MetricType
+		unit           string
+		expectedResult string
+	}{
+		{
+			name:       "gauge",
+			metric:     "lint_build_info",
+			metricType: MetricTypeGauge,
+		},
+		{
+			name:       "untyped",
+			metric:     "lint_build_info",
+			metricType: MetricTypeUntyped,
+		},
+		{
+			name:           "counter",
+			metric:         "lint_build_info",
+			metricType:     MetricTypeCounter,
+			expectedResult: fmt.Sprintf(LintErrMsgInfoType, MetricTypeCounter),
+		},
+		{
+			name:           "unit in name",
+			metric:         "lint_uptime_seconds_info",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgInfoUnit, "seconds"),
+		},
+		{
+			name:           "declared unit",
+			metric:         "lint_build_info",
+			metricType:     MetricTypeGauge,
+			unit:           "seconds",
+			expectedResult: fmt.Sprintf(LintErrMsgInfoUnit, "seconds"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintInfo(tc.metric, tc.metricType, tc.unit), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestLintInfoName(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           MetricSpec
+		expectedResult string
+	}{
+		{
+			name: "info gauge",
+			spec: MetricSpec{Name: "lint_build_info", Type: MetricTypeGauge, VariableLabels: []string{"version", "revision"}},
+		},
+		{
+			name: "gauge without labels",
+			spec: MetricSpec{Name: "lint_queue_length", Type: MetricTypeGauge},
+		},
+		{
+			name: "gauge with other labels",
+			spec: MetricSpec{Name: "lint_queue_length", Type: MetricTypeGauge, VariableLabels: []string{"version", "queue"}},
+		},
+		{
+			name: "counter with identity labels",
+			spec: MetricSpec{Name: "lint_builds_total", Type: MetricTypeCounter, VariableLabels: []string{"version"}},
+		},
+		{
+			name:           "gauge with identity labels",
+			spec:           MetricSpec{Name: "lint_build", Type: MetricTypeGauge, ConstLabels: map[string]string{"version": "1.0"}, VariableLabels: []string{"revision"}},
+			expectedResult: fmt.Sprintf(LintErrMsgInfoName, []string{"revision", "version"}),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintInfoName(tc.spec.FQName(), tc.spec.Type, tc.spec.ConstLabels, tc.spec.VariableLabels), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
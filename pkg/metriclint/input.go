@@ -0,0 +1,103 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	utf8BOM   = []byte{0xef, 0xbb, 0xbf}
+)
+
+// NewExpositionReader wraps r so that exposition dumps collected on any platform parse the same:
+// gzip and zstd compressed input is decompressed, a leading UTF-8 BOM is dropped and CRLF
+// line endings are turned into LF.
+func NewExpositionReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		debugf("exposition input is zstd compressed")
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(zr.IOReadCloser())
+	case bytes.HasPrefix(magic, gzipMagic):
+		debugf("exposition input is gzip compressed")
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(gr)
+	}
+
+	if bom, _ := br.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		debugf("dropping UTF-8 BOM from exposition input")
+		if _, err := br.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &crlfReader{r: br}, nil
+}
+
+// crlfReader turns "\r\n" into "\n". A lone "\r" is kept as is.
+type crlfReader struct {
+	r *bufio.Reader
+}
+
+func (c *crlfReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+
+		if b == '\r' {
+			if next, err := c.r.Peek(1); err == nil && next[0] == '\n' {
+				continue
+			}
+		}
+
+		p[n] = b
+		n++
+
+		// Don't block on the underlying reader once we have something to return.
+		if c.r.Buffered() == 0 {
+			break
+		}
+	}
+
+	return n, nil
+}
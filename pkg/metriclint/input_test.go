@@ -0,0 +1,114 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/common/expfmt"
+)
+
+const windowsExposition = "\xef\xbb\xbf# HELP lint_test_total this is help message\r\n" +
+	"# TYPE lint_test_total counter\r\n" +
+	"lint_test_total{lname=\"lvalue\"} 1\r\n"
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write([]byte(s)); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func zstdCompressed(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestNewExpositionReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "plain",
+			input: []byte("# HELP lint_test_total this is help message\nlint_test_total 1\n"),
+		},
+		{
+			name:  "bom and crlf",
+			input: []byte(windowsExposition),
+		},
+		{
+			name:  "gzip with bom and crlf",
+			input: gzipped(t, windowsExposition),
+		},
+		{
+			name:  "zstd with bom and crlf",
+			input: zstdCompressed(t, windowsExposition),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewExpositionReader(bytes.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bytes.Contains(data, []byte("\r")) || bytes.HasPrefix(data, utf8BOM) {
+				t.Errorf("input not normalized: %q", data)
+			}
+
+			var parser expfmt.TextParser
+			families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to parse normalized input: %v", err)
+			}
+			if _, ok := families["lint_test_total"]; !ok {
+				t.Errorf("expected lint_test_total in parsed families, but got: %v", families)
+			}
+		})
+	}
+}
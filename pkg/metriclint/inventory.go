@@ -0,0 +1,145 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the encoding of a metric inventory.
+type Format string
+
+const (
+	// FormatJSON is a JSON array of InventoryEntry objects.
+	FormatJSON Format = "json"
+
+	// FormatCSV is a CSV table with a "name,type,help,labels" header, labels are separated by ";".
+	FormatCSV Format = "csv"
+)
+
+// InventoryEntry is the declaration of a metric in an inventory.
+type InventoryEntry struct {
+	Name string `json:"name"`
+
+	// One of "counter", "gauge", "histogram" or "summary".
+	Type string `json:"type"`
+
+	Help string `json:"help"`
+
+	// Variable label names, a non-empty list lints the metric as a vector.
+	Labels []string `json:"labels"`
+}
+
+// LintInventory lints the metric declarations of an inventory, e.g. produced by the build tooling
+// of non-Go services, without requiring them to emit the exposition format first.
+func LintInventory(r io.Reader, format Format) ([]*LintResult, error) {
+	var entries []InventoryEntry
+	var err error
+
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&entries)
+	case FormatCSV:
+		entries, err = readCSVInventory(r)
+	default:
+		return nil, fmt.Errorf("unknown inventory format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s inventory: %v", format, err)
+	}
+
+	results := make([]*LintResult, 0, len(entries))
+	for i, entry := range entries {
+		result, err := lintInventoryEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("inventory entry %d (%s): %v", i, entry.Name, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func lintInventoryEntry(entry InventoryEntry) (*LintResult, error) {
+	spec := MetricSpec{
+		Name:           entry.Name,
+		Help:           entry.Help,
+		Type:           MetricType(strings.ToLower(entry.Type)),
+		VariableLabels: entry.Labels,
+	}
+
+	switch spec.Type {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeHistogram, MetricTypeSummary:
+		return LintSpec(spec), nil
+	default:
+		return nil, fmt.Errorf("unsupported metric type %q", entry.Type)
+	}
+}
+
+func readCSVInventory(r io.Reader) ([]InventoryEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %q column in header", required)
+		}
+	}
+
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entries := make([]InventoryEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		entry := InventoryEntry{
+			Name: column(record, "name"),
+			Type: column(record, "type"),
+			Help: column(record, "help"),
+		}
+		for _, label := range strings.Split(column(record, "labels"), ";") {
+			if label = strings.TrimSpace(label); label != "" {
+				entry.Labels = append(entry.Labels, label)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
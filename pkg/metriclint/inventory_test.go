@@ -0,0 +1,88 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintInventory(t *testing.T) {
+	tests := []struct {
+		name            string
+		format          Format
+		input           string
+		expectedResults []string
+		expectedErr     bool
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			input: `[
+				{"name": "lint_test_total", "type": "counter", "help": "this is help message"},
+				{"name": "lint_test_seconds", "type": "histogram", "labels": ["lName"]}
+			]`,
+			expectedResults: []string{
+				"lint_test_total:",
+				fmt.Sprintf("lint_test_seconds:%s,%s", LintErrMsgNoHelp, LintErrMsgLabelShouldBeSnakeCase),
+			},
+		},
+		{
+			name:   "csv",
+			format: FormatCSV,
+			input: "name,type,help,labels\n" +
+				"lint_test_total,gauge,this is help message,code;method\n",
+			expectedResults: []string{
+				fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			},
+		},
+		{
+			name:        "csv without type column",
+			format:      FormatCSV,
+			input:       "name,help\nlint_test_total,this is help message\n",
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported type",
+			format:      FormatJSON,
+			input:       `[{"name": "lint_test", "type": "info"}]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := LintInventory(strings.NewReader(tc.input), tc.format)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %v, but got: %v", tc.expectedErr, err)
+			}
+			if len(results) != len(tc.expectedResults) {
+				t.Fatalf("expected: %v, but got: %v", tc.expectedResults, results)
+			}
+			for i, result := range results {
+				if result.String() != tc.expectedResults[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expectedResults[i], result.String())
+				}
+			}
+		})
+	}
+}
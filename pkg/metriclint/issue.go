@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "sort"
+
+// Issue represents a single lint error of a metric.
+type Issue struct {
+	// Stable ID of the rule reporting the issue, such as RuleHelpMissing.
+	// Empty for issues converted from plain messages.
+	ID string `json:",omitempty"`
+
+	// Human readable description of the issue.
+	Message string
+
+	// Severity of the issue, empty if the rule reporting it doesn't set one.
+	Severity Severity `json:",omitempty"`
+
+	// Suggested fix, the compliant metric name, or label name for label rules, empty if the rule has none.
+	Suggestion string `json:",omitempty"`
+
+	// Where the metric is declared, nil unless the issue was found in source code.
+	Location *SourceLocation `json:",omitempty"`
+}
+
+// SourceLocation is a position in a source file, 1-based.
+type SourceLocation struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// ruleIssues converts the messages of a rule into issues with the rule ID and its default severity.
+func ruleIssues(id string, messages []string) []Issue {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	rule, _ := RuleByID(id)
+	issues := make([]Issue, 0, len(messages))
+	for _, m := range messages {
+		issues = append(issues, Issue{ID: id, Message: m, Severity: rule.Severity})
+	}
+
+	return issues
+}
+
+// IssuesFromMessages converts plain issue messages into issues.
+func IssuesFromMessages(messages []string) []Issue {
+	if messages == nil {
+		return nil
+	}
+
+	issues := make([]Issue, 0, len(messages))
+	for _, m := range messages {
+		issues = append(issues, Issue{Message: m})
+	}
+
+	return issues
+}
+
+// IssueMessages returns the messages of the issues.
+func IssueMessages(issues []Issue) []string {
+	if issues == nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+
+	return messages
+}
+
+// AddIssues appends issues to both LintResult.Findings and LintResult.Issues.
+func (lr *LintResult) AddIssues(issues ...Issue) {
+	for _, issue := range issues {
+		lr.Findings = append(lr.Findings, issue)
+		lr.Issues = append(lr.Issues, issue.Message)
+	}
+}
+
+// AddMessages appends plain issue messages to both LintResult.Findings and LintResult.Issues.
+func (lr *LintResult) AddMessages(messages ...string) {
+	lr.AddIssues(IssuesFromMessages(messages)...)
+}
+
+// AddRuleMessages appends the messages reported by a rule, with the rule ID and its default severity.
+func (lr *LintResult) AddRuleMessages(id string, messages ...string) {
+	lr.AddIssues(ruleIssues(id, messages)...)
+}
+
+// dedupe drops the issues equal to an earlier one, e.g. reported for an "le" label which is both a const and
+// a variable label, keeping the order of the others.
+func (lr *LintResult) dedupe() {
+	var kept []Issue
+	for i, issue := range lr.Findings {
+		duplicate := false
+		for _, previous := range lr.Findings[:i] {
+			if previous == issue {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, issue)
+		}
+	}
+	if len(kept) == len(lr.Findings) {
+		return
+	}
+	lr.Findings = kept
+	lr.Issues = IssueMessages(kept)
+}
+
+// SortIssues sorts the findings of the result by rule ID, then message, instead of the order the rules ran in,
+// e.g. for reports diffed between CI runs. Issues without ID come first.
+func (lr *LintResult) SortIssues() {
+	sort.SliceStable(lr.Findings, func(i, j int) bool {
+		a, b := lr.Findings[i], lr.Findings[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Message < b.Message
+	})
+	lr.Issues = IssueMessages(lr.Findings)
+}
+
+// ruleKey returns the ID of the rule reporting the issue, or its message for issues without ID.
+func (i Issue) ruleKey() string {
+	if i.ID != "" {
+		return i.ID
+	}
+
+	return i.Message
+}
+
+// findings returns the findings of the result, falling back to the plain issue messages
+// for results which only have Issues, e.g. decoded from reports stored by older versions.
+func (lr *LintResult) findings() []Issue {
+	if len(lr.Findings) == 0 {
+		return IssuesFromMessages(lr.Issues)
+	}
+
+	return lr.Findings
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// assertConsistent checks that the deprecated Issues never diverge from Findings.
+func assertConsistent(t *testing.T, result *LintResult) {
+	t.Helper()
+
+	if !reflect.DeepEqual(IssueMessages(result.Findings), result.Issues) {
+		t.Errorf("Issues %q diverged from Findings %v", result.Issues, result.Findings)
+	}
+}
+
+func TestFindingsConsistentWithIssues(t *testing.T) {
+	specs := []MetricSpec{
+		{Name: "lint_test_total", Help: "this is help message", Type: MetricTypeCounter},
+		{Name: "lint_tesT_hours", Type: MetricTypeCounter, VariableLabels: []string{"le", "lName"}},
+		{Name: "lint_ms_total", Type: MetricTypeGauge, ConstLabels: map[string]string{"quantile": "1"}},
+		{Namespace: "lint_", Name: "_bucket", Type: MetricTypeHistogram},
+		{Name: "lint_test_bucket", Type: MetricTypeSummary, VariableLabels: []string{"le"}},
+	}
+
+	for _, spec := range specs {
+		assertConsistent(t, LintSpec(spec))
+	}
+
+}
+
+func TestIssueConversion(t *testing.T) {
+	messages := []string{LintErrMsgNoHelp, LintErrMsgNoReservedChars}
+
+	if got := IssueMessages(IssuesFromMessages(messages)); !reflect.DeepEqual(got, messages) {
+		t.Errorf("expected: %v, but got: %v", messages, got)
+	}
+
+	result := &LintResult{MetricName: "lint_test", Issues: messages}
+	if got := IssueMessages(result.findings()); !reflect.DeepEqual(got, messages) {
+		t.Errorf("expected findings from legacy issues: %v, but got: %v", messages, got)
+	}
+}
+
+func TestLintSpecIssueIDs(t *testing.T) {
+	specs := []MetricSpec{
+		{Name: "lint_tesT_hours", Type: MetricTypeCounter, VariableLabels: []string{"le", "lName"}},
+		{Name: "lint_ms_total", Type: MetricTypeGauge, ConstLabels: map[string]string{"quantile": "1"}},
+		{Namespace: "lint_", Name: "_bucket", Type: MetricTypeHistogram},
+		{Name: "", Type: MetricTypeSummary},
+	}
+
+	for _, spec := range specs {
+		for _, issue := range LintSpec(spec).Findings {
+			rule, ok := RuleByID(issue.ID)
+			if !ok {
+				t.Errorf("issue %q has unknown rule ID %q", issue.Message, issue.ID)
+				continue
+			}
+			if issue.Severity != rule.Severity {
+				t.Errorf("expected severity: %s, but got: %s for %q", rule.Severity, issue.Severity, issue.Message)
+			}
+		}
+	}
+}
+
+func TestLintSpecLabelSuggestions(t *testing.T) {
+	spec := MetricSpec{Name: "lint_queue_length", Help: "this is help message", Type: MetricTypeGauge,
+		ConstLabels: map[string]string{"zoneName": "a", "hostName": "b", "region": "c"}, VariableLabels: []string{"queueName"}}
+
+	var suggestions []string
+	for _, issue := range LintSpec(spec).Findings {
+		if issue.ID == RuleLabelCamelCase {
+			suggestions = append(suggestions, issue.Suggestion)
+		}
+	}
+	expected := []string{"host_name", "zone_name", "queue_name"}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("expected: %v, but got: %v", expected, suggestions)
+	}
+}
+
+func TestLintSpecSuggestions(t *testing.T) {
+	var tests = []struct {
+		name     string
+		spec     MetricSpec
+		id       string
+		expected string
+	}{
+		{
+			name:     "counter total suffix",
+			spec:     MetricSpec{Name: "lint_requests", Help: "this is help message", Type: MetricTypeCounter},
+			id:       RuleCounterTotalSuffix,
+			expected: "lint_requests_total",
+		},
+		{
+			name:     "non counter total suffix",
+			spec:     MetricSpec{Name: "lint_queue_total", Help: "this is help message", Type: MetricTypeGauge},
+			id:       RuleNonCounterTotalSuffix,
+			expected: "lint_queue",
+		},
+		{
+			name:     "camel case",
+			spec:     MetricSpec{Name: "lint_queueLength", Help: "this is help message", Type: MetricTypeGauge},
+			id:       RuleNameCamelCase,
+			expected: "lint_queue_length",
+		},
+		{
+			name:     "non base unit",
+			spec:     MetricSpec{Name: "lint_duration_milliseconds", Help: "this is help message", Type: MetricTypeHistogram},
+			id:       RuleNonBaseUnit,
+			expected: "lint_duration_seconds",
+		},
+		{
+			name:     "abbreviated unit",
+			spec:     MetricSpec{Name: "lint_duration_ms", Help: "this is help message", Type: MetricTypeHistogram},
+			id:       RuleNameAbbreviatedUnit,
+			expected: "lint_duration_seconds",
+		},
+		{
+			name:     "camel case label",
+			spec:     MetricSpec{Name: "lint_queue_length", Help: "this is help message", Type: MetricTypeGauge, VariableLabels: []string{"queueName"}},
+			id:       RuleLabelCamelCase,
+			expected: "queue_name",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			for _, issue := range LintSpec(tc.spec).Findings {
+				if issue.ID == tc.id {
+					if issue.Suggestion != tc.expected {
+						t.Errorf("expected: %s, but got: %s", tc.expected, issue.Suggestion)
+					}
+					return
+				}
+			}
+			t.Errorf("expected an issue of rule %s", tc.id)
+		})
+	}
+}
+
+func TestLintSpecDedupesIssues(t *testing.T) {
+	spec := MetricSpec{Name: "lint_queue_length", Help: "this is help message", Type: MetricTypeGauge,
+		ConstLabels: map[string]string{"le": "1"}, VariableLabels: []string{"le"}}
+
+	result := LintSpec(spec)
+	count := 0
+	for _, issue := range result.Findings {
+		if issue.ID == RuleNonHistogramLeLabel {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected: 1 %s issue, but got: %v", RuleNonHistogramLeLabel, result.Findings)
+	}
+	assertConsistent(t, result)
+}
+
+func TestSortIssues(t *testing.T) {
+	spec := MetricSpec{Name: "lintRequests", Type: MetricTypeCounter, VariableLabels: []string{"hostName", "code"}}
+
+	config := &Config{SortIssues: true}
+	l, err := NewLinterFromConfig(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := l.Lint(spec)
+	if len(result.Findings) < 3 {
+		t.Fatalf("expected: several issues, but got: %v", result.Findings)
+	}
+
+	sorted := sort.SliceIsSorted(result.Findings, func(i, j int) bool {
+		return result.Findings[i].ID < result.Findings[j].ID
+	})
+	if !sorted {
+		t.Errorf("expected: issues sorted by rule ID, but got: %v", result.Findings)
+	}
+	assertConsistent(t, result)
+	if unsorted := NewLinter().Lint(spec); len(unsorted.Findings) != len(result.Findings) {
+		t.Errorf("expected: the issues of the unsorted result, but got: %v", result.Findings)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	LintErrMsgBooleanLabel         = `label %q only has boolean values, consider splitting the metric or a label naming the state`
+	LintErrMsgHighCardinalityLabel = `label %q usually has unbounded values, which multiply the number of series`
+)
+
+// DefaultHighCardinalityLabels are label names historically associated with unbounded values.
+var DefaultHighCardinalityLabels = []string{"id", "uuid", "path", "url", "ip", "user", "email", "query", "pod_name"}
+
+// Pairs of label values treated as boolean, lower case.
+var booleanLabelValues = [][2]string{
+	{"true", "false"},
+	{"yes", "no"},
+}
+
+// BooleanLabelRule is an advisory rule flagging labels whose values are only ever true/false or yes/no.
+// Such labels tend to be unreadable in queries, two metrics or a label naming the state read better.
+type BooleanLabelRule struct {
+	// Labels which are fine to be boolean.
+	Allowed []string
+}
+
+// Lint checks the declared or observed values of each label.
+func (r BooleanLabelRule) Lint(labelValues map[string][]string) (issues []string) {
+	names := make([]string, 0, len(labelValues))
+	for name := range labelValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if r.allowed(name) || !isBooleanValues(labelValues[name]) {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf(LintErrMsgBooleanLabel, name))
+	}
+
+	return issues
+}
+
+// LintConstLabels checks the values of const labels.
+func (r BooleanLabelRule) LintConstLabels(constLabels map[string]string) []string {
+	labelValues := make(map[string][]string, len(constLabels))
+	for name, value := range constLabels {
+		labelValues[name] = []string{value}
+	}
+
+	return r.Lint(labelValues)
+}
+
+func (r BooleanLabelRule) allowed(name string) bool {
+	for _, a := range r.Allowed {
+		if a == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBooleanValues(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, pair := range booleanLabelValues {
+		matched := true
+		for _, v := range values {
+			v = strings.ToLower(v)
+			if v != pair[0] && v != pair[1] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CardinalityLabelRule flags const and variable label names historically associated with unbounded
+// values, before they turn into cardinality bombs in production. Names are matched exactly.
+type CardinalityLabelRule struct {
+	// Label names to flag, DefaultHighCardinalityLabels if nil.
+	Deny []string
+
+	// Label names not to flag, e.g. a "path" label bounded by route templates.
+	Allow []string
+}
+
+// Lint checks the const label names and the label names of a vector.
+func (r CardinalityLabelRule) Lint(constLabels map[string]string, labelNames []string) (issues []string) {
+	deny := r.Deny
+	if deny == nil {
+		deny = DefaultHighCardinalityLabels
+	}
+	denied := make(map[string]bool, len(deny))
+	for _, name := range deny {
+		denied[name] = true
+	}
+	for _, name := range r.Allow {
+		delete(denied, name)
+	}
+
+	for _, name := range append(sortedLabelNames(constLabels), labelNames...) {
+		if denied[name] {
+			issues = append(issues, fmt.Sprintf(LintErrMsgHighCardinalityLabel, name))
+		}
+	}
+
+	return issues
+}
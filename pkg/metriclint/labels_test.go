@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBooleanLabelRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           BooleanLabelRule
+		labelValues    map[string][]string
+		expectedResult string
+	}{
+		{
+			name:           "true and false",
+			labelValues:    map[string][]string{"cached": {"true", "false"}},
+			expectedResult: fmt.Sprintf(LintErrMsgBooleanLabel, "cached"),
+		},
+		{
+			name:           "yes and no",
+			labelValues:    map[string][]string{"hit": {"Yes", "no"}},
+			expectedResult: fmt.Sprintf(LintErrMsgBooleanLabel, "hit"),
+		},
+		{
+			name:        "mixed values",
+			labelValues: map[string][]string{"result": {"true", "error"}},
+		},
+		{
+			name:        "allowed label",
+			rule:        BooleanLabelRule{Allowed: []string{"cached"}},
+			labelValues: map[string][]string{"cached": {"true", "false"}},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.rule.Lint(tc.labelValues), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestCardinalityLabelRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           CardinalityLabelRule
+		constLabels    map[string]string
+		labelNames     []string
+		expectedResult string
+	}{
+		{
+			name:       "bounded labels",
+			labelNames: []string{"code", "handler"},
+		},
+		{
+			name:        "default deny list",
+			constLabels: map[string]string{"pod_name": "a"},
+			labelNames:  []string{"code", "path"},
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgHighCardinalityLabel, "pod_name"),
+				fmt.Sprintf(LintErrMsgHighCardinalityLabel, "path"),
+			}, ","),
+		},
+		{
+			name:       "allowed label",
+			rule:       CardinalityLabelRule{Allow: []string{"path"}},
+			labelNames: []string{"path"},
+		},
+		{
+			name:           "custom deny list",
+			rule:           CardinalityLabelRule{Deny: []string{"tenant"}},
+			labelNames:     []string{"path", "tenant"},
+			expectedResult: fmt.Sprintf(LintErrMsgHighCardinalityLabel, "tenant"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.rule.Lint(tc.constLabels, tc.labelNames), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
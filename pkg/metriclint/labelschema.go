@@ -0,0 +1,156 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const LintErrMsgLabelSchema = `label %q should be named %q like in the other metrics of %q`
+
+// DefaultLabelSynonyms are groups of label names commonly used for the same concept.
+var DefaultLabelSynonyms = [][]string{
+	{"code", "status_code", "response_code", "http_code"},
+	{"method", "verb", "http_method"},
+	{"result", "outcome"},
+	{"pod", "pod_name"},
+	{"container", "container_name"},
+}
+
+// LabelSchemaRule is an advisory batch rule: the metrics of a namespace should name the same concept with
+// the same label, e.g. not "code" in one metric and "status_code" in another, to keep dashboards join-able.
+type LabelSchemaRule struct {
+	// Groups of label names meaning the same concept, DefaultLabelSynonyms if empty.
+	Synonyms [][]string
+
+	// BySubsystem compares the metrics of each subsystem rather than of each namespace. The namespace and
+	// the subsystem are the first segments of the metric names.
+	BySubsystem bool
+}
+
+// Lint checks the declared metrics and returns the results of the metrics using another label name than
+// the one most metrics of their namespace use for the same concept. On a tie, the name listed first in
+// the synonym group wins.
+func (r LabelSchemaRule) Lint(metrics []InventoryEntry) (results []*LintResult) {
+	synonyms := r.Synonyms
+	if len(synonyms) == 0 {
+		synonyms = DefaultLabelSynonyms
+	}
+
+	// counts[group][label] is the number of metrics of the group having the label.
+	counts := map[string]map[string]int{}
+	for _, m := range metrics {
+		group, ok := r.group(m.Name)
+		if !ok {
+			continue
+		}
+		if counts[group] == nil {
+			counts[group] = map[string]int{}
+		}
+		for _, label := range CanonicalLabelNames(nil, m.Labels) {
+			counts[group][label]++
+		}
+	}
+
+	for _, m := range metrics {
+		group, ok := r.group(m.Name)
+		if !ok {
+			continue
+		}
+
+		var issues []string
+		for _, label := range CanonicalLabelNames(nil, m.Labels) {
+			for _, names := range synonyms {
+				if !containsLabel(names, label) {
+					continue
+				}
+				if preferred := preferredLabel(names, counts[group]); preferred != label {
+					issues = append(issues, fmt.Sprintf(LintErrMsgLabelSchema, label, preferred, group))
+				}
+			}
+		}
+		if len(issues) > 0 {
+			result := &LintResult{MetricName: m.Name}
+			result.AddRuleMessages(RuleLabelSchema, issues...)
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// LintLabelSchema runs the rule with the label synonyms of the linter, unless the rule has its own, then drops the
+// issues of the ignored metrics and of the disabled and suppressed rules, and applies the severity overrides, like
+// Lint. Metrics left without issue are left out.
+func (l *Linter) LintLabelSchema(rule LabelSchemaRule, metrics []InventoryEntry) (results []*LintResult) {
+	if len(rule.Synonyms) == 0 {
+		rule.Synonyms = l.synonyms
+	}
+
+	for _, result := range rule.Lint(metrics) {
+		if l.ignored(result.MetricName) {
+			continue
+		}
+		l.applyPolicy(result)
+		if len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// group returns the namespace, or the namespace and subsystem, of the metric name, false if the name
+// has no segment left for the metric itself.
+func (r LabelSchemaRule) group(name string) (string, bool) {
+	n := 1
+	if r.BySubsystem {
+		n = 2
+	}
+	segments := strings.Split(name, "_")
+	if len(segments) <= n {
+		return "", false
+	}
+
+	return strings.Join(segments[:n], "_"), true
+}
+
+// preferredLabel returns the synonym used by the most metrics, the first one listed on a tie.
+func preferredLabel(names []string, counts map[string]int) string {
+	preferred := ""
+	for _, name := range names {
+		if counts[name] > 0 && (preferred == "" || counts[name] > counts[preferred]) {
+			preferred = name
+		}
+	}
+
+	return preferred
+}
+
+func containsLabel(names []string, label string) bool {
+	for _, name := range names {
+		if name == label {
+			return true
+		}
+	}
+
+	return false
+}
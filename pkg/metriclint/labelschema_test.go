@@ -0,0 +1,172 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLabelSchemaRule(t *testing.T) {
+	tests := []struct {
+		name            string
+		rule            LabelSchemaRule
+		metrics         []InventoryEntry
+		expectedResults []string
+	}{
+		{
+			name: "consistent labels",
+			metrics: []InventoryEntry{
+				{Name: "apiserver_requests_total", Labels: []string{"code", "verb"}},
+				{Name: "apiserver_errors_total", Labels: []string{"code", "verb"}},
+			},
+		},
+		{
+			name: "synonyms used by the minority",
+			metrics: []InventoryEntry{
+				{Name: "apiserver_requests_total", Labels: []string{"code", "method"}},
+				{Name: "apiserver_errors_total", Labels: []string{"code", "method"}},
+				{Name: "apiserver_request_duration_seconds", Labels: []string{"status_code", "verb"}},
+			},
+			expectedResults: []string{
+				"apiserver_request_duration_seconds:" + fmt.Sprintf(LintErrMsgLabelSchema, "status_code", "code", "apiserver") +
+					"," + fmt.Sprintf(LintErrMsgLabelSchema, "verb", "method", "apiserver"),
+			},
+		},
+		{
+			name: "tie goes to the first synonym",
+			metrics: []InventoryEntry{
+				{Name: "apiserver_requests_total", Labels: []string{"verb"}},
+				{Name: "apiserver_errors_total", Labels: []string{"method"}},
+			},
+			expectedResults: []string{
+				"apiserver_requests_total:" + fmt.Sprintf(LintErrMsgLabelSchema, "verb", "method", "apiserver"),
+			},
+		},
+		{
+			name: "other namespaces",
+			metrics: []InventoryEntry{
+				{Name: "apiserver_requests_total", Labels: []string{"code"}},
+				{Name: "etcd_requests_total", Labels: []string{"status_code"}},
+			},
+		},
+		{
+			name: "by subsystem",
+			rule: LabelSchemaRule{BySubsystem: true},
+			metrics: []InventoryEntry{
+				{Name: "apiserver_storage_requests_total", Labels: []string{"code"}},
+				{Name: "apiserver_watch_events_total", Labels: []string{"status_code"}},
+			},
+		},
+		{
+			name: "custom synonyms",
+			rule: LabelSchemaRule{Synonyms: [][]string{{"tenant", "customer"}}},
+			metrics: []InventoryEntry{
+				{Name: "billing_invoices_total", Labels: []string{"tenant", "code"}},
+				{Name: "billing_payments_total", Labels: []string{"customer", "status_code"}},
+			},
+			expectedResults: []string{
+				"billing_payments_total:" + fmt.Sprintf(LintErrMsgLabelSchema, "customer", "tenant", "billing"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results := tc.rule.Lint(tc.metrics)
+			if len(results) != len(tc.expectedResults) {
+				t.Fatalf("expected: %v, but got: %v", tc.expectedResults, results)
+			}
+			for i, result := range results {
+				if result.String() != tc.expectedResults[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expectedResults[i], result.String())
+				}
+			}
+		})
+	}
+}
+
+func TestLinterLintLabelSchema(t *testing.T) {
+	metrics := []InventoryEntry{
+		{Name: "billing_invoices_total", Labels: []string{"tenant", "code"}},
+		{Name: "billing_refunds_total", Labels: []string{"tenant", "code"}},
+		{Name: "billing_payments_total", Labels: []string{"customer", "status_code"}},
+	}
+
+	tests := []struct {
+		name            string
+		linter          *Linter
+		rule            LabelSchemaRule
+		expectedResults []string
+	}{
+		{
+			name:   "default synonyms",
+			linter: NewLinter(),
+			expectedResults: []string{
+				"billing_payments_total:" + fmt.Sprintf(LintErrMsgLabelSchema, "status_code", "code", "billing"),
+			},
+		},
+		{
+			name:   "synonyms of the linter",
+			linter: NewLinter(WithLabelSynonyms([]string{"tenant", "customer"})),
+			expectedResults: []string{
+				"billing_payments_total:" + fmt.Sprintf(LintErrMsgLabelSchema, "customer", "tenant", "billing"),
+			},
+		},
+		{
+			name:   "synonyms of the rule",
+			linter: NewLinter(WithLabelSynonyms([]string{"tenant", "customer"})),
+			rule:   LabelSchemaRule{Synonyms: DefaultLabelSynonyms},
+			expectedResults: []string{
+				"billing_payments_total:" + fmt.Sprintf(LintErrMsgLabelSchema, "status_code", "code", "billing"),
+			},
+		},
+		{
+			name:   "disabled",
+			linter: NewLinter(DisableRules(RuleLabelSchema)),
+		},
+		{
+			name:   "ignored",
+			linter: NewLinter(IgnoreMetrics("billing_payments_.*")),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results := tc.linter.LintLabelSchema(tc.rule, metrics)
+			if len(results) != len(tc.expectedResults) {
+				t.Fatalf("expected: %v, but got: %v", tc.expectedResults, results)
+			}
+			for i, result := range results {
+				if result.String() != tc.expectedResults[i] {
+					t.Errorf("expected: %s, but got: %s", tc.expectedResults[i], result.String())
+				}
+			}
+		})
+	}
+
+	// The severity overrides apply.
+	results := NewLinter(Treat(RuleLabelSchema, SeverityError)).LintLabelSchema(LabelSchemaRule{}, metrics)
+	if len(results) != 1 || results[0].Findings[0].Severity != SeverityError {
+		t.Errorf("expected: an error, but got: %v", results)
+	}
+}
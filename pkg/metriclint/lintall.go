@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+)
+
+// LintAll lints metrics with the default rules, see Linter.LintAll.
+func LintAll(specs ...MetricSpec) (Results, error) {
+	return NewLinter().LintAll(specs...)
+}
+
+// LintAll lints metrics at once, e.g. all metrics of a component in a startup self-check, with Lint,
+// or LintVector for the specs with variable labels, and LintDuplicates. It returns an error, without
+// linting, if a spec has an unknown metric type.
+func (l *Linter) LintAll(specs ...MetricSpec) (Results, error) {
+	for _, spec := range specs {
+		switch spec.Type {
+		case MetricTypeCounter, MetricTypeGauge, MetricTypeHistogram, MetricTypeSummary, MetricTypeUntyped:
+		default:
+			return nil, fmt.Errorf("metric %q has unknown type %q", spec.FQName(), spec.Type)
+		}
+	}
+
+	results := make(Results, len(specs))
+	l.parallel(len(specs), func(i int) {
+		if len(specs[i].VariableLabels) > 0 {
+			results[i] = l.LintVector(specs[i])
+		} else {
+			results[i] = l.Lint(specs[i])
+		}
+	})
+	l.LintDuplicates(specs, results)
+
+	return results, nil
+}
+
+// CountByRule returns the number of issues of each rule. Issues without rule ID are counted under "".
+func (rs Results) CountByRule() map[string]int {
+	counts := map[string]int{}
+	for _, r := range rs {
+		for _, issue := range r.findings() {
+			counts[issue.ID]++
+		}
+	}
+
+	return counts
+}
+
+// CountBySeverity returns the number of issues of each severity.
+func (rs Results) CountBySeverity() map[Severity]int {
+	counts := map[Severity]int{}
+	for _, r := range rs {
+		for _, issue := range r.findings() {
+			counts[issue.Severity]++
+		}
+	}
+
+	return counts
+}
+
+// HasErrors reports whether any issue is blocking.
+func (rs Results) HasErrors() bool {
+	return rs.CountBySeverity()[SeverityError] > 0
+}
+
+// Summary returns a one line summary of the results, e.g.
+// "3 issues in 2 of 5 metrics: 2 errors, 1 warnings".
+func (rs Results) Summary() string {
+	metrics := 0
+	for _, r := range rs {
+		if len(r.findings()) > 0 {
+			metrics++
+		}
+	}
+	severities := rs.CountBySeverity()
+
+	return fmt.Sprintf("%d issues in %d of %d metrics: %d errors, %d warnings",
+		rs.IssueCount(), metrics, len(rs), severities[SeverityError], severities[SeverityWarning])
+}
+
+// String renders the number of issues of each rule and severity as a table sorted by rule ID,
+// followed by the Summary line.
+func (rs Results) String() string {
+	type row struct {
+		rule     string
+		severity Severity
+	}
+	counts := map[row]int{}
+	for _, r := range rs {
+		for _, issue := range r.findings() {
+			counts[row{issue.ID, issue.Severity}]++
+		}
+	}
+	rows := make([]row, 0, len(counts))
+	for r := range counts {
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].rule != rows[j].rule {
+			return rows[i].rule < rows[j].rule
+		}
+		return rows[i].severity < rows[j].severity
+	})
+
+	var b bytes.Buffer
+	if len(rows) > 0 {
+		tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "RULE\tSEVERITY\tISSUES")
+		for _, r := range rows {
+			rule := r.rule
+			if rule == "" {
+				rule = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", rule, r.severity, counts[r])
+		}
+		tw.Flush()
+	}
+	b.WriteString(rs.Summary())
+
+	return b.String()
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintAll(t *testing.T) {
+	results, err := LintAll(
+		MetricSpec{Name: "http_requests", Help: "Number of requests.", Type: MetricTypeCounter},
+		MetricSpec{Name: "queue_length", Type: MetricTypeGauge, VariableLabels: []string{"path", "path"}},
+		MetricSpec{Name: "up", Help: "Whether the target is up.", Type: MetricTypeGauge},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected: %d results, but got: %d", 3, len(results))
+	}
+
+	expectedRules := map[string]int{
+		RuleCounterTotalSuffix:   1,
+		RuleHelpMissing:          1,
+		RuleHighCardinalityLabel: 1,
+		RuleLabelDuplicate:       1,
+	}
+	if counts := results.CountByRule(); !reflect.DeepEqual(counts, expectedRules) {
+		t.Errorf("expected: %v, but got: %v", expectedRules, counts)
+	}
+
+	expectedSeverities := map[Severity]int{SeverityError: 3, SeverityWarning: 1}
+	if counts := results.CountBySeverity(); !reflect.DeepEqual(counts, expectedSeverities) {
+		t.Errorf("expected: %v, but got: %v", expectedSeverities, counts)
+	}
+
+	if !results.HasErrors() {
+		t.Errorf("expected errors")
+	}
+
+	expected := "RULE                    SEVERITY  ISSUES\n" +
+		"counter-total-suffix    error     1\n" +
+		"help-missing            error     1\n" +
+		"high-cardinality-label  warning   1\n" +
+		"label-duplicate         error     1\n" +
+		"4 issues in 2 of 3 metrics: 3 errors, 1 warnings"
+	if s := results.String(); s != expected {
+		t.Errorf("expected: %s, but got: %s", expected, s)
+	}
+}
+
+func TestLintAllClean(t *testing.T) {
+	results, err := LintAll(MetricSpec{Name: "up", Help: "Whether the target is up.", Type: MetricTypeGauge})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results.HasErrors() {
+		t.Errorf("expected no error, but got: %v", results.CountBySeverity())
+	}
+	expected := "0 issues in 0 of 1 metrics: 0 errors, 0 warnings"
+	if s := results.String(); s != expected {
+		t.Errorf("expected: %s, but got: %s", expected, s)
+	}
+}
+
+func TestLintAllUnknownType(t *testing.T) {
+	_, err := NewLinter().LintAll(
+		MetricSpec{Name: "up", Help: "Whether the target is up.", Type: MetricTypeGauge},
+		MetricSpec{Name: "queue_length", Type: "gaueg"},
+	)
+	expected := `metric "queue_length" has unknown type "gaueg"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, err)
+	}
+}
@@ -0,0 +1,453 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Linter lints metrics with a configurable set of rules. The package level functions such as
+// LintSpec lint with the default rules, like a Linter without options.
+type Linter struct {
+	enabled     map[string]bool
+	disabled    map[string]bool
+	declarative []*compiledRule
+	custom      []Rule
+	cardinality *CardinalityLabelRule
+	synonyms    [][]string
+	scrape      time.Duration
+	namespace   *compiledNamespacePolicy
+	tombstones  map[string]Tombstone
+	severities  map[string]Severity
+	failOn      Severity
+	sortIssues  bool
+	ignore      []*regexp.Regexp
+	units       map[string]string
+	unitMatcher *unitMatcher
+	parallelism int
+
+	suppressions []suppression
+}
+
+// Option configures a Linter.
+type Option func(*Linter)
+
+// EnableRules enables opt-in rules by ID, such as RuleUnitSuffix. The opt-in rules run with their
+// default settings. The other rules are enabled by default.
+func EnableRules(ids ...string) Option {
+	return func(l *Linter) {
+		for _, id := range ids {
+			l.enabled[id] = true
+		}
+	}
+}
+
+// DisableRules disables rules by ID, the issues they report are dropped.
+func DisableRules(ids ...string) Option {
+	return func(l *Linter) {
+		for _, id := range ids {
+			l.disabled[id] = true
+		}
+	}
+}
+
+// WithCardinalityLabels replaces the label names reported by RuleHighCardinalityLabel.
+func WithCardinalityLabels(rule CardinalityLabelRule) Option {
+	return func(l *Linter) {
+		l.cardinality = &rule
+	}
+}
+
+// WithLabelSynonyms replaces the groups of label names meaning the same concept which LintLabelSchema
+// compares, DefaultLabelSynonyms by default.
+func WithLabelSynonyms(synonyms ...[]string) Option {
+	return func(l *Linter) {
+		l.synonyms = synonyms
+	}
+}
+
+// WithScrapeInterval sets the scrape interval NativeHistogramRule checks the minimum reset duration of native
+// histograms against, DefaultScrapeInterval by default.
+func WithScrapeInterval(interval time.Duration) Option {
+	return func(l *Linter) {
+		l.scrape = interval
+	}
+}
+
+// WithSeverities replaces the default severity of rules by ID, e.g. to make a warning blocking.
+func WithSeverities(severities map[string]Severity) Option {
+	return func(l *Linter) {
+		if l.severities == nil {
+			l.severities = map[string]Severity{}
+		}
+		for id, severity := range severities {
+			l.severities[id] = severity
+		}
+	}
+}
+
+// Treat sets the severity of a single rule, e.g. Treat(RuleHelpPrefix, SeverityError) in CI while
+// development builds keep it a warning, see WithSeverities.
+func Treat(id string, severity Severity) Option {
+	return WithSeverities(map[string]Severity{id: severity})
+}
+
+// FailOn sets the severity from which issues fail a set of results, see Linter.Judge: SeverityError, the
+// default, fails on errors only, SeverityWarning on any issue.
+func FailOn(severity Severity) Option {
+	return func(l *Linter) {
+		l.failOn = severity
+	}
+}
+
+// SortIssues sorts the issues of every result by rule ID, then message, see LintResult.SortIssues, so that
+// reports diff cleanly between CI runs whatever the rules and their order.
+func SortIssues() Option {
+	return func(l *Linter) {
+		l.sortIssues = true
+	}
+}
+
+// IgnoreMetrics skips the metrics matching the regular expressions, anchored to the whole metric name,
+// their results have no issue. It panics on malformed expressions, which are programming errors.
+func IgnoreMetrics(patterns ...string) Option {
+	return func(l *Linter) {
+		for _, pattern := range patterns {
+			l.ignore = append(l.ignore, regexp.MustCompile("^(?:"+pattern+")$"))
+		}
+	}
+}
+
+// WithUnits recognizes units in addition to the built-in ones, mapped to their base unit,
+// e.g. "kibibytes": "bytes". A base unit maps to itself.
+func WithUnits(custom map[string]string) Option {
+	return func(l *Linter) {
+		if len(custom) == 0 {
+			return
+		}
+		if l.units == nil {
+			l.units = map[string]string{}
+			for unit, base := range units {
+				l.units[unit] = base
+			}
+		}
+		for unit, base := range custom {
+			l.units[unit] = base
+		}
+		l.unitMatcher = newUnitMatcher(l.units)
+	}
+}
+
+// withDeclarativeRules adds compiled declarative rules.
+func withDeclarativeRules(rules ...*compiledRule) Option {
+	return func(l *Linter) {
+		l.declarative = append(l.declarative, rules...)
+	}
+}
+
+// optInRules are the opt-in rules a Linter can run on a single spec, with their default settings.
+var optInRules = map[string]func(spec MetricSpec) []string{
+	RuleUnitSuffix:        func(spec MetricSpec) []string { return LintUnitSuffix(spec.FQName()) },
+	RuleAcronymLowercase:  func(spec MetricSpec) []string { return AcronymPolicy{}.Lint(spec.FQName()) },
+	RuleNumericFragment:   func(spec MetricSpec) []string { return NumericFragmentRule{}.Lint(spec.FQName()) },
+	RuleHelpPrefix:        func(spec MetricSpec) []string { return DefaultHelpPrefixPolicy.Lint(spec.Type, spec.Help) },
+	RuleBooleanLabel:      func(spec MetricSpec) []string { return BooleanLabelRule{}.LintConstLabels(spec.ConstLabels) },
+	RuleLabelDocumented:   func(spec MetricSpec) []string { return LabelDocRule{}.Lint(spec.Help, spec.VariableLabels) },
+	RuleSummaryObjectives: LintSummaryObjectives,
+	RuleNamespacePolicy:   func(spec MetricSpec) []string { return NamespacePolicy{}.Lint(spec) },
+}
+
+// NewLinter returns a Linter configured by the options.
+// It panics on unknown rule IDs, which are programming errors.
+func NewLinter(opts ...Option) *Linter {
+	l := &Linter{enabled: map[string]bool{}, disabled: map[string]bool{}}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	for _, ids := range []map[string]bool{l.enabled, l.disabled} {
+		for id := range ids {
+			if !l.knows(id) {
+				panic(fmt.Sprintf("metriclint: unknown rule %q", id))
+			}
+		}
+	}
+	for id := range l.severities {
+		if !l.knows(id) {
+			panic(fmt.Sprintf("metriclint: unknown rule %q", id))
+		}
+	}
+	if l.failOn == "" {
+		l.failOn = SeverityError
+	} else if err := l.failOn.validate(); err != nil {
+		panic(fmt.Sprintf("metriclint: fail on: %v", err))
+	}
+	for _, s := range l.suppressions {
+		for id := range s.rules {
+			if !l.knows(id) {
+				panic(fmt.Sprintf("metriclint: unknown rule %q", id))
+			}
+		}
+	}
+
+	return l
+}
+
+// NewLinterFromConfig returns a Linter configured by the config, running its declarative rules in
+// addition to the built-in ones. It fails with ConfigErrors if the config doesn't pass Validate, and if
+// the baseline of the config can't be loaded.
+func NewLinterFromConfig(config *Config) (*Linter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var declarative []*compiledRule
+	for _, rule := range config.Rules {
+		compiled, err := rule.compile()
+		if err != nil {
+			return nil, err
+		}
+		declarative = append(declarative, compiled)
+	}
+
+	var opts []Option
+	if config.Profile != "" {
+		opts = append(opts, WithProfile(config.Profile))
+	}
+	opts = append(opts,
+		UsePolicyBundles(config.Bundles...),
+		withDeclarativeRules(declarative...),
+		EnableRules(config.Enable...),
+		DisableRules(config.Disable...),
+		WithTombstones(config.Tombstones...),
+		WithSeverities(config.Severities),
+		WithSuppressions(config.Suppressions...),
+		IgnoreMetrics(config.Ignore...),
+		WithUnits(config.Units),
+	)
+	if config.ScrapeInterval != 0 {
+		opts = append(opts, WithScrapeInterval(config.ScrapeInterval))
+	}
+	if len(config.LabelSynonyms) > 0 {
+		opts = append(opts, WithLabelSynonyms(config.LabelSynonyms...))
+	}
+	if config.Namespace != nil {
+		opts = append(opts, WithNamespacePolicy(*config.Namespace))
+	}
+	if config.FailOn != "" {
+		opts = append(opts, FailOn(config.FailOn))
+	}
+	if config.SortIssues {
+		opts = append(opts, SortIssues())
+	}
+	if config.Baseline != "" {
+		baseline, err := LoadBaseline(config.Baseline)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load baseline: %v", err)
+		}
+		opts = append(opts, WithBaseline(baseline))
+	}
+
+	return NewLinter(opts...), nil
+}
+
+// knows reports whether id is a built-in rule, or a declarative or custom rule of the linter.
+func (l *Linter) knows(id string) bool {
+	if _, ok := RuleByID(id); ok {
+		return true
+	}
+	for _, r := range l.declarative {
+		if r.Name == id {
+			return true
+		}
+	}
+	for _, r := range l.custom {
+		if r.Name() == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lint lints a metric with the default rules, the enabled opt-in rules, the declarative and the custom rules,
+// leaving out the issues of the disabled rules.
+func (l *Linter) Lint(spec MetricSpec) *LintResult {
+	result := LintSpec(spec)
+	l.lintExtra(spec, result)
+
+	return result
+}
+
+// LintVector lints a vector like Lint, including the label names passed to its constructor, and the "le"
+// and "quantile" label names of histogram and summary vectors.
+func (l *Linter) LintVector(spec MetricSpec) *LintResult {
+	result := LintSpec(spec)
+	result.AddRuleMessages(RuleVectorLabels, VectorLabelsRule{}.Lint(spec.VariableLabels)...)
+	lintGeneratedLabels(spec, result)
+	l.lintExtra(spec, result)
+
+	return result
+}
+
+// LintUntyped lints an untyped metric like Lint, whatever the type of the spec, with an advisory
+// RuleUntypedMetric issue. It's meant for code still emitting untyped metrics, e.g. bridged exporters.
+func (l *Linter) LintUntyped(spec MetricSpec) *LintResult {
+	spec.Type = MetricTypeUntyped
+	result := LintSpec(spec)
+	result.AddRuleMessages(RuleUntypedMetric, LintErrMsgUntyped)
+	l.lintExtra(spec, result)
+
+	return result
+}
+
+// LintUntypedVector lints an untyped vector like LintUntyped, including the label names passed to its constructor.
+func (l *Linter) LintUntypedVector(spec MetricSpec) *LintResult {
+	spec.Type = MetricTypeUntyped
+	result := LintSpec(spec)
+	result.AddRuleMessages(RuleUntypedMetric, LintErrMsgUntyped)
+	result.AddRuleMessages(RuleVectorLabels, VectorLabelsRule{}.Lint(spec.VariableLabels)...)
+	l.lintExtra(spec, result)
+
+	return result
+}
+
+// lintExtra runs the rules configured on the linter, drops the disabled and suppressed ones and applies the
+// severity overrides.
+// The results of ignored metrics are emptied.
+func (l *Linter) lintExtra(spec MetricSpec, result *LintResult) {
+	if l.ignored(result.MetricName) {
+		debugf("skipping rules of ignored metric %s", result.MetricName)
+		result.Findings = nil
+		result.Issues = nil
+		return
+	}
+
+	if l.cardinality != nil {
+		replaceRuleMessages(result, RuleHighCardinalityLabel, l.cardinality.Lint(spec.ConstLabels, spec.VariableLabels))
+	}
+	if l.scrape != 0 && spec.Type == MetricTypeHistogram {
+		replaceRuleMessages(result, RuleNativeHistogramMinResetDuration, NativeHistogramRule{ScrapeInterval: l.scrape}.lintMinResetDuration(spec))
+	}
+	for _, rule := range rules {
+		if lint, ok := optInRules[rule.ID]; ok && l.enabled[rule.ID] {
+			result.AddRuleMessages(rule.ID, lint(spec)...)
+		}
+	}
+	if l.namespace != nil && l.enabled[RuleNamespacePolicy] {
+		replaceRuleMessages(result, RuleNamespacePolicy, l.namespace.lint(spec))
+	}
+	if l.units != nil {
+		l.lintUnits(spec, result)
+	}
+	for _, rule := range l.declarative {
+		result.AddIssues(rule.Lint(spec)...)
+	}
+	for _, rule := range l.custom {
+		result.AddIssues(checkCustom(rule, spec)...)
+	}
+
+	l.applyPolicy(result)
+}
+
+// AddRuleMessages adds the issues a rule found outside of Lint, e.g. by comparing two scrapes, to the result,
+// following the policy of the linter: the issues of ignored metrics and of disabled or suppressed rules are
+// dropped, and the severity overrides apply.
+func (l *Linter) AddRuleMessages(result *LintResult, id string, messages ...string) {
+	if len(messages) == 0 || l.ignored(result.MetricName) {
+		return
+	}
+
+	result.AddRuleMessages(id, messages...)
+	l.applyPolicy(result)
+}
+
+// ignored reports whether the metric matches one of the ignored patterns.
+func (l *Linter) ignored(metricName string) bool {
+	for _, re := range l.ignore {
+		if re.MatchString(metricName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintUnits lints the unit of the metric again, recognizing the custom units.
+func (l *Linter) lintUnits(spec MetricSpec, result *LintResult) {
+	replaceRuleMessages(result, RuleNonBaseUnit, lintMetricUnit(spec.FQName(), l.unitMatcher))
+	if l.enabled[RuleUnitSuffix] {
+		var nouns []string
+		for unit := range l.units {
+			nouns = append(nouns, unit)
+		}
+		replaceRuleMessages(result, RuleUnitSuffix, LintUnitSuffix(spec.FQName(), nouns...))
+	}
+}
+
+// applyPolicy drops the duplicate issues and the ones of the disabled and suppressed rules from the result,
+// applies the severity overrides and sorts the issues if the linter does.
+func (l *Linter) applyPolicy(result *LintResult) {
+	result.dedupe()
+	l.dropDisabled(result)
+	l.dropSuppressed(result)
+	l.overrideSeverities(result)
+	if l.sortIssues {
+		result.SortIssues()
+	}
+}
+
+// overrideSeverities sets the configured severities on the issues of the result.
+func (l *Linter) overrideSeverities(result *LintResult) {
+	for i, issue := range result.Findings {
+		if severity, ok := l.severities[issue.ID]; ok {
+			result.Findings[i].Severity = severity
+		}
+	}
+}
+
+// replaceRuleMessages replaces the issues reported by a rule with the given messages.
+func replaceRuleMessages(result *LintResult, id string, messages []string) {
+	var kept []Issue
+	for _, issue := range result.Findings {
+		if issue.ID != id {
+			kept = append(kept, issue)
+		}
+	}
+	result.Findings, result.Issues = nil, nil
+	result.AddIssues(kept...)
+	result.AddRuleMessages(id, messages...)
+}
+
+// dropDisabled removes the issues of the disabled rules from the result.
+func (l *Linter) dropDisabled(result *LintResult) {
+	if len(l.disabled) == 0 {
+		return
+	}
+	var kept []Issue
+	for _, issue := range result.Findings {
+		if !l.disabled[issue.ID] {
+			kept = append(kept, issue)
+		} else {
+			debugf("skipping disabled rule %s for %s", issue.ID, result.MetricName)
+		}
+	}
+	result.Findings = kept
+	result.Issues = IssueMessages(kept)
+}
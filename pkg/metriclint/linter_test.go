@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLinterOptions(t *testing.T) {
+	spec := MetricSpec{Name: "lint_queue_depth", Type: MetricTypeGauge}
+
+	var tests = []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "default rules",
+			expected: []string{RuleHelpMissing},
+		},
+		{
+			name:     "opt-in rule enabled",
+			opts:     []Option{EnableRules(RuleUnitSuffix)},
+			expected: []string{RuleHelpMissing, RuleUnitSuffix},
+		},
+		{
+			name: "default rule disabled",
+			opts: []Option{DisableRules(RuleHelpMissing)},
+		},
+		{
+			name:     "custom unit",
+			opts:     []Option{EnableRules(RuleUnitSuffix), WithUnits(map[string]string{"depth": "depth"})},
+			expected: []string{RuleHelpMissing},
+		},
+		{
+			name: "ignored metric",
+			opts: []Option{IgnoreMetrics("lint_.*")},
+		},
+		{
+			name:     "disable wins over enable",
+			opts:     []Option{EnableRules(RuleUnitSuffix), DisableRules(RuleUnitSuffix)},
+			expected: []string{RuleHelpMissing},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewLinter(tc.opts...).Lint(spec)
+			var ids []string
+			for _, issue := range result.Findings {
+				ids = append(ids, issue.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, ids)
+			}
+			assertConsistent(t, result)
+		})
+	}
+}
+
+func TestLinterWithSeverities(t *testing.T) {
+	spec := MetricSpec{Name: "lint_queue_depth", Type: MetricTypeGauge}
+
+	result := NewLinter(WithSeverities(map[string]Severity{RuleHelpMissing: SeverityWarning})).Lint(spec)
+	if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityWarning {
+		t.Errorf("expected: %s %s, but got: %v", RuleHelpMissing, SeverityWarning, result.Findings)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for unknown rule")
+		}
+	}()
+	NewLinter(WithSeverities(map[string]Severity{"help-mising": SeverityWarning}))
+}
+
+func TestLinterLintVector(t *testing.T) {
+	spec := MetricSpec{Name: "lint_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter}
+
+	result := NewLinter().LintVector(spec)
+	if len(result.Findings) != 1 || result.Findings[0].ID != RuleVectorLabels {
+		t.Errorf("expected: %s, but got: %v", LintErrMsgVectorNoLabels, result.Findings)
+	}
+
+	result = NewLinter(DisableRules(RuleVectorLabels)).LintVector(spec)
+	if len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+}
+
+func TestNewLinterUnknownRule(t *testing.T) {
+	defer func() {
+		expected := fmt.Sprintf("metriclint: unknown rule %q", "no-such-rule")
+		if r := recover(); r != expected {
+			t.Errorf("expected: %s, but got: %v", expected, r)
+		}
+	}()
+
+	NewLinter(DisableRules("no-such-rule"))
+}
+
+func TestNewLinterFromConfigEnableDisable(t *testing.T) {
+	linter, err := NewLinterFromConfig(&Config{Enable: []string{RuleUnitSuffix}, Disable: []string{RuleHelpMissing}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := linter.Lint(MetricSpec{Name: "lint_queue_depth", Type: MetricTypeGauge})
+	if len(result.Findings) != 1 || result.Findings[0].ID != RuleUnitSuffix {
+		t.Errorf("expected: %s, but got: %v", fmt.Sprintf(LintErrMsgUnknownUnit, "depth"), result.Findings)
+	}
+}
+
+func TestLinterWithCardinalityLabels(t *testing.T) {
+	spec := MetricSpec{Name: "lint_requests_total", Help: "this is help message", Type: MetricTypeCounter, VariableLabels: []string{"path", "tenant"}}
+
+	result := NewLinter(WithCardinalityLabels(CardinalityLabelRule{Deny: []string{"tenant"}})).LintVector(spec)
+	expected := fmt.Sprintf(LintErrMsgHighCardinalityLabel, "tenant")
+	if len(result.Findings) != 1 || result.Findings[0].Message != expected {
+		t.Errorf("expected: %s, but got: %v", expected, result.Findings)
+	}
+	assertConsistent(t, result)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIssues matches every *LintError with errors.Is, for callers which don't need the results.
+var ErrIssues = errors.New("metriclint: metrics failed linting")
+
+// LintError is returned by the strict entry points when metrics fail linting, i.e. have issues failing
+// the verdict of the linter, see Linter.Judge: Check, a promadapter.LintingRegisterer rejecting collectors.
+// Callers extract the results with errors.As.
+type LintError struct {
+	// Results of the failing metrics, with all their issues.
+	Results []*LintResult
+}
+
+func (e *LintError) Error() string {
+	results := make([]string, 0, len(e.Results))
+	for _, result := range e.Results {
+		results = append(results, result.String())
+	}
+
+	return fmt.Sprintf("metriclint: %d metrics failed linting: %s", len(e.Results), strings.Join(results, "; "))
+}
+
+// Is reports whether target is ErrIssues.
+func (e *LintError) Is(target error) bool {
+	return target == ErrIssues
+}
+
+// Check lints metrics with the default rules, see Linter.Check.
+func Check(specs ...MetricSpec) error {
+	return NewLinter().Check(specs...)
+}
+
+// Check lints metrics like LintAll and returns a *LintError holding the results failing the verdict of the
+// linter, if any, so that initialization code can fail fast. With the default FailOn, warnings alone don't
+// fail the check.
+func (l *Linter) Check(specs ...MetricSpec) error {
+	results, err := l.LintAll(specs...)
+	if err != nil {
+		return err
+	}
+
+	var failed []*LintResult
+	for _, result := range results {
+		if l.Judge([]*LintResult{result}).Failed() {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) > 0 {
+		return &LintError{Results: failed}
+	}
+
+	return nil
+}
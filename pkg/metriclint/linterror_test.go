@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	good := MetricSpec{Name: "jobs_total", Help: "Number of jobs.", Type: MetricTypeCounter}
+	warning := MetricSpec{Name: "job_success_percent", Help: "Share of successful jobs.", Type: MetricTypeGauge}
+	bad := MetricSpec{Name: "jobDuration", Help: "Duration of jobs.", Type: MetricTypeGauge}
+
+	if err := Check(good, warning); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := fmt.Errorf("init: %w", Check(good, bad))
+	if !errors.Is(err, ErrIssues) {
+		t.Errorf("expected: %v, but got: %v", ErrIssues, err)
+	}
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Results) != 1 || lintErr.Results[0].MetricName != "jobDuration" {
+		t.Fatalf("expected: a LintError of jobDuration, but got: %v", err)
+	}
+	expected := "metriclint: 1 metrics failed linting: " + lintErr.Results[0].String()
+	if lintErr.Error() != expected {
+		t.Errorf("expected: %s, but got: %s", expected, lintErr.Error())
+	}
+
+	err = NewLinter(FailOn(SeverityWarning)).Check(good, warning)
+	if !errors.As(err, &lintErr) || len(lintErr.Results) != 1 || lintErr.Results[0].MetricName != "job_success_percent" {
+		t.Errorf("expected: a LintError of job_success_percent, but got: %v", err)
+	}
+
+	if err := Check(MetricSpec{Name: "jobs", Type: "meter"}); err == nil || errors.Is(err, ErrIssues) {
+		t.Errorf("expected an unknown type error, but got: %v", err)
+	}
+}
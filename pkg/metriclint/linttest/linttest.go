@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package linttest provides assertions on lint results for tests, including the tests of custom rules.
+package linttest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// AssertIssues fails the test unless the issues of the result are reported by exactly the wanted rules,
+// in any order. A rule reporting several issues is wanted as many times. Issues without rule ID are
+// matched by their message. The failure message lists the missing and unexpected issues diff-style.
+func AssertIssues(t testing.TB, result *metriclint.LintResult, wantRuleIDs ...string) {
+	t.Helper()
+
+	if diff := DiffIssues(result, wantRuleIDs...); diff != "" {
+		t.Errorf("issues of %s differ (-want +got):\n%s", result.MetricName, diff)
+	}
+}
+
+// AssertNoIssues fails the test if the result has any issue.
+func AssertNoIssues(t testing.TB, result *metriclint.LintResult) {
+	t.Helper()
+
+	AssertIssues(t, result)
+}
+
+// DiffIssues returns the missing issues, prefixed with "-", and the unexpected ones, prefixed with "+",
+// one per line and sorted, or an empty string if the issues are reported by exactly the wanted rules.
+func DiffIssues(result *metriclint.LintResult, wantRuleIDs ...string) string {
+	want := map[string]int{}
+	for _, id := range wantRuleIDs {
+		want[id]++
+	}
+
+	var lines []string
+	for _, issue := range result.Findings {
+		key := issue.ID
+		if key == "" {
+			key = issue.Message
+		}
+		if want[key] > 0 {
+			want[key]--
+			continue
+		}
+		if issue.ID == "" {
+			lines = append(lines, "+ "+issue.Message)
+		} else {
+			lines = append(lines, fmt.Sprintf("+ %s: %s", issue.ID, issue.Message))
+		}
+	}
+	for id, n := range want {
+		for i := 0; i < n; i++ {
+			lines = append(lines, "- "+id)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linttest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// recorder is a testing.TB recording the failures instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertIssues(t *testing.T) {
+	result := metriclint.LintSpec(metriclint.MetricSpec{Name: "lint_requests", Type: metriclint.MetricTypeCounter})
+
+	var tests = []struct {
+		name     string
+		want     []string
+		expected string
+	}{
+		{
+			name: "same rules in another order",
+			want: []string{metriclint.RuleCounterTotalSuffix, metriclint.RuleHelpMissing},
+		},
+		{
+			name: "missing and unexpected rules",
+			want: []string{metriclint.RuleCounterTotalSuffix, metriclint.RuleNonBaseUnit},
+			expected: "issues of lint_requests differ (-want +got):\n" +
+				"+ help-missing: no help text\n" +
+				"- non-base-unit\n",
+		},
+		{
+			name: "rule wanted twice",
+			want: []string{metriclint.RuleCounterTotalSuffix, metriclint.RuleHelpMissing, metriclint.RuleHelpMissing},
+			expected: "issues of lint_requests differ (-want +got):\n" +
+				"- help-missing\n",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			AssertIssues(r, result, tc.want...)
+
+			var got string
+			if len(r.errors) > 0 {
+				got = r.errors[0]
+			}
+			if got != tc.expected {
+				t.Errorf("expected: %q, but got: %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAssertNoIssues(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertNoIssues(r, &metriclint.LintResult{MetricName: "lint", Findings: []metriclint.Issue{{Message: "custom problem"}}})
+
+	expected := "issues of lint differ (-want +got):\n+ custom problem\n"
+	if len(r.errors) != 1 || r.errors[0] != expected {
+		t.Errorf("expected: %q, but got: %q", expected, r.errors)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"sync/atomic"
+)
+
+// Logger receives debug information from the linter, such as skipped rules or
+// detected input encodings. It is meant for diagnosing configuration problems.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// LoggerFunc adapts an ordinary printf style function to a Logger, e.g. LoggerFunc(log.Printf).
+type LoggerFunc func(format string, args ...interface{})
+
+// Debugf calls f(format, args...).
+func (f LoggerFunc) Debugf(format string, args ...interface{}) {
+	f(format, args...)
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+
+type loggerHolder struct {
+	Logger
+}
+
+var logger atomic.Value
+
+func init() {
+	logger.Store(loggerHolder{noopLogger{}})
+}
+
+// SetLogger sets the logger used by the package. A nil logger disables logging, which is the default.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = noopLogger{}
+	}
+	logger.Store(loggerHolder{l})
+}
+
+// Debugf logs through the logger set with SetLogger, so that the packages built on the linter, like
+// promadapter, log their debug information to the same logger.
+func Debugf(format string, args ...interface{}) {
+	debugf(format, args...)
+}
+
+// debugf logs through the package logger.
+func debugf(format string, args ...interface{}) {
+	logger.Load().(loggerHolder).Debugf(format, args...)
+}
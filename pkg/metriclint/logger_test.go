@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	var logs []string
+	SetLogger(LoggerFunc(func(format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}))
+	defer SetLogger(nil)
+
+	debugf("skipping rule %s", "lint_test")
+	if len(logs) != 1 || logs[0] != "skipping rule lint_test" {
+		t.Errorf("expected one log, but got: %v", logs)
+	}
+
+	SetLogger(nil)
+	debugf("should not panic with %s logger", "noop")
+	if len(logs) != 1 {
+		t.Errorf("expected no more log after resetting logger, but got: %v", logs)
+	}
+}
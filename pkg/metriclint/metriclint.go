@@ -19,130 +19,122 @@ limitations under the License.
 //
 // metriclint provides a ability to lint a metric at the registry which is different with promlint.
 // The lint rules also base on promlint but we may add more rules if necessary.
+//
+// The rules operate on MetricSpec, so the package doesn't depend on any instrumentation library.
+// Adapters for client_golang live in the promadapter package.
 package metriclint
 
 import (
+	"fmt"
 	"strings"
-
-	"github.com/prometheus/client_golang/prometheus"
 )
 
+// APIVersion is the version of the exported API of this package.
+// See docs/API.md for the stability policy it stands for.
+const APIVersion = "v1alpha3"
+
 // LintResult represents lint result of a specific metric.
 type LintResult struct {
 	// The FQName of a metric.
 	MetricName string
 
 	// one or more lint errors of the metric.
+	//
+	// Deprecated: use Findings, Issues holds the messages of Findings until consumers migrated.
 	Issues []string
+
+	// one or more lint errors of the metric, in the order the rules run: the common rules, the rules of
+	// the metric type, then the rules configured on the Linter. An issue is reported once, even if several
+	// labels or checks lead to it. See SortIssues for an order by rule ID.
+	Findings []Issue
 }
 
 func (lr *LintResult) String() string {
 	return lr.MetricName + ":" + strings.Join(lr.Issues, ",")
 }
 
-func LintCounter(counterOpts prometheus.CounterOpts) *LintResult {
-	result := &LintResult{
-		MetricName: prometheus.BuildFQName(counterOpts.Namespace, counterOpts.Subsystem, counterOpts.Name),
-	}
-
-	result.Issues = append(result.Issues, commonLint(prometheus.Opts(counterOpts))...)
-
-	// lint names
-	result.Issues = append(result.Issues, lintNonHistogramNoBucket(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramSummaryNoCount(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramSummaryNoSum(result.MetricName)...)
-	result.Issues = append(result.Issues, lintCounterContainsTotal(result.MetricName)...)
-
-	// lint labels
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(counterOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(counterOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(counterOpts.ConstLabels, nil)...)
-
-	return result
-}
-
-func LintCounterVector(counterOpts prometheus.CounterOpts, labelNames []string) *LintResult {
-	result := LintCounter(counterOpts)
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(nil, labelNames)...)
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(nil, labelNames)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(nil, labelNames)...)
-
-	return result
-}
-
-func LintGauge(gaugeOpts prometheus.GaugeOpts) *LintResult {
-	result := &LintResult{
-		MetricName: prometheus.BuildFQName(gaugeOpts.Namespace, gaugeOpts.Subsystem, gaugeOpts.Name),
-	}
-
-	result.Issues = append(result.Issues, commonLint(prometheus.Opts(gaugeOpts))...)
-	result.Issues = append(result.Issues, lintNonCounterNoTotal(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramNoBucket(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramSummaryNoCount(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramSummaryNoSum(result.MetricName)...)
-
-	// lint labels
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(gaugeOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(gaugeOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(gaugeOpts.ConstLabels, nil)...)
-
-	return result
-}
-
-func LintGaugeVector(gaugeOpts prometheus.GaugeOpts, labelNames []string) *LintResult {
-	result := LintGauge(gaugeOpts)
-
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(nil, labelNames)...)
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(nil, labelNames)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(nil, labelNames)...)
-
-	return result
-}
-
-func LintHistogram(histogramOpts prometheus.HistogramOpts) *LintResult {
+// LintSpec lints a metric with the rules of its type, MetricTypeUntyped metrics with the common rules only.
+// It panics on an unknown metric type.
+func LintSpec(spec MetricSpec) *LintResult {
 	result := &LintResult{
-		MetricName: prometheus.BuildFQName(histogramOpts.Namespace, histogramOpts.Subsystem, histogramOpts.Name),
+		MetricName: spec.FQName(),
 	}
 
-	result.Issues = append(result.Issues, commonLint(histogramOpts)...)
-	result.Issues = append(result.Issues, lintNonCounterNoTotal(result.MetricName)...)
-
-	// lint labels
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(histogramOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(histogramOpts.ConstLabels, nil)...)
-
-	return result
-}
-
-func LintHistogramVector(histogramOpts prometheus.HistogramOpts, labelNames []string) *LintResult {
-	result := LintHistogram(histogramOpts)
-	result.Issues = append(result.Issues, lintNonSummaryNoLabelQuantile(nil, labelNames)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(nil, labelNames)...)
-
-	return result
-}
-
-func LintSummary(summaryOpts prometheus.SummaryOpts) *LintResult {
-	result := &LintResult{
-		MetricName: prometheus.BuildFQName(summaryOpts.Namespace, summaryOpts.Subsystem, summaryOpts.Name),
+	result.AddIssues(commonLint(spec)...)
+
+	switch spec.Type {
+	case MetricTypeCounter:
+		// lint names
+		result.AddRuleMessages(RuleNonHistogramBucketSuffix, lintNonHistogramNoBucket(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramCountSuffix, lintNonHistogramSummaryNoCount(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramSumSuffix, lintNonHistogramSummaryNoSum(result.MetricName)...)
+		result.AddRuleMessages(RuleCounterTotalSuffix, lintCounterContainsTotal(result.MetricName)...)
+
+		// lint labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, spec.ConstLabels, nil)...)
+
+		// lint vector labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(nil, spec.VariableLabels)...)
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(nil, spec.VariableLabels)...)
+	case MetricTypeGauge:
+		result.AddRuleMessages(RuleNonCounterTotalSuffix, lintNonCounterNoTotal(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramBucketSuffix, lintNonHistogramNoBucket(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramCountSuffix, lintNonHistogramSummaryNoCount(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramSumSuffix, lintNonHistogramSummaryNoSum(result.MetricName)...)
+
+		// lint labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, spec.ConstLabels, nil)...)
+
+		// lint vector labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(nil, spec.VariableLabels)...)
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(nil, spec.VariableLabels)...)
+	case MetricTypeHistogram:
+		result.AddRuleMessages(RuleNonCounterTotalSuffix, lintNonCounterNoTotal(result.MetricName)...)
+		result.AddIssues(NativeHistogramRule{}.issues(spec)...)
+		result.AddIssues(BucketsRule{}.issues(spec)...)
+		result.AddRuleMessages(RuleHistogramBucketScale, lintBucketScale(result.MetricName, spec.Unit, spec.Buckets)...)
+
+		// lint labels
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, spec.ConstLabels, nil)...)
+
+		// lint vector labels
+		result.AddRuleMessages(RuleNonSummaryQuantileLabel, lintNonSummaryNoLabelQuantile(nil, spec.VariableLabels)...)
+	case MetricTypeSummary:
+		result.AddRuleMessages(RuleNonCounterTotalSuffix, lintNonCounterNoTotal(result.MetricName)...)
+		result.AddRuleMessages(RuleNonHistogramBucketSuffix, lintNonHistogramNoBucket(result.MetricName)...)
+		result.AddIssues(SummaryRule{}.issues(spec)...)
+
+		// lint labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, spec.ConstLabels, nil)...)
+
+		// lint vector labels
+		result.AddRuleMessages(RuleNonHistogramLeLabel, lintNonHistogramNoLabelLe(nil, spec.VariableLabels)...)
+	case MetricTypeUntyped:
+		// lint labels
+		result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(spec.ConstLabels, nil)...)
+		result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, spec.ConstLabels, nil)...)
+	default:
+		panic(fmt.Sprintf("unknow metric type: %q", spec.Type))
 	}
 
-	result.Issues = append(result.Issues, commonLint(summaryOpts)...)
-	result.Issues = append(result.Issues, lintNonCounterNoTotal(result.MetricName)...)
-	result.Issues = append(result.Issues, lintNonHistogramNoBucket(result.MetricName)...)
+	result.AddRuleMessages(RuleLabelCamelCase, lintLabelNameCamelCase(nil, spec.VariableLabels)...)
+	result.AddRuleMessages(RuleLabelRepeatsName, lintLabelNameRepeatsMetricName(result.MetricName, nil, spec.VariableLabels)...)
+	result.AddRuleMessages(RuleLabelShadowsConstLabel, lintLabelNameShadowsConstLabel(spec.ConstLabels, spec.VariableLabels)...)
+	result.AddRuleMessages(RuleLabelDuplicate, lintDuplicateLabelNames(spec.VariableLabels)...)
+	result.AddRuleMessages(RuleLabelReservedPrefix, lintLabelNameReservedPrefix(spec.ConstLabels, spec.VariableLabels)...)
 
-	// lint labels
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(summaryOpts.ConstLabels, nil)...)
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(summaryOpts.ConstLabels, nil)...)
+	suggest(spec, result.Findings)
+	result.dedupe()
 
 	return result
 }
-
-func LintSummaryVector(summaryOpts prometheus.SummaryOpts, labelNames []string) *LintResult {
-	result := LintSummary(summaryOpts)
-	result.Issues = append(result.Issues, lintNonHistogramNoLabelLe(nil, labelNames)...)
-
-	result.Issues = append(result.Issues, lintLabelNameCamelCase(nil, labelNames)...)
-
-	return result
-}
\ No newline at end of file
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMinimalBuild builds the library packages with the metriclint_minimal tag, as documented in the README, and
+// checks the package only depends on the standard library then.
+func TestMinimalBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the minimal build in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	if out, err := exec.Command(goTool, "build", "-tags", "metriclint_minimal", "./...").CombinedOutput(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+
+	out, err := exec.Command(goTool, "list", "-tags", "metriclint_minimal", "-deps",
+		"-f", "{{if not .Standard}}{{.ImportPath}}{{end}}", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	expected := "github.com/promlint/promlint/pkg/metriclint"
+	if deps := strings.TrimSpace(string(out)); deps != expected {
+		t.Errorf("expected: %s, but got: %s", expected, deps)
+	}
+}
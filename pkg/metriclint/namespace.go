@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	LintErrMsgNamespaceMissing    = `metric should have a namespace`
+	LintErrMsgNamespaceNotAllowed = `namespace %q is not allowed by the namespace policy`
+	LintErrMsgSubsystemMissing    = `metric should have a subsystem`
+	LintErrMsgSubsystemNotAllowed = `subsystem %q is not allowed by the namespace policy`
+)
+
+// NamespacePolicy requires metrics to be prefixed with the namespace, and optionally the subsystem, of the
+// owning component, e.g. "apiserver" or "kubelet".
+//
+// A spec without namespace and subsystem, such as a gathered family, is checked by its name: the first
+// segment is the namespace and, if RequireSubsystem is set, the second one is the subsystem.
+type NamespacePolicy struct {
+	// Allowed namespaces. Any namespace is allowed if both Namespaces and Pattern are empty.
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+
+	// Regular expression, anchored to the whole namespace, matching the allowed namespaces in addition to Namespaces.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// Whether a subsystem is required.
+	RequireSubsystem bool `json:"requireSubsystem,omitempty" yaml:"requireSubsystem,omitempty"`
+
+	// Allowed subsystems, any subsystem if empty.
+	Subsystems []string `json:"subsystems,omitempty" yaml:"subsystems,omitempty"`
+}
+
+// WithNamespacePolicy enables RuleNamespacePolicy with the policy. It panics on a malformed pattern,
+// which is a programming error.
+func WithNamespacePolicy(policy NamespacePolicy) Option {
+	return func(l *Linter) {
+		pattern, err := policy.compile()
+		if err != nil {
+			panic(fmt.Sprintf("metriclint: namespace policy: %v", err))
+		}
+		l.namespace = &compiledNamespacePolicy{policy: policy, pattern: pattern}
+		l.enabled[RuleNamespacePolicy] = true
+	}
+}
+
+// Lint checks the namespace and the subsystem of the metric.
+func (p NamespacePolicy) Lint(spec MetricSpec) []string {
+	pattern, err := p.compile()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	return compiledNamespacePolicy{policy: p, pattern: pattern}.lint(spec)
+}
+
+// compile returns the anchored pattern, nil without pattern.
+func (p NamespacePolicy) compile() (*regexp.Regexp, error) {
+	if p.Pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + p.Pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("malformed regular expression: %v", err)
+	}
+
+	return re, nil
+}
+
+// compiledNamespacePolicy is a NamespacePolicy with its pattern compiled once.
+type compiledNamespacePolicy struct {
+	policy  NamespacePolicy
+	pattern *regexp.Regexp
+}
+
+func (p compiledNamespacePolicy) lint(spec MetricSpec) (issues []string) {
+	namespace, subsystem := spec.Namespace, spec.Subsystem
+	if namespace == "" && subsystem == "" {
+		// The name is joined already, at least one segment must be left for the name itself.
+		segments := strings.Split(spec.Name, "_")
+		if len(segments) > 1 {
+			namespace = segments[0]
+		}
+		if len(segments) > 2 {
+			subsystem = segments[1]
+		}
+	}
+
+	switch {
+	case namespace == "":
+		issues = append(issues, LintErrMsgNamespaceMissing)
+	case !p.allowed(namespace):
+		issues = append(issues, fmt.Sprintf(LintErrMsgNamespaceNotAllowed, namespace))
+	}
+
+	if !p.policy.RequireSubsystem {
+		return issues
+	}
+	switch {
+	case subsystem == "":
+		issues = append(issues, LintErrMsgSubsystemMissing)
+	case len(p.policy.Subsystems) > 0 && !contains(p.policy.Subsystems, subsystem):
+		issues = append(issues, fmt.Sprintf(LintErrMsgSubsystemNotAllowed, subsystem))
+	}
+
+	return issues
+}
+
+func (p compiledNamespacePolicy) allowed(namespace string) bool {
+	if len(p.policy.Namespaces) == 0 && p.pattern == nil {
+		return true
+	}
+
+	return contains(p.policy.Namespaces, namespace) || p.pattern != nil && p.pattern.MatchString(namespace)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNamespacePolicy(t *testing.T) {
+	policy := NamespacePolicy{
+		Namespaces:       []string{"apiserver"},
+		Pattern:          "kube.*",
+		RequireSubsystem: true,
+		Subsystems:       []string{"storage", "watch"},
+	}
+
+	var tests = []struct {
+		name     string
+		policy   NamespacePolicy
+		spec     MetricSpec
+		expected []string
+	}{
+		{
+			name:   "any namespace allowed by default",
+			policy: NamespacePolicy{},
+			spec:   MetricSpec{Namespace: "etcd", Name: "requests_total"},
+		},
+		{
+			name:     "missing namespace",
+			policy:   NamespacePolicy{},
+			spec:     MetricSpec{Name: "requests"},
+			expected: []string{LintErrMsgNamespaceMissing},
+		},
+		{
+			name:   "namespace in allowlist",
+			policy: policy,
+			spec:   MetricSpec{Namespace: "apiserver", Subsystem: "storage", Name: "objects"},
+		},
+		{
+			name:   "namespace matching pattern",
+			policy: policy,
+			spec:   MetricSpec{Namespace: "kubelet", Subsystem: "watch", Name: "events_total"},
+		},
+		{
+			name:   "pattern anchored to the whole namespace",
+			policy: policy,
+			spec:   MetricSpec{Namespace: "mykubelet", Subsystem: "watch", Name: "events_total"},
+			expected: []string{
+				fmt.Sprintf(LintErrMsgNamespaceNotAllowed, "mykubelet"),
+			},
+		},
+		{
+			name:     "missing subsystem",
+			policy:   policy,
+			spec:     MetricSpec{Namespace: "apiserver", Name: "requests_total"},
+			expected: []string{LintErrMsgSubsystemMissing},
+		},
+		{
+			name:   "subsystem not allowed",
+			policy: policy,
+			spec:   MetricSpec{Namespace: "etcd", Subsystem: "disk", Name: "writes_total"},
+			expected: []string{
+				fmt.Sprintf(LintErrMsgNamespaceNotAllowed, "etcd"),
+				fmt.Sprintf(LintErrMsgSubsystemNotAllowed, "disk"),
+			},
+		},
+		{
+			name:   "joined name",
+			policy: policy,
+			spec:   MetricSpec{Name: "apiserver_storage_objects"},
+		},
+		{
+			name:     "joined name without subsystem",
+			policy:   policy,
+			spec:     MetricSpec{Name: "apiserver_requests"},
+			expected: []string{LintErrMsgSubsystemMissing},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := tc.policy.Lint(tc.spec)
+			if !reflect.DeepEqual(issues, tc.expected) {
+				t.Errorf("expected: %q, but got: %q", tc.expected, issues)
+			}
+		})
+	}
+}
+
+func TestWithNamespacePolicy(t *testing.T) {
+	spec := MetricSpec{Namespace: "etcd", Name: "requests", Help: "Number of requests.", Type: MetricTypeGauge}
+
+	if ids := issueIDs(NewLinter().Lint(spec)); len(ids) != 0 {
+		t.Errorf("expected no issue without policy, but got: %v", ids)
+	}
+
+	linter := NewLinter(WithNamespacePolicy(NamespacePolicy{Namespaces: []string{"apiserver"}}))
+	expected := []string{RuleNamespacePolicy}
+	if ids := issueIDs(linter.Lint(spec)); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected: %v, but got: %v", expected, ids)
+	}
+
+	linter = NewLinter(WithNamespacePolicy(NamespacePolicy{Namespaces: []string{"apiserver"}}), DisableRules(RuleNamespacePolicy))
+	if ids := issueIDs(linter.Lint(spec)); len(ids) != 0 {
+		t.Errorf("expected no issue with the rule disabled, but got: %v", ids)
+	}
+}
+
+func TestWithNamespacePolicyMalformedPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for malformed pattern")
+		}
+	}()
+	NewLinter(WithNamespacePolicy(NamespacePolicy{Pattern: "kube("}))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	LintErrMsgNumericFragment = `name segment %q looks like a %s, consider a label or removing it`
+)
+
+var (
+	dateSegment       = regexp.MustCompile(`^(19|20)\d{2}((0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01]))?$`)
+	versionSegment    = regexp.MustCompile(`^v\d+$`)
+	percentileSegment = regexp.MustCompile(`^p\d{1,3}$`)
+	numberSegment     = regexp.MustCompile(`^\d+$`)
+)
+
+// DefaultNumericFragmentAllowlist are segments with digits which are fine in metric names.
+var DefaultNumericFragmentAllowlist = []string{
+	"http2",
+	"ipv4",
+	"ipv6",
+	"k8s",
+	"md5",
+	"s3",
+	"sha1",
+	"sha256",
+	"x509",
+}
+
+// NumericFragmentRule flags name segments which look like embedded dates, versions, percentiles or
+// plain numbers, e.g. "2024", "v1", "p95". Those usually come from code generation bugs or belong
+// into a label.
+type NumericFragmentRule struct {
+	// Segments which are fine, DefaultNumericFragmentAllowlist if nil.
+	Allowed []string
+}
+
+// Lint checks the segments of a metric name.
+func (r NumericFragmentRule) Lint(name string) (issues []string) {
+	allowed := r.Allowed
+	if allowed == nil {
+		allowed = DefaultNumericFragmentAllowlist
+	}
+
+next:
+	for _, segment := range strings.Split(strings.ToLower(name), "_") {
+		for _, a := range allowed {
+			if segment == a {
+				continue next
+			}
+		}
+
+		var kind string
+		switch {
+		case dateSegment.MatchString(segment):
+			kind = "date"
+		case versionSegment.MatchString(segment):
+			kind = "version"
+		case percentileSegment.MatchString(segment):
+			kind = "percentile"
+		case numberSegment.MatchString(segment):
+			kind = "number"
+		default:
+			continue
+		}
+		issues = append(issues, fmt.Sprintf(LintErrMsgNumericFragment, segment, kind))
+	}
+
+	return issues
+}
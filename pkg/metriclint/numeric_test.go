@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNumericFragmentRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		rule           NumericFragmentRule
+		metric         string
+		expectedResult string
+	}{
+		{
+			name:           "date",
+			metric:         "lint_2024_requests_total",
+			expectedResult: fmt.Sprintf(LintErrMsgNumericFragment, "2024", "date"),
+		},
+		{
+			name:   "version",
+			metric: "lint_v1_2_requests_total",
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgNumericFragment, "v1", "version"),
+				fmt.Sprintf(LintErrMsgNumericFragment, "2", "number"),
+			}, ","),
+		},
+		{
+			name:           "percentile",
+			metric:         "lint_latency_p95_seconds",
+			expectedResult: fmt.Sprintf(LintErrMsgNumericFragment, "p95", "percentile"),
+		},
+		{
+			name:   "default allowlist",
+			metric: "lint_http2_ipv6_requests_total",
+		},
+		{
+			name:           "custom allowlist",
+			rule:           NumericFragmentRule{Allowed: []string{"v1"}},
+			metric:         "lint_v1_http2_requests_total",
+			expectedResult: "",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := strings.Join(tc.rule.Lint(tc.metric), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LintErrMsgOpenMetricsCounterSample     = `counter samples should be named %q, not %q`
+	LintErrMsgOpenMetricsCounterFamilyName = `counter family name should not end with "_total", its samples add it`
+	LintErrMsgOpenMetricsCreatedType       = `"_created" series are only allowed for counters, histograms and summaries, not %s`
+	LintErrMsgOpenMetricsCreatedOrphan     = `"_created" series %s has no matching %q series`
+	LintErrMsgOpenMetricsCreatedValue      = `"_created" value should be a timestamp in seconds, not %v`
+	LintErrMsgOpenMetricsInfoType          = `"_info" metrics should be info or gauge metrics, not %s`
+	LintErrMsgOpenMetricsInfoValue         = `"_info" metrics should have value 1, not %v`
+	LintErrMsgOpenMetricsUnitSuffix        = `metric name should end with its unit %q`
+)
+
+// OpenMetrics types, as in TYPE lines.
+const (
+	omCounter        = "counter"
+	omGauge          = "gauge"
+	omHistogram      = "histogram"
+	omGaugeHistogram = "gaugehistogram"
+	omSummary        = "summary"
+	omInfo           = "info"
+	omStateSet       = "stateset"
+	omUnknown        = "unknown"
+)
+
+// omTypes maps the OpenMetrics types to the metric types linting them.
+var omTypes = map[string]MetricType{
+	omCounter:        MetricTypeCounter,
+	omGauge:          MetricTypeGauge,
+	omHistogram:      MetricTypeHistogram,
+	omGaugeHistogram: MetricTypeHistogram,
+	omSummary:        MetricTypeSummary,
+	omInfo:           MetricTypeUntyped,
+	omStateSet:       MetricTypeUntyped,
+	omUnknown:        MetricTypeUntyped,
+}
+
+// omSuffixes are the sample name suffixes a family can expose.
+var omSuffixes = []string{"_total", "_created", "_bucket", "_count", "_sum", "_gcount", "_gsum", "_info"}
+
+type omSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+type omFamily struct {
+	name    string
+	typ     string
+	help    string
+	unit    string
+	samples []omSample
+}
+
+// owns reports whether a sample name belongs to the family.
+func (f *omFamily) owns(name string) bool {
+	if name == f.name {
+		return true
+	}
+	for _, suffix := range omSuffixes {
+		if name == f.name+suffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LintOpenMetrics parses an OpenMetrics 1.0 text payload and lints each of its families, sorted by
+// name, with the default rules and the OpenMetrics rules.
+func LintOpenMetrics(r io.Reader) ([]*LintResult, error) {
+	return NewLinter().LintOpenMetrics(r)
+}
+
+// LintOpenMetrics parses an OpenMetrics 1.0 text payload and lints each of its families, sorted by
+// name, with the rules of the linter and the OpenMetrics rules.
+//
+// The families are linted by the names of their samples, e.g. "http_requests_total" for the counter
+// family "http_requests". Info, stateset and unknown families are linted as untyped. The issues of the
+// OpenMetrics rules follow the policy of the linter like the others, see Linter.AddRuleMessages.
+func (l *Linter) LintOpenMetrics(r io.Reader) ([]*LintResult, error) {
+	r, err := NewExpositionReader(r)
+	if err != nil {
+		return nil, err
+	}
+	families, err := parseOpenMetrics(r)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	results := make([]*LintResult, len(families))
+	l.parallel(len(families), func(i int) {
+		f := families[i]
+		result := l.Lint(f.spec())
+		l.AddRuleMessages(result, RuleOpenMetricsCounterTotal, lintOMCounterTotal(f)...)
+		l.AddRuleMessages(result, RuleOpenMetricsCreated, lintOMCreated(f)...)
+		l.AddRuleMessages(result, RuleOpenMetricsInfo, lintOMInfo(f)...)
+		l.AddRuleMessages(result, RuleOpenMetricsUnit, lintOMUnit(f)...)
+		results[i] = result
+	})
+
+	return results, nil
+}
+
+// OpenMetricsSpecs converts the families of an OpenMetrics 1.0 text payload into the MetricSpecs they are
+// linted as by LintOpenMetrics, sorted by name.
+func OpenMetricsSpecs(r io.Reader) ([]MetricSpec, error) {
+	r, err := NewExpositionReader(r)
+	if err != nil {
+		return nil, err
+	}
+	families, err := parseOpenMetrics(r)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]MetricSpec, 0, len(families))
+	for _, f := range families {
+		specs = append(specs, f.spec())
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+
+	return specs, nil
+}
+
+// spec converts the family into the MetricSpec of its exposed name.
+func (f *omFamily) spec() MetricSpec {
+	name := f.name
+	switch f.typ {
+	case omCounter:
+		name = strings.TrimSuffix(f.name, "_total") + "_total"
+	case omInfo:
+		name = f.name + "_info"
+	}
+
+	var labelNames []string
+	for _, s := range f.samples {
+		for labelName := range s.labels {
+			if labelName == LabelLe && strings.HasSuffix(s.name, "_bucket") || labelName == LabelQuantile && f.typ == omSummary {
+				continue
+			}
+			labelNames = append(labelNames, labelName)
+		}
+	}
+
+	return MetricSpec{
+		Name:           name,
+		Help:           f.help,
+		Type:           omTypes[f.typ],
+		VariableLabels: CanonicalLabelNames(nil, labelNames),
+	}
+}
+
+func lintOMCounterTotal(f *omFamily) (issues []string) {
+	if f.typ != omCounter {
+		return nil
+	}
+	if strings.HasSuffix(f.name, "_total") {
+		return []string{LintErrMsgOpenMetricsCounterFamilyName}
+	}
+
+	reported := map[string]bool{}
+	for _, s := range f.samples {
+		if s.name != f.name+"_total" && s.name != f.name+"_created" && !reported[s.name] {
+			reported[s.name] = true
+			issues = append(issues, fmt.Sprintf(LintErrMsgOpenMetricsCounterSample, f.name+"_total", s.name))
+		}
+	}
+
+	return issues
+}
+
+func lintOMCreated(f *omFamily) (issues []string) {
+	var primary string
+	switch f.typ {
+	case omCounter:
+		primary = f.name + "_total"
+	case omHistogram, omSummary:
+		primary = f.name + "_count"
+	}
+
+	series := map[string]bool{}
+	for _, s := range f.samples {
+		if s.name == primary {
+			series[SeriesID(s.name, s.labels)] = true
+		}
+	}
+
+	for _, s := range f.samples {
+		if s.name != f.name+"_created" {
+			continue
+		}
+		if primary == "" {
+			return []string{fmt.Sprintf(LintErrMsgOpenMetricsCreatedType, f.typ)}
+		}
+		if !series[SeriesID(primary, s.labels)] {
+			issues = append(issues, fmt.Sprintf(LintErrMsgOpenMetricsCreatedOrphan, SeriesID(s.name, s.labels), primary))
+		}
+		if s.value < 0 || math.IsNaN(s.value) || math.IsInf(s.value, 0) {
+			issues = append(issues, fmt.Sprintf(LintErrMsgOpenMetricsCreatedValue, s.value))
+		}
+	}
+
+	return issues
+}
+
+func lintOMInfo(f *omFamily) (issues []string) {
+	if f.typ != omInfo && !strings.HasSuffix(f.name, "_info") {
+		return nil
+	}
+	if f.typ != omInfo && f.typ != omGauge {
+		return []string{fmt.Sprintf(LintErrMsgOpenMetricsInfoType, f.typ)}
+	}
+
+	for _, s := range f.samples {
+		if s.value != 1 {
+			issues = append(issues, fmt.Sprintf(LintErrMsgOpenMetricsInfoValue, s.value))
+			break
+		}
+	}
+
+	return issues
+}
+
+func lintOMUnit(f *omFamily) []string {
+	if f.unit == "" || strings.HasSuffix(strings.TrimSuffix(f.name, "_total"), "_"+f.unit) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf(LintErrMsgOpenMetricsUnitSuffix, f.unit)}
+}
+
+// parseOpenMetrics parses the families of an OpenMetrics text payload, in the order they appear.
+// Exemplars and timestamps are skipped.
+func parseOpenMetrics(r io.Reader) ([]*omFamily, error) {
+	var families []*omFamily
+	var current *omFamily
+	family := func(name string) *omFamily {
+		if current == nil || current.name != name {
+			current = &omFamily{name: name, typ: omUnknown}
+			families = append(families, current)
+		}
+		return current
+	}
+
+	scanner := bufio.NewScanner(r)
+	eof := false
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if eof {
+			return nil, fmt.Errorf("line %d: content after # EOF", lineNo)
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if line == "# EOF" {
+				eof = true
+				continue
+			}
+			fields := strings.SplitN(line, " ", 4)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("line %d: malformed metadata %q", lineNo, line)
+			}
+			var value string
+			if len(fields) == 4 {
+				value = fields[3]
+			}
+			switch fields[1] {
+			case "TYPE":
+				if _, ok := omTypes[value]; !ok {
+					return nil, fmt.Errorf("line %d: unknown type %q", lineNo, value)
+				}
+				family(fields[2]).typ = value
+			case "HELP":
+				family(fields[2]).help = value
+			case "UNIT":
+				family(fields[2]).unit = value
+			default:
+				return nil, fmt.Errorf("line %d: unknown metadata %q", lineNo, fields[1])
+			}
+			continue
+		}
+
+		s, err := parseOMSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		if current == nil || !current.owns(s.name) {
+			family(s.name)
+		}
+		current.samples = append(current.samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !eof {
+		return nil, fmt.Errorf("missing # EOF")
+	}
+
+	return families, nil
+}
+
+// parseOMSample parses a sample line: name, optional labels, value, optional timestamp and exemplar.
+func parseOMSample(line string) (omSample, error) {
+	s := omSample{labels: map[string]string{}}
+
+	i := strings.IndexAny(line, "{ ")
+	if i <= 0 {
+		return s, fmt.Errorf("malformed sample %q", line)
+	}
+	s.name, line = line[:i], line[i:]
+
+	if strings.HasPrefix(line, "{") {
+		line = line[1:]
+		for !strings.HasPrefix(line, "}") {
+			eq := strings.Index(line, "=\"")
+			if eq <= 0 {
+				return s, fmt.Errorf("malformed labels of %s", s.name)
+			}
+			labelName := line[:eq]
+			value, rest, err := unquoteOMLabelValue(line[eq+1:])
+			if err != nil {
+				return s, fmt.Errorf("malformed label %s of %s: %v", labelName, s.name, err)
+			}
+			s.labels[labelName] = value
+			line = strings.TrimPrefix(rest, ",")
+			if line == "" {
+				return s, fmt.Errorf("unterminated labels of %s", s.name)
+			}
+		}
+		line = line[1:]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return s, fmt.Errorf("missing value of %s", s.name)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return s, fmt.Errorf("malformed value of %s: %v", s.name, err)
+	}
+	s.value = value
+
+	return s, nil
+}
+
+// unquoteOMLabelValue unquotes the label value at the start of s and returns the rest of s.
+// OpenMetrics only escapes backslashes, double quotes and line feeds.
+func unquoteOMLabelValue(s string) (value, rest string, err error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), s[i+1:], nil
+		case '\\':
+			i++
+			if i == len(s) {
+				break
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case '"', '\\':
+				b.WriteByte(s[i])
+			default:
+				return "", "", fmt.Errorf("invalid escape %q", s[i-1:i+1])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated value")
+}
@@ -0,0 +1,212 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintOpenMetrics(t *testing.T) {
+	tests := []struct {
+		name           string
+		payload        []string
+		expectedResult []string
+	}{
+		{
+			name: "valid families",
+			payload: []string{
+				"# TYPE http_requests counter",
+				"# HELP http_requests Total number of requests.",
+				`http_requests_total{code="200"} 3`,
+				`http_requests_created{code="200"} 1.6e+09`,
+				"# TYPE build info",
+				"# HELP build Build information.",
+				`build_info{version="1.0 \"rc\""} 1`,
+				"# TYPE request_duration_seconds histogram",
+				"# UNIT request_duration_seconds seconds",
+				"# HELP request_duration_seconds Distribution of request durations.",
+				`request_duration_seconds_bucket{le="+Inf"} 1 # {trace_id="a"} 0.1`,
+				"request_duration_seconds_count 1",
+				"request_duration_seconds_sum 0.1",
+				"request_duration_seconds_created 1.6e+09",
+			},
+			expectedResult: []string{"build_info:", "http_requests_total:", "request_duration_seconds:"},
+		},
+		{
+			name: "counter samples without total",
+			payload: []string{
+				"# TYPE http_requests counter",
+				"# HELP http_requests Total number of requests.",
+				"http_requests 3",
+			},
+			expectedResult: []string{"http_requests_total:" + fmt.Sprintf(LintErrMsgOpenMetricsCounterSample, "http_requests_total", "http_requests")},
+		},
+		{
+			name: "counter family with total",
+			payload: []string{
+				"# TYPE http_requests_total counter",
+				"# HELP http_requests_total Total number of requests.",
+				"http_requests_total 3",
+			},
+			expectedResult: []string{"http_requests_total:" + LintErrMsgOpenMetricsCounterFamilyName},
+		},
+		{
+			name: "created series",
+			payload: []string{
+				"# TYPE http_requests counter",
+				"# HELP http_requests Total number of requests.",
+				`http_requests_total{code="200"} 3`,
+				`http_requests_created{code="500"} -1`,
+				"# TYPE queue_length gauge",
+				"# HELP queue_length Queue length.",
+				"queue_length 3",
+				"queue_length_created 1.6e+09",
+			},
+			expectedResult: []string{
+				"http_requests_total:" + fmt.Sprintf(LintErrMsgOpenMetricsCreatedOrphan, `http_requests_created{code="500"}`, "http_requests_total") +
+					"," + fmt.Sprintf(LintErrMsgOpenMetricsCreatedValue, -1.0),
+				"queue_length:" + fmt.Sprintf(LintErrMsgOpenMetricsCreatedType, "gauge"),
+			},
+		},
+		{
+			name: "info metrics",
+			payload: []string{
+				"# TYPE build_info gauge",
+				"# HELP build_info Build information.",
+				"build_info 2",
+				"# TYPE feature_info counter",
+				"# HELP feature_info Total number of features.",
+				"feature_info_total 1",
+			},
+			expectedResult: []string{
+				"build_info:" + fmt.Sprintf(LintErrMsgOpenMetricsInfoValue, 2.0),
+				"feature_info_total:" + fmt.Sprintf(LintErrMsgOpenMetricsInfoType, "counter"),
+			},
+		},
+		{
+			name: "unit suffix",
+			payload: []string{
+				"# TYPE request_duration gauge",
+				"# UNIT request_duration seconds",
+				"# HELP request_duration Request duration.",
+				"request_duration 1",
+			},
+			expectedResult: []string{"request_duration:" + fmt.Sprintf(LintErrMsgOpenMetricsUnitSuffix, "seconds")},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			payload := strings.Join(append(tc.payload, "# EOF"), "\n") + "\n"
+			results, err := LintOpenMetrics(strings.NewReader(payload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.String())
+				assertConsistent(t, result)
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.expectedResult, "\n") {
+				t.Errorf("expected: %v, but got: %v", tc.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestLintOpenMetricsPolicy(t *testing.T) {
+	payload := "# TYPE foo gauge\n# UNIT foo seconds\n# HELP foo Foo.\nfoo 1\n# EOF\n"
+
+	var tests = []struct {
+		name     string
+		opts     []Option
+		severity Severity
+	}{
+		{name: "default", severity: SeverityError},
+		{name: "treat", opts: []Option{Treat(RuleOpenMetricsUnit, SeverityWarning)}, severity: SeverityWarning},
+		{name: "ignore", opts: []Option{IgnoreMetrics("foo.*")}},
+		{name: "disable", opts: []Option{DisableRules(RuleOpenMetricsUnit)}},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := NewLinter(tc.opts...).LintOpenMetrics(strings.NewReader(payload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, but got: %v", results)
+			}
+			findings := results[0].Findings
+			if tc.severity == "" {
+				if len(findings) != 0 {
+					t.Errorf("expected: no issue, but got: %v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].ID != RuleOpenMetricsUnit || findings[0].Severity != tc.severity {
+				t.Errorf("expected: a %s %s issue, but got: %v", tc.severity, RuleOpenMetricsUnit, findings)
+			}
+		})
+	}
+}
+
+func TestLintOpenMetricsParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "missing eof",
+			payload:  "queue_length 1\n",
+			expected: "missing # EOF",
+		},
+		{
+			name:     "content after eof",
+			payload:  "# EOF\nqueue_length 1\n",
+			expected: "line 2: content after # EOF",
+		},
+		{
+			name:     "unknown type",
+			payload:  "# TYPE queue_length untyped\n# EOF\n",
+			expected: `line 1: unknown type "untyped"`,
+		},
+		{
+			name:     "unterminated label value",
+			payload:  "queue_length{queue=\"a} 1\n# EOF\n",
+			expected: "line 1: malformed label queue of queue_length: unterminated value",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LintOpenMetrics(strings.NewReader(tc.payload))
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("expected: %s, but got: %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"runtime"
+	"sync"
+)
+
+// WithParallelism lints the specs of LintSpecs, LintAll, LintOpenMetrics and LintRemoteWrite on n goroutines,
+// GOMAXPROCS if n is zero or negative, e.g. for registries and payloads with thousands of families. Linting is
+// serial by default: custom rules, see Rule, must be safe for concurrent use to lint in parallel.
+func WithParallelism(n int) Option {
+	return func(l *Linter) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		l.parallelism = n
+	}
+}
+
+// LintSpecs lints the specs with Lint, on the goroutines of WithParallelism, and returns their results
+// in the order of the specs.
+func (l *Linter) LintSpecs(specs []MetricSpec) []*LintResult {
+	results := make([]*LintResult, len(specs))
+	l.parallel(len(specs), func(i int) {
+		results[i] = l.Lint(specs[i])
+	})
+
+	return results
+}
+
+// parallel calls fn with the indexes from 0 to n-1, on the goroutines of WithParallelism.
+func (l *Linter) parallel(n int, fn func(i int)) {
+	workers := l.parallelism
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	indexes := make(chan int, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
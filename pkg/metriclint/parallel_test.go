@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// parallelSpecs returns n specs, every third one with issues.
+func parallelSpecs(n int) []MetricSpec {
+	specs := make([]MetricSpec, 0, n)
+	for i := 0; i < n; i++ {
+		spec := MetricSpec{Name: fmt.Sprintf("kube_object_%d_total", i), Help: "Number of objects.", Type: MetricTypeCounter, VariableLabels: []string{"namespace", "pod"}}
+		if i%3 == 0 {
+			spec.Name = fmt.Sprintf("kubeObject%d", i)
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+func TestWithParallelism(t *testing.T) {
+	specs := parallelSpecs(100)
+
+	serial, err := NewLinter().LintAll(specs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, n := range []int{0, 1, 4, 200} {
+		l := NewLinter(WithParallelism(n))
+		parallel, err := l.LintAll(specs...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Errorf("parallelism %d: expected: %v, but got: %v", n, serial, parallel)
+		}
+		if results := l.LintSpecs(specs); len(results) != len(specs) || results[3].MetricName != "kubeObject3" {
+			t.Errorf("parallelism %d: expected: results in the order of the specs, but got: %v", n, results)
+		}
+	}
+}
+
+func BenchmarkLintAll(b *testing.B) {
+	specs := parallelSpecs(5000)
+	for _, bench := range []struct {
+		name        string
+		parallelism int
+	}{{"serial", 1}, {"gomaxprocs", 0}} {
+		l := NewLinter(WithParallelism(bench.parallelism))
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := l.LintAll(specs...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
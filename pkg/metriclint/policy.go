@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PolicyBundle is a named lint policy, such as the rules of an organization, compiled into its own
+// Go module so that it can be versioned independently of the applications and of metriclint.
+type PolicyBundle struct {
+	// Unique name the bundle is referred to by, e.g. "example.com/platform".
+	Name string
+
+	// Version of the bundle, informational.
+	Version string
+
+	// Rules, enabled and disabled rules of the bundle. Bundles can't refer to other bundles.
+	Config Config
+}
+
+var (
+	policyBundlesMu sync.RWMutex
+	policyBundles   = map[string]PolicyBundle{}
+)
+
+// RegisterPolicyBundle makes a bundle available to UsePolicyBundles and to the bundles entry of a
+// Config. It's meant to be called from the init function of the package shipping the bundle, and
+// panics if the bundle has no name, is already registered or has an invalid config.
+func RegisterPolicyBundle(bundle PolicyBundle) {
+	if bundle.Name == "" {
+		panic("metriclint: policy bundle without name")
+	}
+	if len(bundle.Config.Bundles) > 0 {
+		panic(fmt.Sprintf("metriclint: policy bundle %q refers to other bundles", bundle.Name))
+	}
+	if err := bundle.Config.Validate(); err != nil {
+		panic(fmt.Sprintf("metriclint: policy bundle %q: %v", bundle.Name, err))
+	}
+
+	policyBundlesMu.Lock()
+	defer policyBundlesMu.Unlock()
+
+	if _, ok := policyBundles[bundle.Name]; ok {
+		panic(fmt.Sprintf("metriclint: policy bundle %q registered twice", bundle.Name))
+	}
+	policyBundles[bundle.Name] = bundle
+}
+
+// PolicyBundles returns the registered bundles sorted by name.
+func PolicyBundles() []PolicyBundle {
+	policyBundlesMu.RLock()
+	defer policyBundlesMu.RUnlock()
+
+	bundles := make([]PolicyBundle, 0, len(policyBundles))
+	for _, bundle := range policyBundles {
+		bundles = append(bundles, bundle)
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+
+	return bundles
+}
+
+func lookupPolicyBundle(name string) (PolicyBundle, bool) {
+	policyBundlesMu.RLock()
+	defer policyBundlesMu.RUnlock()
+
+	bundle, ok := policyBundles[name]
+	return bundle, ok
+}
+
+// UsePolicyBundles applies registered bundles: their rules run, and the rules they enable or disable are
+// enabled or disabled. It panics on unknown bundles, like NewLinter on unknown rules.
+func UsePolicyBundles(names ...string) Option {
+	return func(l *Linter) {
+		for _, name := range names {
+			bundle, ok := lookupPolicyBundle(name)
+			if !ok {
+				panic(fmt.Sprintf("metriclint: unknown policy bundle %q", name))
+			}
+
+			if bundle.Config.Profile != "" {
+				WithProfile(bundle.Config.Profile)(l)
+			}
+			for _, rule := range bundle.Config.Rules {
+				// The config was validated on registration, so its rules compile.
+				compiled, err := rule.compile()
+				if err != nil {
+					panic(err)
+				}
+				l.declarative = append(l.declarative, compiled)
+			}
+			EnableRules(bundle.Config.Enable...)(l)
+			DisableRules(bundle.Config.Disable...)(l)
+			WithSeverities(bundle.Config.Severities)(l)
+			WithSuppressions(bundle.Config.Suppressions...)(l)
+			IgnoreMetrics(bundle.Config.Ignore...)(l)
+			WithUnits(bundle.Config.Units)(l)
+			if bundle.Config.Namespace != nil {
+				WithNamespacePolicy(*bundle.Config.Namespace)(l)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func init() {
+	RegisterPolicyBundle(PolicyBundle{
+		Name:    "example.com/platform",
+		Version: "v1.2.0",
+		Config: Config{
+			Disable: []string{RuleHelpMissing},
+			Rules: []DeclarativeRule{
+				{Name: "platform-prefix", Target: DeclarativeTargetName, Pattern: "platform_.*", Mode: DeclarativeModeRequired},
+			},
+		},
+	})
+}
+
+func TestUsePolicyBundles(t *testing.T) {
+	spec := MetricSpec{Name: "lint_requests_total", Type: MetricTypeCounter}
+
+	result := NewLinter(UsePolicyBundles("example.com/platform")).Lint(spec)
+	if len(result.Findings) != 1 || result.Findings[0].ID != "platform-prefix" {
+		t.Errorf("expected: %s, but got: %v", "platform-prefix", result.Findings)
+	}
+
+	linter, err := NewLinterFromConfig(&Config{Bundles: []string{"example.com/platform"}, Disable: []string{"platform-prefix"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := linter.Lint(spec); len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+}
+
+func TestConfigUnknownPolicyBundle(t *testing.T) {
+	err := (&Config{Bundles: []string{"example.com/missing"}}).Validate()
+	expected := "invalid config:\n" + `bundles[0]: unknown policy bundle "example.com/missing"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, err)
+	}
+}
+
+func TestRegisterPolicyBundlePanics(t *testing.T) {
+	var tests = []struct {
+		name     string
+		bundle   PolicyBundle
+		expected string
+	}{
+		{
+			name:     "without name",
+			expected: "metriclint: policy bundle without name",
+		},
+		{
+			name:     "registered twice",
+			bundle:   PolicyBundle{Name: "example.com/platform"},
+			expected: fmt.Sprintf("metriclint: policy bundle %q registered twice", "example.com/platform"),
+		},
+		{
+			name:     "nested bundles",
+			bundle:   PolicyBundle{Name: "example.com/nested", Config: Config{Bundles: []string{"example.com/platform"}}},
+			expected: fmt.Sprintf("metriclint: policy bundle %q refers to other bundles", "example.com/nested"),
+		},
+		{
+			name:     "invalid config",
+			bundle:   PolicyBundle{Name: "example.com/invalid", Config: Config{Enable: []string{"no-such-rule"}}},
+			expected: fmt.Sprintf("metriclint: policy bundle %q: invalid config:\nenable[0]: unknown rule %q", "example.com/invalid", "no-such-rule"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.expected {
+					t.Errorf("expected: %s, but got: %v", tc.expected, r)
+				}
+			}()
+			RegisterPolicyBundle(tc.bundle)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package presets registers policy bundles encoding well-known instrumentation guidelines. Import it for its
+// side effect and apply a preset with metriclint.UsePolicyBundles or with the bundles entry of a config.
+package presets
+
+import "github.com/promlint/promlint/pkg/metriclint"
+
+// Kubernetes is the name of the bundle encoding the Kubernetes instrumentation guidelines.
+const Kubernetes = "kubernetes"
+
+// IDs of the rules of the Kubernetes bundle.
+const (
+	RuleKubernetesStabilityLevel  = "kubernetes-stability-level"
+	RuleKubernetesDeprecatedLabel = "kubernetes-deprecated-label"
+)
+
+// KubernetesBundle follows the Kubernetes instrumentation guidelines:
+//   - help text starts with the stability level k8s.io/component-base/metrics adds, e.g. "[ALPHA] ",
+//   - metric and label names are snake_case,
+//   - metrics are in base units and counters end with "_total",
+//   - the pod and container labels are named "pod" and "container", not "pod_name" and "container_name",
+//     which were removed from cAdvisor metrics in Kubernetes 1.16.
+var KubernetesBundle = metriclint.PolicyBundle{
+	Name:    Kubernetes,
+	Version: "v1.0.0",
+	Config: metriclint.Config{
+		Rules: []metriclint.DeclarativeRule{
+			{
+				Name:    RuleKubernetesStabilityLevel,
+				Target:  metriclint.DeclarativeTargetHelp,
+				Pattern: `\[(ALPHA|BETA|STABLE|INTERNAL)\] .+`,
+				Mode:    metriclint.DeclarativeModeRequired,
+				Message: "help text should start with the stability level, e.g. \"[ALPHA] \"",
+			},
+			{
+				Name:    RuleKubernetesDeprecatedLabel,
+				Target:  metriclint.DeclarativeTargetLabel,
+				Pattern: "pod_name|container_name",
+				Mode:    metriclint.DeclarativeModeForbidden,
+				Message: "labels pod_name and container_name are deprecated, use pod and container",
+			},
+		},
+		// The guidelines make these rules blocking, whatever their default severity.
+		Severities: map[string]metriclint.Severity{
+			metriclint.RuleNameCamelCase:      metriclint.SeverityError,
+			metriclint.RuleLabelCamelCase:     metriclint.SeverityError,
+			metriclint.RuleNonBaseUnit:        metriclint.SeverityError,
+			metriclint.RuleCounterTotalSuffix: metriclint.SeverityError,
+		},
+	},
+}
+
+func init() {
+	metriclint.RegisterPolicyBundle(KubernetesBundle)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presets
+
+import (
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/linttest"
+)
+
+func TestKubernetes(t *testing.T) {
+	linter := metriclint.NewLinter(metriclint.UsePolicyBundles(Kubernetes))
+
+	var tests = []struct {
+		name     string
+		spec     metriclint.MetricSpec
+		expected []string
+	}{
+		{
+			name: "follows the guidelines",
+			spec: metriclint.MetricSpec{
+				Namespace:      "apiserver",
+				Name:           "request_duration_seconds",
+				Help:           "[STABLE] Response latency distribution in seconds for each verb.",
+				Type:           metriclint.MetricTypeHistogram,
+				Buckets:        []float64{0.05, 0.1, 0.5, 1, 5},
+				VariableLabels: []string{"verb"},
+			},
+		},
+		{
+			name: "deprecated metric",
+			spec: metriclint.MetricSpec{
+				Name: "kubelet_pods",
+				Help: "[ALPHA] (Deprecated since 1.19.0) Number of pods.",
+				Type: metriclint.MetricTypeGauge,
+			},
+		},
+		{
+			name:     "missing stability level",
+			spec:     metriclint.MetricSpec{Name: "kubelet_pods", Help: "Number of pods.", Type: metriclint.MetricTypeGauge},
+			expected: []string{RuleKubernetesStabilityLevel},
+		},
+		{
+			name:     "lowercase stability level",
+			spec:     metriclint.MetricSpec{Name: "kubelet_pods", Help: "[alpha] Number of pods.", Type: metriclint.MetricTypeGauge},
+			expected: []string{RuleKubernetesStabilityLevel},
+		},
+		{
+			name: "deprecated labels",
+			spec: metriclint.MetricSpec{
+				Name:           "container_restarts_total",
+				Help:           "[ALPHA] Number of container restarts.",
+				Type:           metriclint.MetricTypeCounter,
+				VariableLabels: []string{"namespace", "pod_name"},
+			},
+			// pod_name is also one of the default high-cardinality labels.
+			expected: []string{RuleKubernetesDeprecatedLabel, metriclint.RuleHighCardinalityLabel},
+		},
+		{
+			name: "conventions of the common rules",
+			spec: metriclint.MetricSpec{
+				Name: "volumeOperations_milliseconds",
+				Help: "[ALPHA] Number of volume operations.",
+				Type: metriclint.MetricTypeCounter,
+			},
+			expected: []string{metriclint.RuleNameCamelCase, metriclint.RuleNonBaseUnit, metriclint.RuleCounterTotalSuffix},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			linttest.AssertIssues(t, linter.Lint(tc.spec), tc.expected...)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "fmt"
+
+// Names of the built-in profiles.
+const (
+	// ProfileStrict enables every opt-in rule and makes every warning blocking.
+	ProfileStrict = "strict"
+
+	// ProfileDefault is the default rule set with the default severities.
+	ProfileDefault = "default"
+
+	// ProfileLenient only blocks on issues breaking the exposition or the queries, such as invalid
+	// names or duplicate labels. The style issues are warnings.
+	ProfileLenient = "lenient"
+
+	// ProfileOpenMetrics enforces the naming of OpenMetrics: the unit is the suffix of the name,
+	// counters end with "_total", info metrics with "_info", and metrics are typed.
+	ProfileOpenMetrics = "openmetrics"
+)
+
+// lenientErrors are the rules still blocking in ProfileLenient.
+var lenientErrors = map[string]bool{
+	RuleNameEmpty:                       true,
+	RuleInvalidName:                     true,
+	RuleNameReservedChars:               true,
+	RuleLabelDuplicate:                  true,
+	RuleLabelShadowsConstLabel:          true,
+	RuleLabelReservedPrefix:             true,
+	RuleNonHistogramLeLabel:             true,
+	RuleNonSummaryQuantileLabel:         true,
+	RuleHistogramLeLabel:                true,
+	RuleSummaryQuantileLabel:            true,
+	RuleVectorLabels:                    true,
+	RuleHistogramBucketsOrder:           true,
+	RuleSummaryQuantiles:                true,
+	RuleNativeHistogramBucketFactor:     true,
+	RuleNativeHistogramZeroThreshold:    true,
+	RuleNativeHistogramMaxZeroThreshold: true,
+	RuleNativeHistogramMinResetDuration: true,
+}
+
+// profiles returns the options of the built-in profiles.
+var profiles = map[string]func() []Option{
+	ProfileStrict: func() []Option {
+		var optIn []string
+		for id := range optInRules {
+			optIn = append(optIn, id)
+		}
+		severities := map[string]Severity{}
+		for _, rule := range rules {
+			if rule.Severity == SeverityWarning {
+				severities[rule.ID] = SeverityError
+			}
+		}
+
+		return []Option{EnableRules(optIn...), WithSeverities(severities)}
+	},
+	ProfileDefault: func() []Option { return nil },
+	ProfileLenient: func() []Option {
+		severities := map[string]Severity{}
+		for _, rule := range rules {
+			if rule.Severity == SeverityError && !lenientErrors[rule.ID] {
+				severities[rule.ID] = SeverityWarning
+			}
+		}
+
+		return []Option{WithSeverities(severities)}
+	},
+	ProfileOpenMetrics: func() []Option {
+		return []Option{
+			EnableRules(RuleUnitSuffix),
+			WithSeverities(map[string]Severity{
+				RuleUnitSuffix:    SeverityError,
+				RuleInfoMetric:    SeverityError,
+				RuleInfoName:      SeverityError,
+				RuleUntypedMetric: SeverityError,
+				RuleTimestampName: SeverityError,
+			}),
+		}
+	},
+}
+
+// Profiles returns the names of the built-in profiles, from the strictest to the most lenient, then
+// the profiles of the other conventions.
+func Profiles() []string {
+	return []string{ProfileStrict, ProfileDefault, ProfileLenient, ProfileOpenMetrics}
+}
+
+// WithProfile applies a built-in profile, a rule set with severities to start from. The options after it,
+// such as EnableRules or WithSeverities, refine the profile. It panics on unknown profiles, like
+// NewLinter on unknown rules.
+func WithProfile(name string) Option {
+	return func(l *Linter) {
+		profile, ok := profiles[name]
+		if !ok {
+			panic(fmt.Sprintf("metriclint: unknown profile %q", name))
+		}
+		for _, opt := range profile() {
+			opt(l)
+		}
+	}
+}
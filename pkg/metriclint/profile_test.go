@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithProfile(t *testing.T) {
+	// Lacks help, "_total" and a unit, and has a label with a high cardinality.
+	counter := MetricSpec{Name: "http_requests", Type: MetricTypeCounter, VariableLabels: []string{"path"}}
+	// Only lacks the "_info" suffix.
+	info := MetricSpec{Name: "build", Help: "Build information.", Type: MetricTypeGauge, ConstLabels: map[string]string{"version": "v1"}}
+	// Invalid whatever the profile.
+	duplicate := MetricSpec{Name: "queue_length", Help: "Length of the queue.", Type: MetricTypeGauge, VariableLabels: []string{"queue", "queue"}}
+
+	var tests = []struct {
+		profile   string
+		counter   []string
+		info      []string
+		duplicate []string
+	}{
+		{
+			profile:   ProfileDefault,
+			counter:   []string{"help-missing:error", "high-cardinality-label:warning", "counter-total-suffix:error"},
+			info:      []string{"info-name:warning"},
+			duplicate: []string{"label-duplicate:error"},
+		},
+		{
+			profile:   ProfileStrict,
+			counter:   []string{"help-missing:error", "high-cardinality-label:error", "counter-total-suffix:error", "unit-suffix:error"},
+			info:      []string{"info-name:error", "unit-suffix:error", "namespace-policy:error"},
+			duplicate: []string{"label-duplicate:error", "unit-suffix:error"},
+		},
+		{
+			profile:   ProfileLenient,
+			counter:   []string{"help-missing:warning", "high-cardinality-label:warning", "counter-total-suffix:warning"},
+			info:      []string{"info-name:warning"},
+			duplicate: []string{"label-duplicate:error"},
+		},
+		{
+			profile:   ProfileOpenMetrics,
+			counter:   []string{"help-missing:error", "high-cardinality-label:warning", "counter-total-suffix:error", "unit-suffix:error"},
+			info:      []string{"info-name:error", "unit-suffix:error"},
+			duplicate: []string{"label-duplicate:error", "unit-suffix:error"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.profile, func(t *testing.T) {
+			linter := NewLinter(WithProfile(tc.profile))
+
+			for _, c := range []struct {
+				spec     MetricSpec
+				expected []string
+			}{{counter, tc.counter}, {info, tc.info}, {duplicate, tc.duplicate}} {
+				var issues []string
+				for _, issue := range linter.Lint(c.spec).Findings {
+					issues = append(issues, issue.ID+":"+string(issue.Severity))
+				}
+				if !reflect.DeepEqual(issues, c.expected) {
+					t.Errorf("%s: expected: %v, but got: %v", c.spec.Name, c.expected, issues)
+				}
+			}
+		})
+	}
+}
+
+func TestWithProfileRefined(t *testing.T) {
+	linter := NewLinter(WithProfile(ProfileStrict), WithSeverities(map[string]Severity{RuleHighCardinalityLabel: SeverityWarning}))
+	result := linter.Lint(MetricSpec{Name: "http_requests_total", Help: "Total number of requests by path.", Type: MetricTypeCounter, VariableLabels: []string{"path"}})
+	if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityWarning {
+		t.Errorf("expected: %s warning, but got: %v", RuleHighCardinalityLabel, result.Findings)
+	}
+}
+
+func TestWithProfileUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for unknown profile")
+		}
+	}()
+	NewLinter(WithProfile("pedantic"))
+}
+
+func TestConfigProfile(t *testing.T) {
+	linter, err := NewLinterFromConfig(&Config{Profile: ProfileLenient})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := linter.Lint(MetricSpec{Name: "http_requests", Help: "Number of requests.", Type: MetricTypeCounter})
+	if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityWarning {
+		t.Errorf("expected: %s warning, but got: %v", RuleCounterTotalSuffix, result.Findings)
+	}
+
+	err = (&Config{Profile: "pedantic"}).Validate()
+	expected := "invalid config:\n" + `profile: unknown profile "pedantic"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, err)
+	}
+}
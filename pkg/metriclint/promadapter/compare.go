@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// DifferenceKind is the kind of a Difference between two gatherers.
+type DifferenceKind string
+
+const (
+	// DifferenceRemoved is a metric only gathered by the first gatherer.
+	DifferenceRemoved DifferenceKind = "removed"
+
+	// DifferenceAdded is a metric only gathered by the second gatherer.
+	DifferenceAdded DifferenceKind = "added"
+
+	// DifferenceType is a metric whose type changed.
+	DifferenceType DifferenceKind = "type"
+
+	// DifferenceLabels is a metric whose label names changed.
+	DifferenceLabels DifferenceKind = "labels"
+)
+
+// Difference is a metric gathered differently by two gatherers.
+type Difference struct {
+	Metric string
+	Kind   DifferenceKind
+
+	// Type or label names of the metric in each gatherer, empty for added and removed metrics.
+	Before string
+	After  string
+
+	// Lint result of the metric in the second gatherer, or in the first one for removed metrics.
+	Result *metriclint.LintResult
+}
+
+func (d Difference) String() string {
+	if d.Before == "" && d.After == "" {
+		return fmt.Sprintf("%s: %s", d.Metric, d.Kind)
+	}
+
+	return fmt.Sprintf("%s: %s changed from %s to %s", d.Metric, d.Kind, d.Before, d.After)
+}
+
+// CompareGatherers gathers the metric families of a and b and reports the metrics removed, added, or
+// whose type or label names changed from a to b, e.g. to verify that a refactoring didn't drop or alter
+// any series. The differences are ordered by metric name, and each of them has the lint result of the metric.
+//
+// Label names are compared on the gathered series, so a vector has to have children for its labels to be compared.
+func (l *Linter) CompareGatherers(a, b prometheus.Gatherer) ([]Difference, error) {
+	before, err := gatherSpecs(a)
+	if err != nil {
+		return nil, err
+	}
+	after, err := gatherSpecs(b)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []Difference
+	for _, name := range names {
+		old, hadOld := before[name]
+		spec, hasNew := after[name]
+		switch {
+		case !hasNew:
+			diffs = append(diffs, Difference{Metric: name, Kind: DifferenceRemoved, Result: l.Lint(old)})
+		case !hadOld:
+			diffs = append(diffs, Difference{Metric: name, Kind: DifferenceAdded, Result: l.Lint(spec)})
+		default:
+			var result *metriclint.LintResult
+			if old.Type != spec.Type {
+				result = l.Lint(spec)
+				diffs = append(diffs, Difference{Metric: name, Kind: DifferenceType, Before: string(old.Type), After: string(spec.Type), Result: result})
+			}
+			if oldLabels, newLabels := fmt.Sprint(old.VariableLabels), fmt.Sprint(spec.VariableLabels); oldLabels != newLabels {
+				if result == nil {
+					result = l.Lint(spec)
+				}
+				diffs = append(diffs, Difference{Metric: name, Kind: DifferenceLabels, Before: oldLabels, After: newLabels, Result: result})
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// CompareGatherers compares the gatherers like Linter.CompareGatherers, with the default rules.
+func CompareGatherers(a, b prometheus.Gatherer) ([]Difference, error) {
+	return defaultLinter.CompareGatherers(a, b)
+}
+
+// gatherSpecs gathers the metric families of the gatherer, keyed by name.
+func gatherSpecs(gatherer prometheus.Gatherer) (map[string]metriclint.MetricSpec, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make(map[string]metriclint.MetricSpec, len(families))
+	for _, mf := range families {
+		specs[mf.GetName()] = FamilySpec(mf)
+	}
+
+	return specs, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCompareGatherers(t *testing.T) {
+	before := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message"}, []string{"code"})
+	requests.WithLabelValues("200")
+	before.MustRegister(
+		requests,
+		prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_errors_total", Help: "this is help message"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_queue_length", Help: "this is help message"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_unchanged", Help: "this is help message"}),
+	)
+
+	after := prometheus.NewRegistry()
+	renamed := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message"}, []string{"code", "method"})
+	renamed.WithLabelValues("200", "get")
+	after.MustRegister(
+		renamed,
+		prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_failures", Help: "this is help message"}),
+		prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_queue_length", Help: "this is help message"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_unchanged", Help: "this is help message"}),
+	)
+
+	diffs, err := CompareGatherers(before, after)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		diff   string
+		issues int
+	}{
+		{diff: "lint_errors_total: removed"},
+		{diff: "lint_failures: added", issues: 1},
+		{diff: "lint_queue_length: type changed from gauge to counter", issues: 1},
+		{diff: "lint_requests_total: labels changed from [code] to [code method]"},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, diffs)
+	}
+	for i, e := range expected {
+		if diffs[i].String() != e.diff {
+			t.Errorf("expected: %s, but got: %s", e.diff, diffs[i])
+		}
+		if len(diffs[i].Result.Issues) != e.issues {
+			t.Errorf("expected %d issues for %s, but got: %v", e.issues, diffs[i].Metric, diffs[i].Result.Issues)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// DescSpec converts a Desc into a MetricSpec of the given type.
+//
+// A Desc keeps its fields private, they are decoded from its String form, e.g.
+// Desc{fqName: "a_total", help: "help", constLabels: {zone="a"}, variableLabels: [code]}.
+func DescSpec(desc *prometheus.Desc, metricType metriclint.MetricType) (metriclint.MetricSpec, error) {
+	s := desc.String()
+	d := &descDecoder{s: s}
+
+	d.expect("Desc{fqName: ")
+	name := d.quoted()
+	d.expect(", help: ")
+	help := d.quoted()
+	d.expect(", constLabels: {")
+	var constLabels map[string]string
+	for d.err == nil && !d.consume("}") {
+		if constLabels != nil {
+			d.expect(",")
+		} else {
+			constLabels = map[string]string{}
+		}
+		i := strings.Index(d.s, "=")
+		if i < 0 {
+			d.fail("const label value")
+			break
+		}
+		labelName := d.s[:i]
+		d.s = d.s[i+1:]
+		constLabels[labelName] = d.quoted()
+	}
+	d.expect(", variableLabels: [")
+	i := strings.Index(d.s, "]")
+	if i < 0 {
+		d.fail("variable labels")
+	}
+	if d.err != nil {
+		return metriclint.MetricSpec{}, fmt.Errorf("failed to decode %s: %v", s, d.err)
+	}
+	var variableLabels []string
+	if fields := strings.Fields(d.s[:i]); len(fields) > 0 {
+		variableLabels = fields
+	}
+
+	return metriclint.MetricSpec{
+		Name:           name,
+		Help:           help,
+		Type:           metricType,
+		ConstLabels:    constLabels,
+		VariableLabels: variableLabels,
+	}, nil
+}
+
+// descDecoder consumes the String form of a Desc, remembering the first error.
+type descDecoder struct {
+	s   string
+	err error
+}
+
+func (d *descDecoder) fail(what string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("expected %s at %q", what, d.s)
+	}
+}
+
+func (d *descDecoder) consume(prefix string) bool {
+	if d.err != nil || !strings.HasPrefix(d.s, prefix) {
+		return false
+	}
+	d.s = d.s[len(prefix):]
+
+	return true
+}
+
+func (d *descDecoder) expect(prefix string) {
+	if !d.consume(prefix) {
+		d.fail(strconv.Quote(prefix))
+	}
+}
+
+// quoted consumes a Go quoted string and returns its value.
+func (d *descDecoder) quoted() string {
+	if d.err != nil {
+		return ""
+	}
+	if strings.HasPrefix(d.s, "\"") {
+		for i := 1; i < len(d.s); i++ {
+			switch d.s[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(d.s[:i+1])
+				if err != nil {
+					d.fail("quoted string")
+					return ""
+				}
+				d.s = d.s[i+1:]
+				return value
+			}
+		}
+	}
+	d.fail("quoted string")
+
+	return ""
+}
+
+// collectorTypes collects the metrics of a collector once and returns the type of each of its Descs,
+// keyed by their String form. Descs without collected metric, such as vectors without children, are missing.
+func collectorTypes(c prometheus.Collector) map[string]metriclint.MetricType {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	types := map[string]metriclint.MetricType{}
+	for m := range ch {
+		if metricType, err := collectedType(m); err == nil {
+			types[m.Desc().String()] = metricType
+		}
+	}
+
+	return types
+}
+
+// collectedType returns the type of a collected metric.
+func collectedType(m prometheus.Metric) (metriclint.MetricType, error) {
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return "", err
+	}
+
+	return writtenType(&pb), nil
+}
+
+// writtenType returns the type of a written metric.
+func writtenType(pb *dto.Metric) metriclint.MetricType {
+	switch {
+	case pb.Counter != nil:
+		return metriclint.MetricTypeCounter
+	case pb.Gauge != nil:
+		return metriclint.MetricTypeGauge
+	case pb.Histogram != nil:
+		return metriclint.MetricTypeHistogram
+	case pb.Summary != nil:
+		return metriclint.MetricTypeSummary
+	default:
+		return metriclint.MetricTypeUntyped
+	}
+}
+
+// LintDesc lints a Desc built with prometheus.NewDesc, e.g. in a custom Collector. A Desc has no type,
+// it's linted as untyped with the common rules, see LintConstMetric to lint with the rules of its type.
+func (l *Linter) LintDesc(desc *prometheus.Desc) (*metriclint.LintResult, error) {
+	spec, err := DescSpec(desc, metriclint.MetricTypeUntyped)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Lint(spec), nil
+}
+
+// LintConstMetric lints the Desc of a metric built with prometheus.MustNewConstMetric or the like,
+// with the rules of the type of the metric.
+func (l *Linter) LintConstMetric(m prometheus.Metric) (*metriclint.LintResult, error) {
+	metricType, err := collectedType(m)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := DescSpec(m.Desc(), metricType)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Lint(spec), nil
+}
+
+// LintDesc lints a Desc like Linter.LintDesc, with the default rules.
+func LintDesc(desc *prometheus.Desc) (*metriclint.LintResult, error) {
+	return defaultLinter.LintDesc(desc)
+}
+
+// LintConstMetric lints a metric like Linter.LintConstMetric, with the default rules.
+func LintConstMetric(m prometheus.Metric) (*metriclint.LintResult, error) {
+	return defaultLinter.LintConstMetric(m)
+}
+
+// collectorSpecs returns the specs of the Descs of a collector, in the order they are described.
+// The type of the Descs of client_golang metrics and vectors comes from their Go type, see metricSpec.
+// The Descs of other collectors are typed from a collected metric if collect is set, untyped otherwise.
+func collectorSpecs(c prometheus.Collector, collect bool) ([]metriclint.MetricSpec, error) {
+	spec, ok, err := metricSpec(c)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return []metriclint.MetricSpec{spec}, nil
+	}
+
+	descs := describe(c)
+	var types map[string]metriclint.MetricType
+	if collect {
+		types = collectorTypes(c)
+	}
+	specs := make([]metriclint.MetricSpec, 0, len(descs))
+	for _, desc := range descs {
+		metricType, ok := types[desc.String()]
+		if !ok {
+			metricType = metriclint.MetricTypeUntyped
+		}
+		spec, err := DescSpec(desc, metricType)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// describe returns the Descs of a collector, in the order they are described.
+func describe(c prometheus.Collector) []*prometheus.Desc {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(ch)
+		close(ch)
+	}()
+	var descs []*prometheus.Desc
+	for desc := range ch {
+		descs = append(descs, desc)
+	}
+
+	return descs
+}
+
+// metricSpec returns the spec of a vector or a metric of client_golang, whose single Desc is typed after
+// its Go type, without collecting. A vector doesn't expose its buckets or objectives, those of a metric
+// are written like when it's gathered, see FamilySpec. ok is false for other collectors.
+func metricSpec(c prometheus.Collector) (spec metriclint.MetricSpec, ok bool, err error) {
+	var desc *prometheus.Desc
+	var metricType metriclint.MetricType
+	switch v := c.(type) {
+	case *prometheus.CounterVec:
+		desc, metricType = vectorDesc(v), metriclint.MetricTypeCounter
+	case *prometheus.GaugeVec:
+		desc, metricType = vectorDesc(v), metriclint.MetricTypeGauge
+	case *prometheus.HistogramVec:
+		desc, metricType = vectorDesc(v), metriclint.MetricTypeHistogram
+	case *prometheus.SummaryVec:
+		desc, metricType = vectorDesc(v), metriclint.MetricTypeSummary
+	case prometheus.Metric:
+		var pb dto.Metric
+		if err := v.Write(&pb); err != nil {
+			return metriclint.MetricSpec{}, false, err
+		}
+		if spec, err = DescSpec(v.Desc(), writtenType(&pb)); err != nil {
+			return metriclint.MetricSpec{}, false, err
+		}
+		written := FamilySpec(&dto.MetricFamily{Metric: []*dto.Metric{&pb}})
+		spec.Buckets, spec.Objectives = written.Buckets, written.Objectives
+		return spec, true, nil
+	default:
+		return metriclint.MetricSpec{}, false, nil
+	}
+
+	spec, err = DescSpec(desc, metricType)
+	return spec, err == nil, err
+}
+
+// vectorDesc returns the single Desc a vector describes.
+func vectorDesc(c prometheus.Collector) *prometheus.Desc {
+	ch := make(chan *prometheus.Desc, 1)
+	c.Describe(ch)
+
+	return <-ch
+}
+
+// LintCollector lints the Descs of a collector without registering it, returning one result per Desc in
+// the order they are described. The metrics and vectors of client_golang are typed after their Go type,
+// the Descs of hand-written collectors after the metrics they currently collect, Descs without collected
+// metric are linted as untyped, with the common rules only.
+func (l *Linter) LintCollector(c prometheus.Collector) ([]*metriclint.LintResult, error) {
+	specs, err := collectorSpecs(c, true)
+	if err != nil {
+		return nil, err
+	}
+
+	lint := l.collectorLint(c)
+	results := make([]*metriclint.LintResult, 0, len(specs))
+	for _, spec := range specs {
+		results = append(results, lint(spec))
+	}
+
+	return results, nil
+}
+
+// LintCollector lints the Descs of a collector like Linter.LintCollector, with the default rules.
+func LintCollector(c prometheus.Collector) ([]*metriclint.LintResult, error) {
+	return defaultLinter.LintCollector(c)
+}
+
+// lintCollector lints the Descs of a collector, without collecting it, returning the results with issues
+// and the tombstones of the metrics scheduled for removal, which aren't linted. The Descs of hand-written
+// collectors are linted as untyped.
+func (l *Linter) lintCollector(c prometheus.Collector) ([]*metriclint.LintResult, []metriclint.Tombstone, error) {
+	specs, err := collectorSpecs(c, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lint := l.collectorLint(c)
+	descs := describe(c)
+	var results []*metriclint.LintResult
+	var tombstoned []metriclint.Tombstone
+	for i, spec := range specs {
+		if t, ok := l.Tombstone(spec.FQName()); ok {
+			metriclint.Debugf("skipping rules of tombstoned metric %s", spec.FQName())
+			tombstoned = append(tombstoned, t)
+			continue
+		}
+		result := lint(spec)
+		if len(descs) == len(specs) && shadowsConstLabel(descs[i], spec) {
+			l.AddRuleMessages(result, metriclint.RuleLabelShadowsConstLabel, fmt.Sprintf(LintErrMsgLabelShadowsDroppedConstLabel, spec.VariableLabels))
+		}
+		if len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	return results, tombstoned, nil
+}
+
+// collectorLint returns Lint, or LintVector for the vectors of client_golang, so that their label names
+// are checked like those passed to their constructor.
+func (l *Linter) collectorLint(c prometheus.Collector) func(metriclint.MetricSpec) *metriclint.LintResult {
+	switch c.(type) {
+	case *prometheus.CounterVec, *prometheus.GaugeVec, *prometheus.HistogramVec, *prometheus.SummaryVec:
+		return l.LintVector
+	default:
+		return l.Lint
+	}
+}
+
+// shadowsConstLabel reports whether client_golang refused the Desc for duplicate label names while its
+// variable labels are distinct: one of them shadows a const label. Such a Desc has dropped its const
+// labels, so the label can't be named, unlike from the options, see metriclint.RuleLabelShadowsConstLabel.
+func shadowsConstLabel(desc *prometheus.Desc, spec metriclint.MetricSpec) bool {
+	seen := map[string]bool{}
+	for _, name := range spec.VariableLabels {
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+	}
+	err := prometheus.NewRegistry().Register(descCollector{desc})
+
+	return err != nil && strings.Contains(err.Error(), "duplicate label names")
+}
+
+// descCollector describes a single Desc and collects nothing.
+type descCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c descCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c descCollector) Collect(chan<- prometheus.Metric) {}
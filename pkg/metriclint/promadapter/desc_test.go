@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/linttest"
+)
+
+func TestDescSpec(t *testing.T) {
+	var tests = []struct {
+		name     string
+		desc     *prometheus.Desc
+		expected metriclint.MetricSpec
+	}{
+		{
+			name:     "plain",
+			desc:     prometheus.NewDesc("lint_requests_total", "this is help message", nil, nil),
+			expected: metriclint.MetricSpec{Name: "lint_requests_total", Help: "this is help message", Type: metriclint.MetricTypeCounter},
+		},
+		{
+			name: "labels",
+			desc: prometheus.NewDesc("lint_requests_total", "", []string{"code", "method"}, prometheus.Labels{"zone": "a", "quote": "\"x\", y=z"}),
+			expected: metriclint.MetricSpec{Name: "lint_requests_total", Type: metriclint.MetricTypeCounter,
+				ConstLabels:    map[string]string{"zone": "a", "quote": "\"x\", y=z"},
+				VariableLabels: []string{"code", "method"}},
+		},
+		{
+			name:     "escaped help",
+			desc:     prometheus.NewDesc("lint_requests_total", "help with \"quotes\" and }", nil, nil),
+			expected: metriclint.MetricSpec{Name: "lint_requests_total", Help: "help with \"quotes\" and }", Type: metriclint.MetricTypeCounter},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := DescSpec(tc.desc, metriclint.MetricTypeCounter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(spec, tc.expected) {
+				t.Errorf("expected: %+v, but got: %+v", tc.expected, spec)
+			}
+		})
+	}
+}
+
+func TestLintDesc(t *testing.T) {
+	result, err := LintDesc(prometheus.NewDesc("lint_queue-length", "", []string{"code"}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	linttest.AssertIssues(t, result, metriclint.RuleHelpMissing, metriclint.RuleInvalidName)
+}
+
+func TestLintConstMetric(t *testing.T) {
+	desc := prometheus.NewDesc("lint_requests", "this is help message", []string{"code"}, nil)
+
+	result, err := LintConstMetric(prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 1, "200"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	linttest.AssertIssues(t, result, metriclint.RuleCounterTotalSuffix)
+
+	result, err = LintConstMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, "200"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	linttest.AssertNoIssues(t, result)
+}
+
+// exporterCollector is a hand-written collector exposing a gauge and a counter without children.
+type exporterCollector struct {
+	up     *prometheus.Desc
+	errors *prometheus.Desc
+}
+
+func (c exporterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.errors
+}
+
+func (c exporterCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+}
+
+func TestLintCollector(t *testing.T) {
+	c := exporterCollector{
+		up:     prometheus.NewDesc("lint_exporter_up_total", "Whether the exporter is up.", nil, nil),
+		errors: prometheus.NewDesc("lint_exporter_errors", "", nil, nil),
+	}
+
+	results, err := LintCollector(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, but got: %v", results)
+	}
+	// The gauge is typed from the collected metric, the Desc without metric is untyped.
+	linttest.AssertIssues(t, results[0], metriclint.RuleNonCounterTotalSuffix)
+	linttest.AssertIssues(t, results[1], metriclint.RuleHelpMissing)
+}
+
+func TestLintCollectorEmptyVector(t *testing.T) {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests", Help: "this is help message"}, []string{"code"})
+
+	results, err := LintCollector(vec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, but got: %v", results)
+	}
+	linttest.AssertIssues(t, results[0], metriclint.RuleCounterTotalSuffix)
+}
@@ -0,0 +1,61 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+var errorsDesc = prometheus.NewDesc(
+	"metriclint_errors",
+	"Number of lint errors in the latest metriclint report.",
+	[]string{"digest"}, nil,
+)
+
+// ReportCollector exposes the number of errors of the latest report as the metriclint_errors gauge,
+// labeled by the report digest, so that an alert on metriclint_errors > 0 can annotate the top findings.
+type ReportCollector struct {
+	latest func() *metriclint.Report
+}
+
+var _ prometheus.Collector = &ReportCollector{}
+
+// NewReportCollector returns a ReportCollector reading the latest report from the given function.
+// No sample is exposed while it returns nil.
+func NewReportCollector(latest func() *metriclint.Report) *ReportCollector {
+	return &ReportCollector{latest: latest}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ReportCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- errorsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ReportCollector) Collect(ch chan<- prometheus.Metric) {
+	report := c.latest()
+	if report == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(errorsDesc, prometheus.GaugeValue, float64(report.ErrorCount()), report.Digest())
+}
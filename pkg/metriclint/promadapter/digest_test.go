@@ -0,0 +1,64 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestReportCollector(t *testing.T) {
+	var report *metriclint.Report
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewReportCollector(func() *metriclint.Report { return report }))
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) != 0 {
+		t.Fatalf("expected no family without report, but got: %v", families)
+	}
+
+	// Warnings are not counted as errors.
+	report = &metriclint.Report{Results: []*metriclint.LintResult{
+		{MetricName: "lint_a_total", Findings: []metriclint.Issue{
+			{ID: metriclint.RuleHelpMissing, Message: metriclint.LintErrMsgNoHelp, Severity: metriclint.SeverityError},
+			{ID: metriclint.RuleNameAbbreviatedUnit, Message: metriclint.LintErrMsgNameShouldNotHaveAbbr, Severity: metriclint.SeverityWarning},
+		}},
+	}}
+	families, err = reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+		t.Fatalf("expected a single metriclint_errors series, but got: %v", families)
+	}
+	m := families[0].GetMetric()[0]
+	if m.GetGauge().GetValue() != 1 {
+		t.Errorf("expected: %v, but got: %v", 1, m.GetGauge().GetValue())
+	}
+	if m.GetLabel()[0].GetValue() != report.Digest() {
+		t.Errorf("expected: %s, but got: %s", report.Digest(), m.GetLabel()[0].GetValue())
+	}
+}
@@ -0,0 +1,164 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const LintErrMsgCounterDecreased = `counter series %s decreased from %g to %g between scrapes, it may be a gauge`
+
+// LintExposition parses a payload in the Prometheus text format, such as a scraped /metrics page,
+// and lints each of its families, sorted by name. The payload goes through
+// metriclint.NewExpositionReader, so compressed dumps and CRLF line endings are accepted.
+//
+// Families without TYPE line are linted as untyped, with the common rules only. Families whose series
+// have different label names are reported as duplicate metrics, see metriclint.LintDuplicates, and the
+// structure of histogram and summary series is checked with metriclint.Linter.LintScrapedSeries.
+func (l *Linter) LintExposition(r io.Reader) ([]*metriclint.LintResult, error) {
+	families, err := parseExposition(r)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]*dto.MetricFamily, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, families[name])
+	}
+
+	results := l.lintFamilies(sorted)
+	for i, mf := range sorted {
+		l.lintSeries(results[i], mf, false)
+	}
+
+	return results, nil
+}
+
+// LintExposition lints a text format payload with the default rules.
+func LintExposition(r io.Reader) ([]*metriclint.LintResult, error) {
+	return defaultLinter.LintExposition(r)
+}
+
+// CompareExpositions compares two text format payloads scraped from the same target, a before b, and reports
+// with metriclint.RuleCounterDecreased the counters having a series whose value decreased without dropping
+// to zero, as a restart would, which catches metrics mislabeled as counters. The results are sorted by
+// metric name, metrics without issue are left out.
+func (l *Linter) CompareExpositions(a, b io.Reader) ([]*metriclint.LintResult, error) {
+	before, err := parseExposition(a)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseExposition(b)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []*metriclint.LintResult
+	for _, name := range names {
+		old, ok := before[name]
+		mf := after[name]
+		if !ok || old.GetType() != dto.MetricType_COUNTER || mf.GetType() != dto.MetricType_COUNTER {
+			continue
+		}
+
+		values := map[string]float64{}
+		for _, m := range old.GetMetric() {
+			values[seriesID(name, m)] = m.GetCounter().GetValue()
+		}
+		var issues []string
+		for _, m := range mf.GetMetric() {
+			id := seriesID(name, m)
+			value, ok := values[id]
+			if now := m.GetCounter().GetValue(); ok && now < value && now != 0 {
+				issues = append(issues, fmt.Sprintf(LintErrMsgCounterDecreased, id, value, now))
+			}
+		}
+
+		result := &metriclint.LintResult{MetricName: name}
+		l.AddRuleMessages(result, metriclint.RuleCounterDecreased, issues...)
+		if len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// CompareExpositions compares two text format payloads with the default rules.
+func CompareExpositions(a, b io.Reader) ([]*metriclint.LintResult, error) {
+	return defaultLinter.CompareExpositions(a, b)
+}
+
+// ExpositionSpecs converts the families of a text format payload into MetricSpecs, sorted by name,
+// see FamilySpec.
+func ExpositionSpecs(r io.Reader) ([]metriclint.MetricSpec, error) {
+	families, err := parseExposition(r)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]metriclint.MetricSpec, 0, len(families))
+	for _, mf := range families {
+		specs = append(specs, FamilySpec(mf))
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+
+	return specs, nil
+}
+
+// parseExposition parses the families of a text format payload, keyed by name.
+func parseExposition(r io.Reader) (map[string]*dto.MetricFamily, error) {
+	r, err := metriclint.NewExpositionReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(r)
+}
+
+// seriesID returns the identity of a series of the family, see metriclint.SeriesID.
+func seriesID(family string, m *dto.Metric) string {
+	labels := make(map[string]string, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+
+	return metriclint.SeriesID(family, labels)
+}
@@ -0,0 +1,250 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestLintExposition(t *testing.T) {
+	payload := strings.Join([]string{
+		"# HELP http_requests Number of requests.",
+		"# TYPE http_requests counter",
+		`http_requests{code="200"} 3`,
+		"# TYPE queue_length gauge",
+		"queue_length 1",
+		"# HELP request_duration_seconds Distribution of request durations.",
+		"# TYPE request_duration_seconds histogram",
+		`request_duration_seconds_bucket{le="+Inf"} 1`,
+		"request_duration_seconds_sum 0.1",
+		"request_duration_seconds_count 1",
+		"",
+	}, "\r\n")
+
+	results, err := LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"http_requests:" + metriclint.LintErrMsgCounterShouldHaveTotalSuffix,
+		"queue_length:" + metriclint.LintErrMsgNoHelp,
+		"request_duration_seconds:",
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i := range expected {
+		if results[i].String() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], results[i])
+		}
+	}
+}
+
+func TestLintExpositionDuplicateLabelSets(t *testing.T) {
+	payload := strings.Join([]string{
+		"# HELP queue_length Number of items in the queue.",
+		"# TYPE queue_length gauge",
+		`queue_length{queue="a"} 1`,
+		`queue_length{queue="b",shard="1"} 2`,
+		`queue_length{queue="c",shard="2"} 3`,
+		"",
+	}, "\n")
+
+	results, err := LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "queue_length:" + fmt.Sprintf(metriclint.LintErrMsgDuplicateMetric, "label names")
+	if len(results) != 1 || results[0].String() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, results)
+	}
+}
+
+func TestLintExpositionSeriesStructure(t *testing.T) {
+	payload := strings.Join([]string{
+		"# HELP request_duration_seconds Distribution of request durations.",
+		"# TYPE request_duration_seconds histogram",
+		`request_duration_seconds_bucket{code="200",le="0.1"} 2`,
+		`request_duration_seconds_bucket{code="200",le="1"} 1`,
+		`request_duration_seconds_bucket{code="200",le="+Inf"} 4`,
+		`request_duration_seconds_sum{code="200"} 1.5`,
+		`request_duration_seconds_count{code="200"} 5`,
+		`request_duration_seconds_bucket{code="500",le="1"} 1`,
+		`request_duration_seconds_count{code="500"} 1`,
+		"# HELP response_size_bytes Distribution of response sizes.",
+		"# TYPE response_size_bytes summary",
+		`response_size_bytes{quantile="0.5"} 512`,
+		"response_size_bytes_sum 2048",
+		"",
+	}, "\n")
+
+	results, err := LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"request_duration_seconds:" + strings.Join([]string{
+			fmt.Sprintf(metriclint.LintErrMsgBucketsDecreasing, `request_duration_seconds{code="200"}`, 1.0),
+			fmt.Sprintf(metriclint.LintErrMsgBucketCountMismatch, `request_duration_seconds{code="200"}`, 5.0, 4.0),
+			fmt.Sprintf(metriclint.LintErrMsgSeriesIncomplete, "histogram", `request_duration_seconds{code="500"}`, "+Inf bucket"),
+			fmt.Sprintf(metriclint.LintErrMsgSeriesIncomplete, "histogram", `request_duration_seconds{code="500"}`, "_sum sample"),
+		}, ","),
+		"response_size_bytes:" + fmt.Sprintf(metriclint.LintErrMsgSeriesIncomplete, "summary", "response_size_bytes", "_count sample"),
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i := range expected {
+		if results[i].String() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], results[i])
+		}
+	}
+
+	linter := NewLinter(metriclint.NewLinter(metriclint.DisableRules(metriclint.RuleSeriesIncomplete, metriclint.RuleHistogramBucketsCumulative)))
+	results, err = linter.LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if len(result.Findings) > 0 {
+			t.Errorf("expected no issue with the rules disabled, but got: %v", result.Findings)
+		}
+	}
+}
+
+func TestCompareExpositions(t *testing.T) {
+	before := strings.Join([]string{
+		"# TYPE http_requests_total counter",
+		`http_requests_total{code="200"} 10`,
+		`http_requests_total{code="500"} 4`,
+		"# TYPE queue_items_total counter",
+		"queue_items_total 5",
+		"# TYPE restarts_total counter",
+		"restarts_total 7",
+		"# TYPE queue_length gauge",
+		"queue_length 5",
+		"",
+	}, "\n")
+	after := strings.Join([]string{
+		"# TYPE http_requests_total counter",
+		`http_requests_total{code="200"} 12`,
+		`http_requests_total{code="500"} 4`,
+		`http_requests_total{code="503"} 1`,
+		"# TYPE queue_items_total counter",
+		"queue_items_total 3",
+		"# TYPE restarts_total counter",
+		"restarts_total 0",
+		"# TYPE queue_length gauge",
+		"queue_length 3",
+		"",
+	}, "\n")
+
+	results, err := CompareExpositions(strings.NewReader(before), strings.NewReader(after))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "queue_items_total:" + fmt.Sprintf(LintErrMsgCounterDecreased, "queue_items_total", 5.0, 3.0)
+	if len(results) != 1 || results[0].String() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, results)
+	}
+
+	if _, err := CompareExpositions(strings.NewReader(before), strings.NewReader("queue_length{ 1\n")); err == nil {
+		t.Errorf("expected a parse error")
+	}
+}
+
+func TestLintExpositionParseError(t *testing.T) {
+	if _, err := LintExposition(strings.NewReader("# TYPE queue_length gauge\nqueue_length{ 1\n")); err == nil {
+		t.Errorf("expected a parse error")
+	}
+}
+
+func TestExpositionSpecs(t *testing.T) {
+	exposition := strings.Join([]string{
+		"# TYPE queue_length gauge",
+		`queue_length{queue="a"} 5`,
+		"# TYPE http_requests_total counter",
+		"http_requests_total 1",
+		"",
+	}, "\n")
+
+	specs, err := ExpositionSpecs(strings.NewReader(exposition))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(specs) != 2 || specs[0].Name != "http_requests_total" || specs[1].Type != metriclint.MetricTypeGauge || len(specs[1].VariableLabels) != 1 {
+		t.Errorf("expected: http_requests_total and queue_length specs, but got: %v", specs)
+	}
+}
+
+// kubeStateMetrics returns a text exposition shaped like a kube-state-metrics scrape, with the
+// number of gauge families and a series per pod in each.
+func kubeStateMetrics(families, pods int) string {
+	var b strings.Builder
+	for f := 0; f < families; f++ {
+		fmt.Fprintf(&b, "# HELP kube_pod_info_%d Information about pods.\n# TYPE kube_pod_info_%d gauge\n", f, f)
+		for p := 0; p < pods; p++ {
+			fmt.Fprintf(&b, "kube_pod_info_%d{namespace=\"default\",pod=\"pod-%d\"} 1\n", f, p)
+		}
+	}
+
+	return b.String()
+}
+
+func TestLintExpositionParallelism(t *testing.T) {
+	payload := kubeStateMetrics(50, 20)
+
+	serial, err := LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parallel, err := NewLinter(metriclint.NewLinter(metriclint.WithParallelism(4))).LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(parallel) != fmt.Sprint(serial) {
+		t.Errorf("expected: %v, but got: %v", serial, parallel)
+	}
+}
+
+func BenchmarkLintExposition(b *testing.B) {
+	payload := kubeStateMetrics(1000, 50)
+	for _, bench := range []struct {
+		name        string
+		parallelism int
+	}{{"serial", 1}, {"gomaxprocs", 0}} {
+		l := NewLinter(metriclint.NewLinter(metriclint.WithParallelism(bench.parallelism)))
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := l.LintExposition(strings.NewReader(payload)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// LintPath is the conventional path of LintingHandler.LintHandler, next to the /metrics endpoint.
+const LintPath = "/metrics/lint"
+
+// HandlerOpts configures a LintingHandler.
+type HandlerOpts struct {
+	// Debug lints the gathered metric families on each scrape. Without it the handler only serves
+	// the metrics, like promhttp.HandlerFor.
+	Debug bool
+
+	// Linter linting the families, the default rules if nil.
+	Linter *Linter
+
+	// Options of the promhttp handler serving the metrics.
+	Handler promhttp.HandlerOpts
+}
+
+// LintingHandler serves the metrics of a Gatherer with promhttp and, in debug mode, lints the families
+// gathered by each scrape, giving operators runtime visibility into violations. The results of the last
+// scrape are served in JSON by LintHandler, and exposed by IssuesCollector as the metriclint_issues gauge.
+type LintingHandler struct {
+	linter  *Linter
+	debug   bool
+	metrics http.Handler
+
+	mu      sync.Mutex
+	results metriclint.Results
+}
+
+// NewLintingHandler returns a LintingHandler serving the metrics of the gatherer.
+func NewLintingHandler(gatherer prometheus.Gatherer, opts HandlerOpts) *LintingHandler {
+	if opts.Linter == nil {
+		opts.Linter = defaultLinter
+	}
+
+	h := &LintingHandler{linter: opts.Linter, debug: opts.Debug}
+	if opts.Debug {
+		gatherer = lintingGatherer{inner: gatherer, handler: h}
+	}
+	h.metrics = promhttp.HandlerFor(gatherer, opts.Handler)
+
+	return h
+}
+
+// ServeHTTP serves the metrics.
+func (h *LintingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.metrics.ServeHTTP(w, r)
+}
+
+// Results returns the results with issues of the last scrape, nil before the first scrape or without
+// debug mode.
+func (h *LintingHandler) Results() metriclint.Results {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.results
+}
+
+// LintHandler serves the results of the last scrape in JSON, see metriclint.Results. It responds
+// with 404 Not Found without debug mode.
+func (h *LintingHandler) LintHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.debug {
+			http.Error(w, "linting is disabled, see HandlerOpts.Debug", http.StatusNotFound)
+			return
+		}
+
+		data, err := json.Marshal(h.Results())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// IssuesCollector returns a collector exposing the number of issues of the last scrape as the
+// metriclint_issues gauge, by metric, rule and severity. Registered into the gathered registry, it
+// reports the issues of the previous scrape.
+func (h *LintingHandler) IssuesCollector() prometheus.Collector {
+	return issuesCollector{handler: h}
+}
+
+// lint lints the gathered families and keeps the results with issues.
+func (h *LintingHandler) lint(families []*dto.MetricFamily) {
+	var results metriclint.Results
+	for _, mf := range families {
+		if result := h.linter.Lint(FamilySpec(mf)); len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+	metriclint.Debugf("linted %d metric families of the scrape, %d with issues", len(families), len(results))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.results = results
+}
+
+// lintingGatherer lints the families it gathers, so that a scrape is linted as served.
+type lintingGatherer struct {
+	inner   prometheus.Gatherer
+	handler *LintingHandler
+}
+
+func (g lintingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.inner.Gather()
+	g.handler.lint(families)
+
+	return families, err
+}
+
+var issuesDesc = prometheus.NewDesc(
+	"metriclint_issues",
+	"Number of lint issues of the metrics served by the last scrape.",
+	[]string{"metric", "rule", "severity"}, nil,
+)
+
+// issuesCollector exposes the results of a LintingHandler.
+type issuesCollector struct {
+	handler *LintingHandler
+}
+
+func (c issuesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- issuesDesc
+}
+
+func (c issuesCollector) Collect(ch chan<- prometheus.Metric) {
+	type key struct{ metric, rule, severity string }
+	counts := map[key]int{}
+	var keys []key
+	for _, result := range c.handler.Results() {
+		for _, issue := range result.Findings {
+			k := key{result.MetricName, issue.ID, string(issue.Severity)}
+			if counts[k] == 0 {
+				keys = append(keys, k)
+			}
+			counts[k]++
+		}
+	}
+
+	for _, k := range keys {
+		ch <- prometheus.MustNewConstMetric(issuesDesc, prometheus.GaugeValue, float64(counts[k]), k.metric, k.rule, k.severity)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestLintingHandler(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_requests", Help: "Number of requests."}))
+	registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_up", Help: "Whether the target is up."}))
+
+	handler := NewLintingHandler(registry, HandlerOpts{Debug: true})
+	registry.MustRegister(handler.IssuesCollector())
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	mux.Handle(LintPath, handler.LintHandler())
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	if body := get(t, server.URL+"/metrics"); !strings.Contains(body, "lint_requests 0") || strings.Contains(body, "metriclint_issues") {
+		t.Errorf("expected the metrics without issues, but got: %s", body)
+	}
+
+	var results struct {
+		IssueCount int `json:"issueCount"`
+		Results    []struct {
+			Metric string `json:"metric"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(get(t, server.URL+LintPath)), &results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.IssueCount != 1 || len(results.Results) != 1 || results.Results[0].Metric != "lint_requests" {
+		t.Errorf("expected: %s, but got: %+v", "lint_requests", results)
+	}
+
+	// The next scrape exposes the issues of the previous one.
+	expected := `metriclint_issues{metric="lint_requests",rule="counter-total-suffix",severity="error"} 1`
+	if body := get(t, server.URL+"/metrics"); !strings.Contains(body, expected) {
+		t.Errorf("expected: %s, but got: %s", expected, body)
+	}
+	if results := handler.Results(); len(results) != 1 {
+		t.Errorf("expected: %d results, but got: %v", 1, results)
+	}
+}
+
+func TestLintingHandlerWithoutDebug(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_requests", Help: "Number of requests."}))
+	handler := NewLintingHandler(registry, HandlerOpts{})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), "lint_requests 0") {
+		t.Errorf("expected the metrics, but got: %s", recorder.Body.String())
+	}
+	if results := handler.Results(); results != nil {
+		t.Errorf("expected no result, but got: %v", results)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.LintHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, LintPath, nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected: %d, but got: %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(body)
+}
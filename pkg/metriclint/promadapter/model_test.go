@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// TestInvalidNameMatchesModel makes sure the invalid-name rule agrees with prometheus/common/model,
+// which metriclint doesn't import.
+func TestInvalidNameMatchesModel(t *testing.T) {
+	names := []string{"lint_test", "lint:test", "_lint", ":lint", "5lint", "lint-test", "lint.test", "lint test", "lïnt"}
+
+	for _, name := range names {
+		result := metriclint.NewLinter().Lint(metriclint.MetricSpec{Name: name, Help: "this is help message", Type: metriclint.MetricTypeUntyped})
+		if invalid := hasRule(result, metriclint.RuleInvalidName); invalid == model.IsValidMetricName(model.LabelValue(name)) {
+			t.Errorf("metric name %q: expected invalid: %v, but got: %v", name, !invalid, invalid)
+		}
+
+		result = metriclint.NewLinter().Lint(metriclint.MetricSpec{Name: "lint_test", Help: "this is help message", Type: metriclint.MetricTypeUntyped,
+			ConstLabels: map[string]string{name: "a"}})
+		if invalid := hasRule(result, metriclint.RuleInvalidName); invalid == model.LabelName(name).IsValid() {
+			t.Errorf("label name %q: expected invalid: %v, but got: %v", name, !invalid, invalid)
+		}
+	}
+}
+
+func hasRule(result *metriclint.LintResult, id string) bool {
+	for _, issue := range result.Findings {
+		if issue.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// MustLintCounter lints the options of a counter, or of a counter vector with the label names, and returns
+// them, so that it can wrap the options passed to the constructor:
+//
+//	requests := prometheus.NewCounterVec(l.MustLintCounter(opts, "code"), []string{"code"})
+//
+// It panics with a *metriclint.LintError, see metriclint.Linter.Check, if the metric has error issues.
+func (l *Linter) MustLintCounter(counterOpts prometheus.CounterOpts, labelNames ...string) prometheus.CounterOpts {
+	l.mustCheck(CounterSpec(counterOpts, labelNames))
+	return counterOpts
+}
+
+// MustLintGauge lints the options of a gauge like MustLintCounter.
+func (l *Linter) MustLintGauge(gaugeOpts prometheus.GaugeOpts, labelNames ...string) prometheus.GaugeOpts {
+	l.mustCheck(GaugeSpec(gaugeOpts, labelNames))
+	return gaugeOpts
+}
+
+// MustLintHistogram lints the options of a histogram like MustLintCounter.
+func (l *Linter) MustLintHistogram(histogramOpts prometheus.HistogramOpts, labelNames ...string) prometheus.HistogramOpts {
+	l.mustCheck(HistogramSpec(histogramOpts, labelNames))
+	return histogramOpts
+}
+
+// MustLintSummary lints the options of a summary like MustLintCounter.
+func (l *Linter) MustLintSummary(summaryOpts prometheus.SummaryOpts, labelNames ...string) prometheus.SummaryOpts {
+	l.mustCheck(SummarySpec(summaryOpts, labelNames))
+	return summaryOpts
+}
+
+func (l *Linter) mustCheck(spec metriclint.MetricSpec) {
+	if err := l.Check(spec); err != nil {
+		panic(err)
+	}
+}
+
+// MustLintCounter lints the options of a counter like Linter.MustLintCounter, with the default rules.
+func MustLintCounter(counterOpts prometheus.CounterOpts, labelNames ...string) prometheus.CounterOpts {
+	return defaultLinter.MustLintCounter(counterOpts, labelNames...)
+}
+
+// MustLintGauge lints the options of a gauge like Linter.MustLintGauge, with the default rules.
+func MustLintGauge(gaugeOpts prometheus.GaugeOpts, labelNames ...string) prometheus.GaugeOpts {
+	return defaultLinter.MustLintGauge(gaugeOpts, labelNames...)
+}
+
+// MustLintHistogram lints the options of a histogram like Linter.MustLintHistogram, with the default rules.
+func MustLintHistogram(histogramOpts prometheus.HistogramOpts, labelNames ...string) prometheus.HistogramOpts {
+	return defaultLinter.MustLintHistogram(histogramOpts, labelNames...)
+}
+
+// MustLintSummary lints the options of a summary like Linter.MustLintSummary, with the default rules.
+func MustLintSummary(summaryOpts prometheus.SummaryOpts, labelNames ...string) prometheus.SummaryOpts {
+	return defaultLinter.MustLintSummary(summaryOpts, labelNames...)
+}
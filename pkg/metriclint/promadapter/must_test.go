@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestMustLint(t *testing.T) {
+	// recovered returns the error f panics with, nil if it doesn't panic.
+	recovered := func(f func()) (err error) {
+		defer func() {
+			err, _ = recover().(error)
+		}()
+		f()
+		return nil
+	}
+
+	var tests = []struct {
+		name   string
+		lint   func()
+		failed string
+	}{
+		{
+			name: "counter",
+			lint: func() {
+				MustLintCounter(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message"}, "code")
+			},
+		},
+		{
+			name: "bad counter",
+			lint: func() {
+				MustLintCounter(prometheus.CounterOpts{Name: "lint_requests", Help: "this is help message"})
+			},
+			failed: "lint_requests",
+		},
+		{
+			name: "bad gauge label",
+			lint: func() {
+				MustLintGauge(prometheus.GaugeOpts{Name: "lint_queue_length", Help: "this is help message"}, "queueName")
+			},
+			failed: "lint_queue_length",
+		},
+		{
+			name: "histogram",
+			lint: func() {
+				MustLintHistogram(prometheus.HistogramOpts{Name: "lint_duration_seconds", Help: "this is help message"})
+			},
+		},
+		{
+			name: "bad summary",
+			lint: func() {
+				MustLintSummary(prometheus.SummaryOpts{Name: "lint_duration_ms", Help: "this is help message"})
+			},
+			failed: "lint_duration_ms",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			err := recovered(tc.lint)
+			if tc.failed == "" {
+				if err != nil {
+					t.Errorf("unexpected panic: %v", err)
+				}
+				return
+			}
+
+			var lintErr *metriclint.LintError
+			if !errors.As(err, &lintErr) || len(lintErr.Results) != 1 || lintErr.Results[0].MetricName != tc.failed {
+				t.Errorf("expected: a LintError of %s, but got: %v", tc.failed, err)
+			}
+		})
+	}
+}
+
+func TestRejectedErrorUnwrap(t *testing.T) {
+	r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{Action: ActionReject})
+	err := r.Register(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_bad", Help: "this is help message"}))
+
+	if !errors.Is(err, metriclint.ErrIssues) {
+		t.Errorf("expected: %v, but got: %v", metriclint.ErrIssues, err)
+	}
+	var lintErr *metriclint.LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Results) != 1 || lintErr.Results[0].MetricName != "lint_bad" {
+		t.Errorf("expected: a LintError of lint_bad, but got: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package promadapter lints client_golang metric options with the metriclint rules.
+//
+// It converts prometheus.*Opts into metriclint.MetricSpec, so metriclint itself doesn't import client_golang.
+package promadapter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// CounterSpec converts counter options and the label names of a vector into a MetricSpec.
+func CounterSpec(counterOpts prometheus.CounterOpts, labelNames []string) metriclint.MetricSpec {
+	return optsSpec(prometheus.Opts(counterOpts), metriclint.MetricTypeCounter, labelNames)
+}
+
+// GaugeSpec converts gauge options and the label names of a vector into a MetricSpec.
+func GaugeSpec(gaugeOpts prometheus.GaugeOpts, labelNames []string) metriclint.MetricSpec {
+	return optsSpec(prometheus.Opts(gaugeOpts), metriclint.MetricTypeGauge, labelNames)
+}
+
+// HistogramSpec converts histogram options and the label names of a vector into a MetricSpec.
+func HistogramSpec(histogramOpts prometheus.HistogramOpts, labelNames []string) metriclint.MetricSpec {
+	return metriclint.MetricSpec{
+		Namespace:      histogramOpts.Namespace,
+		Subsystem:      histogramOpts.Subsystem,
+		Name:           histogramOpts.Name,
+		Help:           histogramOpts.Help,
+		Type:           metriclint.MetricTypeHistogram,
+		ConstLabels:    histogramOpts.ConstLabels,
+		VariableLabels: labelNames,
+		Buckets:        histogramOpts.Buckets,
+	}
+}
+
+// SummarySpec converts summary options and the label names of a vector into a MetricSpec.
+func SummarySpec(summaryOpts prometheus.SummaryOpts, labelNames []string) metriclint.MetricSpec {
+	return metriclint.MetricSpec{
+		Namespace:      summaryOpts.Namespace,
+		Subsystem:      summaryOpts.Subsystem,
+		Name:           summaryOpts.Name,
+		Help:           summaryOpts.Help,
+		Type:           metriclint.MetricTypeSummary,
+		ConstLabels:    summaryOpts.ConstLabels,
+		VariableLabels: labelNames,
+		Objectives:     summaryOpts.Objectives,
+		MaxAge:         summaryOpts.MaxAge,
+	}
+}
+
+// UntypedSpec converts untyped options and the label names of a vector into a MetricSpec.
+func UntypedSpec(untypedOpts prometheus.UntypedOpts, labelNames []string) metriclint.MetricSpec {
+	return optsSpec(prometheus.Opts(untypedOpts), metriclint.MetricTypeUntyped, labelNames)
+}
+
+// prometheus.CounterOpts, prometheus.GaugeOpts and prometheus.UntypedOpts share the type.
+func optsSpec(opts prometheus.Opts, metricType metriclint.MetricType, labelNames []string) metriclint.MetricSpec {
+	return metriclint.MetricSpec{
+		Namespace:      opts.Namespace,
+		Subsystem:      opts.Subsystem,
+		Name:           opts.Name,
+		Help:           opts.Help,
+		Type:           metricType,
+		ConstLabels:    opts.ConstLabels,
+		VariableLabels: labelNames,
+	}
+}
+
+// Linter lints client_golang metric options with a configured metriclint.Linter.
+type Linter struct {
+	*metriclint.Linter
+}
+
+// NewLinter returns a Linter linting with l.
+func NewLinter(l *metriclint.Linter) *Linter {
+	return &Linter{Linter: l}
+}
+
+// defaultLinter backs the package level functions, it runs the default rules.
+var defaultLinter = NewLinter(metriclint.NewLinter())
+
+func (l *Linter) LintCounter(counterOpts prometheus.CounterOpts) *metriclint.LintResult {
+	return l.Lint(CounterSpec(counterOpts, nil))
+}
+
+func (l *Linter) LintCounterVector(counterOpts prometheus.CounterOpts, labelNames []string) *metriclint.LintResult {
+	return l.LintVector(CounterSpec(counterOpts, labelNames))
+}
+
+func (l *Linter) LintGauge(gaugeOpts prometheus.GaugeOpts) *metriclint.LintResult {
+	return l.Lint(GaugeSpec(gaugeOpts, nil))
+}
+
+func (l *Linter) LintGaugeVector(gaugeOpts prometheus.GaugeOpts, labelNames []string) *metriclint.LintResult {
+	return l.LintVector(GaugeSpec(gaugeOpts, labelNames))
+}
+
+func (l *Linter) LintHistogram(histogramOpts prometheus.HistogramOpts) *metriclint.LintResult {
+	return l.Lint(HistogramSpec(histogramOpts, nil))
+}
+
+func (l *Linter) LintHistogramVector(histogramOpts prometheus.HistogramOpts, labelNames []string) *metriclint.LintResult {
+	return l.LintVector(HistogramSpec(histogramOpts, labelNames))
+}
+
+func (l *Linter) LintSummary(summaryOpts prometheus.SummaryOpts) *metriclint.LintResult {
+	return l.Lint(SummarySpec(summaryOpts, nil))
+}
+
+func (l *Linter) LintSummaryVector(summaryOpts prometheus.SummaryOpts, labelNames []string) *metriclint.LintResult {
+	return l.LintVector(SummarySpec(summaryOpts, labelNames))
+}
+
+// LintUntyped lints untyped options with the common rules and an advisory metriclint.RuleUntypedMetric issue.
+func (l *Linter) LintUntyped(untypedOpts prometheus.UntypedOpts) *metriclint.LintResult {
+	return l.Linter.LintUntyped(UntypedSpec(untypedOpts, nil))
+}
+
+func (l *Linter) LintUntypedVector(untypedOpts prometheus.UntypedOpts, labelNames []string) *metriclint.LintResult {
+	return l.Linter.LintUntypedVector(UntypedSpec(untypedOpts, labelNames))
+}
+
+// LintCounterFunc lints the options of a prometheus.CounterFunc, reporting names which suggest a value that can decrease.
+func (l *Linter) LintCounterFunc(counterOpts prometheus.CounterOpts) *metriclint.LintResult {
+	return l.Linter.LintCounterFunc(CounterSpec(counterOpts, nil))
+}
+
+// LintGaugeFunc lints the options of a prometheus.GaugeFunc like a gauge.
+func (l *Linter) LintGaugeFunc(gaugeOpts prometheus.GaugeOpts) *metriclint.LintResult {
+	return l.LintGauge(gaugeOpts)
+}
+
+func LintCounter(counterOpts prometheus.CounterOpts) *metriclint.LintResult {
+	return defaultLinter.LintCounter(counterOpts)
+}
+
+func LintCounterVector(counterOpts prometheus.CounterOpts, labelNames []string) *metriclint.LintResult {
+	return defaultLinter.LintCounterVector(counterOpts, labelNames)
+}
+
+func LintGauge(gaugeOpts prometheus.GaugeOpts) *metriclint.LintResult {
+	return defaultLinter.LintGauge(gaugeOpts)
+}
+
+func LintGaugeVector(gaugeOpts prometheus.GaugeOpts, labelNames []string) *metriclint.LintResult {
+	return defaultLinter.LintGaugeVector(gaugeOpts, labelNames)
+}
+
+func LintHistogram(histogramOpts prometheus.HistogramOpts) *metriclint.LintResult {
+	return defaultLinter.LintHistogram(histogramOpts)
+}
+
+func LintHistogramVector(histogramOpts prometheus.HistogramOpts, labelNames []string) *metriclint.LintResult {
+	return defaultLinter.LintHistogramVector(histogramOpts, labelNames)
+}
+
+func LintSummary(summaryOpts prometheus.SummaryOpts) *metriclint.LintResult {
+	return defaultLinter.LintSummary(summaryOpts)
+}
+
+func LintSummaryVector(summaryOpts prometheus.SummaryOpts, labelNames []string) *metriclint.LintResult {
+	return defaultLinter.LintSummaryVector(summaryOpts, labelNames)
+}
+
+func LintUntyped(untypedOpts prometheus.UntypedOpts) *metriclint.LintResult {
+	return defaultLinter.LintUntyped(untypedOpts)
+}
+
+func LintUntypedVector(untypedOpts prometheus.UntypedOpts, labelNames []string) *metriclint.LintResult {
+	return defaultLinter.LintUntypedVector(untypedOpts, labelNames)
+}
+
+func LintCounterFunc(counterOpts prometheus.CounterOpts) *metriclint.LintResult {
+	return defaultLinter.LintCounterFunc(counterOpts)
+}
+
+func LintGaugeFunc(gaugeOpts prometheus.GaugeOpts) *metriclint.LintResult {
+	return defaultLinter.LintGaugeFunc(gaugeOpts)
+}
@@ -14,13 +14,17 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package metriclint
+package promadapter
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/linttest"
 )
 
 func TestLintCounter(t *testing.T) {
@@ -48,7 +52,7 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -59,7 +63,7 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_hours_total:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours_total:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "counter should contains total suffix",
@@ -70,7 +74,7 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_suffix:%s", LintErrMsgCounterShouldHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_suffix:%s", metriclint.LintErrMsgCounterShouldHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -81,7 +85,7 @@ func TestLintCounter(t *testing.T) {
 					"le": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -92,7 +96,7 @@ func TestLintCounter(t *testing.T) {
 					"quantile": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -103,7 +107,7 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_counter_total:%s", fmt.Sprintf(LintErrMsgNoMetricType, "counter")),
+			expectedResult: fmt.Sprintf("lint_counter_total:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "counter")),
 		},
 		{
 			name: "should not have special chars",
@@ -114,7 +118,7 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_:_total:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_total:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -125,7 +129,7 @@ func TestLintCounter(t *testing.T) {
 					"lName": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_tesT_total:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_total:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -136,7 +140,29 @@ func TestLintCounter(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_ms_total:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_total:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name: "namespace should not equal subsystem",
+			opts: prometheus.CounterOpts{
+				Namespace: "lint",
+				Subsystem: "lint",
+				Name: "test_total",
+				Help: "this is help message",
+			},
+			expectedResult: fmt.Sprintf("lint_lint_test_total:%s", metriclint.LintErrMsgNamespaceEqualsSubsystem),
+		},
+		{
+			name: "name pieces should not produce double underscores",
+			opts: prometheus.CounterOpts{
+				Namespace: "lint_",
+				Subsystem: "test",
+				Name: "_total",
+				Help: "this is help message",
+			},
+			expectedResult: fmt.Sprintf("lint__test__total:%s,%s",
+				fmt.Sprintf(metriclint.LintErrMsgFQNamePartDoubleUnderscore, "namespace", "lint_"),
+				fmt.Sprintf(metriclint.LintErrMsgFQNamePartDoubleUnderscore, "name", "_total")),
 		},
 	}
 
@@ -179,7 +205,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -191,7 +217,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_hours_total:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours_total:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "counter should contains total suffix",
@@ -203,7 +229,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_suffix:%s", LintErrMsgCounterShouldHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_suffix:%s", metriclint.LintErrMsgCounterShouldHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -215,7 +241,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"le", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -227,7 +253,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"quantile", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -239,7 +265,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_counter_total:%s", fmt.Sprintf(LintErrMsgNoMetricType, "counter")),
+			expectedResult: fmt.Sprintf("lint_counter_total:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "counter")),
 		},
 		{
 			name: "should not have special chars",
@@ -251,7 +277,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_:_total:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_total:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -263,7 +289,7 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lName1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_tesT_total:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_total:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -275,7 +301,57 @@ func TestLintCounterVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_ms_total:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_total:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name: "variable label should not shadow const label",
+			opts: prometheus.CounterOpts{
+				Name: "lint_test_total",
+				Help: "this is help message",
+				ConstLabels: prometheus.Labels{
+					"lname": "lvalue",
+				},
+			},
+			labelNames: []string{"lname", "lname2"},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", fmt.Sprintf(metriclint.LintErrMsgLabelShadowsConstLabel, "lname", "lvalue")),
+		},
+		{
+			name: "vector label names should not be empty",
+			opts: prometheus.CounterOpts{
+				Name: "lint_test_total",
+				Help: "this is help message",
+			},
+			labelNames: []string{"lname1", ""},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", fmt.Sprintf(metriclint.LintErrMsgVectorEmptyLabel, 1)),
+		},
+		{
+			name: "vector should have label names",
+			opts: prometheus.CounterOpts{
+				Name: "lint_test_total",
+				Help: "this is help message",
+			},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgVectorNoLabels),
+		},
+		{
+			name: "variable label names should not be repeated",
+			opts: prometheus.CounterOpts{
+				Name: "lint_test_total",
+				Help: "this is help message",
+			},
+			labelNames: []string{"lname1", "lname2", "lname1"},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", fmt.Sprintf(metriclint.LintErrMsgLabelDuplicate, "lname1", 2, 0)),
+		},
+		{
+			name: "label names should not start with __",
+			opts: prometheus.CounterOpts{
+				Name: "lint_test_total",
+				Help: "this is help message",
+				ConstLabels: prometheus.Labels{
+					"__lname": "lvalue",
+				},
+			},
+			labelNames: []string{"__lname1"},
+			expectedResult: fmt.Sprintf("lint_test_total:%s,%s", fmt.Sprintf(metriclint.LintErrMsgLabelReservedPrefix, "__lname"), fmt.Sprintf(metriclint.LintErrMsgLabelReservedPrefix, "__lname1")),
 		},
 	}
 
@@ -315,7 +391,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -326,7 +402,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_hours_numbers:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours_numbers:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -337,7 +413,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have bucket suffix",
@@ -348,7 +424,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_bucket:%s", LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
+			expectedResult: fmt.Sprintf("lint_test_bucket:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
 		},
 		{
 			name: "non histogram summary should not have count suffix",
@@ -359,7 +435,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_count:%s", LintErrMsgNonHistogramSummaryShouldNotHaveCountSuffix),
+			expectedResult: fmt.Sprintf("lint_test_count:%s", metriclint.LintErrMsgNonHistogramSummaryShouldNotHaveCountSuffix),
 		},
 		{
 			name: "non histogram summary should not have sum suffix",
@@ -370,7 +446,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_sum:%s", LintErrMsgMonHistogramSummaryShouldNotHaveSumSuffix),
+			expectedResult: fmt.Sprintf("lint_test_sum:%s", metriclint.LintErrMsgMonHistogramSummaryShouldNotHaveSumSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -381,7 +457,7 @@ func TestLintGauge(t *testing.T) {
 					"le": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -392,7 +468,7 @@ func TestLintGauge(t *testing.T) {
 					"quantile": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -403,7 +479,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_gauge_numbers:%s", fmt.Sprintf(LintErrMsgNoMetricType, "gauge")),
+			expectedResult: fmt.Sprintf("lint_gauge_numbers:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "gauge")),
 		},
 		{
 			name: "should not have special chars",
@@ -414,7 +490,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_:_numbers:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_numbers:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -425,7 +501,7 @@ func TestLintGauge(t *testing.T) {
 					"lName": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_tesT_numbers:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_numbers:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -436,7 +512,7 @@ func TestLintGauge(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_ms_numbers:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_numbers:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
 		},
 	}
 
@@ -479,7 +555,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -491,7 +567,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_hours_numbers:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours_numbers:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -503,7 +579,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have bucket suffix",
@@ -515,7 +591,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_bucket:%s", LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
+			expectedResult: fmt.Sprintf("lint_test_bucket:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
 		},
 		{
 			name: "non histogram summary should not have count suffix",
@@ -527,7 +603,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_count:%s", LintErrMsgNonHistogramSummaryShouldNotHaveCountSuffix),
+			expectedResult: fmt.Sprintf("lint_test_count:%s", metriclint.LintErrMsgNonHistogramSummaryShouldNotHaveCountSuffix),
 		},
 		{
 			name: "non histogram summary should not have sum suffix",
@@ -539,7 +615,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_sum:%s", LintErrMsgMonHistogramSummaryShouldNotHaveSumSuffix),
+			expectedResult: fmt.Sprintf("lint_test_sum:%s", metriclint.LintErrMsgMonHistogramSummaryShouldNotHaveSumSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -551,7 +627,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"le", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -563,7 +639,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"quantile", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_numbers:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_numbers:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -575,7 +651,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_gauge_numbers:%s", fmt.Sprintf(LintErrMsgNoMetricType, "gauge")),
+			expectedResult: fmt.Sprintf("lint_gauge_numbers:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "gauge")),
 		},
 		{
 			name: "should not have special chars",
@@ -587,7 +663,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_:_numbers:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_numbers:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -599,7 +675,7 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lName1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_tesT_numbers:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_numbers:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -611,7 +687,19 @@ func TestLintGaugeVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_ms_numbers:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_numbers:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name: "label should not repeat metric name",
+			opts: prometheus.GaugeOpts{
+				Name: "lint_test_seconds",
+				Help: "this is help message",
+				ConstLabels: prometheus.Labels{
+					"lname": "lvalue",
+				},
+			},
+			labelNames: []string{"test_method", "lname2"},
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgLabelRepeatsMetricName, "test_method", "method")),
 		},
 	}
 
@@ -651,7 +739,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -662,7 +750,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -673,7 +761,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -684,7 +772,7 @@ func TestLintHistogram(t *testing.T) {
 					"quantile": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not contains type name",
@@ -695,7 +783,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_histogram_seconds:%s", fmt.Sprintf(LintErrMsgNoMetricType, "histogram")),
+			expectedResult: fmt.Sprintf("lint_histogram_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "histogram")),
 		},
 		{
 			name: "should not have special chars",
@@ -706,7 +794,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_:_seconds:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_seconds:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -717,7 +805,7 @@ func TestLintHistogram(t *testing.T) {
 					"lName": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -728,7 +816,7 @@ func TestLintHistogram(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_ms_seconds:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_seconds:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
 		},
 	}
 
@@ -771,7 +859,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -783,7 +871,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -795,7 +883,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non summary should not have quantile label",
@@ -807,7 +895,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"quantile", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNonSummaryShouldNotHaveQuantileLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -819,7 +907,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_histogram_seconds:%s", fmt.Sprintf(LintErrMsgNoMetricType, "histogram")),
+			expectedResult: fmt.Sprintf("lint_histogram_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "histogram")),
 		},
 		{
 			name: "should not have special chars",
@@ -831,7 +919,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_:_seconds:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_seconds:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -843,7 +931,7 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lName1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -855,7 +943,28 @@ func TestLintHistogramVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_ms_seconds:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_seconds:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name: "variable label should not shadow const label",
+			opts: prometheus.HistogramOpts{
+				Name: "lint_test_seconds",
+				Help: "this is help message",
+				ConstLabels: prometheus.Labels{
+					"lname": "lvalue",
+				},
+			},
+			labelNames: []string{"lname", "lname2"},
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgLabelShadowsConstLabel, "lname", "lvalue")),
+		},
+		{
+			name: "should not have le label",
+			opts: prometheus.HistogramOpts{
+				Name: "lint_test_seconds",
+				Help: "this is help message",
+			},
+			labelNames: []string{"le"},
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgHistogramLeLabel),
 		},
 	}
 
@@ -895,7 +1004,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -906,7 +1015,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -917,7 +1026,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have bucket suffix",
@@ -928,7 +1037,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_bucket:%s", LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
+			expectedResult: fmt.Sprintf("lint_test_bucket:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -939,7 +1048,7 @@ func TestLintSummary(t *testing.T) {
 					"le": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -950,7 +1059,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_summary_seconds:%s", fmt.Sprintf(LintErrMsgNoMetricType, "summary")),
+			expectedResult: fmt.Sprintf("lint_summary_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "summary")),
 		},
 		{
 			name: "should not have special chars",
@@ -961,7 +1070,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_:_seconds:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_seconds:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -972,7 +1081,7 @@ func TestLintSummary(t *testing.T) {
 					"lName": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -983,7 +1092,7 @@ func TestLintSummary(t *testing.T) {
 					"lname": "lvalue",
 				},
 			},
-			expectedResult: fmt.Sprintf("lint_ms_seconds:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_seconds:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
 		},
 	}
 
@@ -1026,7 +1135,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNoHelp),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNoHelp),
 		},
 		{
 			name: "should use base unit",
@@ -1038,7 +1147,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "hours")),
+			expectedResult: fmt.Sprintf("lint_test_hours:%s", fmt.Sprintf(metriclint.LintErrMsgNonBaseUnit, "seconds", "hours")),
 		},
 		{
 			name: "non counter should not have total",
@@ -1050,7 +1159,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+			expectedResult: fmt.Sprintf("lint_test_total:%s", metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix),
 		},
 		{
 			name: "non histogram should not have bucket suffix",
@@ -1062,7 +1171,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_bucket:%s", LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
+			expectedResult: fmt.Sprintf("lint_test_bucket:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveBucketSuffix),
 		},
 		{
 			name: "non histogram should not have le label",
@@ -1074,7 +1183,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"le", "lname2"},
-			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel),
 		},
 		{
 			name: "should not have metric type",
@@ -1086,7 +1195,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_summary_seconds:%s", fmt.Sprintf(LintErrMsgNoMetricType, "summary")),
+			expectedResult: fmt.Sprintf("lint_summary_seconds:%s", fmt.Sprintf(metriclint.LintErrMsgNoMetricType, "summary")),
 		},
 		{
 			name: "should not have special chars",
@@ -1098,7 +1207,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_:_seconds:%s", LintErrMsgNoReservedChars),
+			expectedResult: fmt.Sprintf("lint_:_seconds:%s", metriclint.LintErrMsgNoReservedChars),
 		},
 		{
 			name: "name label should in snake case",
@@ -1110,7 +1219,7 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lName1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", LintErrMsgNameShouldBeSnakeCase, LintErrMsgLabelShouldBeSnakeCase),
+			expectedResult: fmt.Sprintf("lint_tesT_seconds:%s,%s", metriclint.LintErrMsgNameShouldBeSnakeCase, metriclint.LintErrMsgLabelShouldBeSnakeCase),
 		},
 		{
 			name: "should not contain abbreviated unit",
@@ -1122,7 +1231,16 @@ func TestLintSummaryVector(t *testing.T) {
 				},
 			},
 			labelNames: []string{"lname1", "lname2"},
-			expectedResult: fmt.Sprintf("lint_ms_seconds:%s", LintErrMsgNameShouldNotHaveAbbr),
+			expectedResult: fmt.Sprintf("lint_ms_seconds:"+metriclint.LintErrMsgAbbreviatedUnit, "ms", `"seconds"`),
+		},
+		{
+			name: "should not have quantile label",
+			opts: prometheus.SummaryOpts{
+				Name: "lint_test_seconds",
+				Help: "this is help message",
+			},
+			labelNames: []string{"quantile"},
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", metriclint.LintErrMsgSummaryQuantileLabel),
 		},
 	}
 
@@ -1136,3 +1254,99 @@ func TestLintSummaryVector(t *testing.T) {
 		})
 	}
 }
+
+func TestLinter(t *testing.T) {
+	opts := prometheus.GaugeOpts{Name: "lint_queue_depth"}
+
+	linttest.AssertIssues(t, LintGauge(opts), metriclint.RuleHelpMissing)
+
+	linter := NewLinter(metriclint.NewLinter(metriclint.DisableRules(metriclint.RuleHelpMissing, metriclint.RuleVectorLabels)))
+	linttest.AssertNoIssues(t, linter.LintGauge(opts))
+	linttest.AssertNoIssues(t, linter.LintGaugeVector(opts, nil))
+}
+
+func TestLintUntyped(t *testing.T) {
+	var tests = []struct {
+		name     string
+		result   *metriclint.LintResult
+		expected []string
+	}{
+		{
+			name:     "advisory only",
+			result:   LintUntyped(prometheus.UntypedOpts{Name: "lint_bridged_requests_total", Help: "Total number of bridged requests."}),
+			expected: []string{metriclint.RuleUntypedMetric},
+		},
+		{
+			name:     "common rules",
+			result:   LintUntyped(prometheus.UntypedOpts{Name: "lint_bridged_latency_ms"}),
+			expected: []string{metriclint.RuleHelpMissing, metriclint.RuleNameAbbreviatedUnit, metriclint.RuleUntypedMetric},
+		},
+		{
+			name:     "vector labels",
+			result:   LintUntypedVector(prometheus.UntypedOpts{Name: "lint_bridged_requests_total", Help: "Total number of bridged requests."}, []string{"code", "code"}),
+			expected: []string{metriclint.RuleLabelDuplicate, metriclint.RuleUntypedMetric},
+		},
+		{
+			name:   "advisory disabled",
+			result: NewLinter(metriclint.NewLinter(metriclint.DisableRules(metriclint.RuleUntypedMetric))).LintUntyped(prometheus.UntypedOpts{Name: "lint_bridged_requests_total", Help: "Total number of bridged requests."}),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			linttest.AssertIssues(t, tc.result, tc.expected...)
+		})
+	}
+}
+
+func TestLintFunc(t *testing.T) {
+	var tests = []struct {
+		name     string
+		result   *metriclint.LintResult
+		expected []string
+	}{
+		{
+			name:   "counter func",
+			result: LintCounterFunc(prometheus.CounterOpts{Name: "lint_process_cpu_seconds_total", Help: "Total user and system CPU time spent in seconds."}),
+		},
+		{
+			name:     "counter func with decreasing value",
+			result:   LintCounterFunc(prometheus.CounterOpts{Name: "lint_queue_length_total", Help: "Current length of the queue."}),
+			expected: []string{metriclint.RuleCounterFuncDecreasing},
+		},
+		{
+			name:     "counter func without total suffix",
+			result:   LintCounterFunc(prometheus.CounterOpts{Name: "lint_process_cpu_seconds", Help: "Total user and system CPU time spent in seconds."}),
+			expected: []string{metriclint.RuleCounterTotalSuffix},
+		},
+		{
+			name:   "gauge func",
+			result: LintGaugeFunc(prometheus.GaugeOpts{Name: "lint_queue_length", Help: "Current length of the queue."}),
+		},
+		{
+			name:     "gauge func with total suffix",
+			result:   LintGaugeFunc(prometheus.GaugeOpts{Name: "lint_queue_length_total", Help: "Current length of the queue."}),
+			expected: []string{metriclint.RuleNonCounterTotalSuffix},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			linttest.AssertIssues(t, tc.result, tc.expected...)
+		})
+	}
+}
+
+func TestOptsSpecBucketsObjectives(t *testing.T) {
+	buckets := []float64{0.1, 1, 10}
+	if spec := HistogramSpec(prometheus.HistogramOpts{Name: "lint_duration_seconds", Buckets: buckets}, nil); !reflect.DeepEqual(spec.Buckets, buckets) {
+		t.Errorf("expected: %v, but got: %v", buckets, spec.Buckets)
+	}
+
+	objectives := map[float64]float64{0.5: 0.05}
+	if spec := SummarySpec(prometheus.SummaryOpts{Name: "lint_duration_seconds", Objectives: objectives}, nil); !reflect.DeepEqual(spec.Objectives, objectives) {
+		t.Errorf("expected: %v, but got: %v", objectives, spec.Objectives)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// PushErrorsHeader is set by PushAnnotate on the forwarded push and on its response to the number of
+// metrics whose issues fail the verdict of the linter, see metriclint.Linter.Judge.
+const PushErrorsHeader = "X-Metriclint-Errors"
+
+// DefaultPushMaxBodyBytes is the size limit of a push without PushOpts.MaxBodyBytes.
+const DefaultPushMaxBodyBytes = 16 << 20
+
+// PushAction is what a push gatekeeper does with a push having metrics whose issues fail the verdict
+// of the linter.
+type PushAction int
+
+const (
+	// PushReject responds with 400 Bad Request listing the metrics failing the verdict, the push is
+	// not forwarded.
+	PushReject PushAction = iota
+
+	// PushAnnotate forwards the push, with the PushErrorsHeader set.
+	PushAnnotate
+)
+
+// PushOpts configures a push gatekeeper.
+type PushOpts struct {
+	Action PushAction
+
+	// Linter linting the pushed families, the default rules if nil. Its FailOn threshold decides which
+	// issues fail a push.
+	Linter *Linter
+
+	// MaxBodyBytes limits the size of a push, larger pushes are refused with 413 Request Entity Too Large
+	// whatever the action, as they can't be linted. DefaultPushMaxBodyBytes if zero.
+	MaxBodyBytes int64
+}
+
+// pushGatekeeper lints the families pushed to a Pushgateway, in text or delimited protobuf format.
+type pushGatekeeper struct {
+	opts PushOpts
+}
+
+func newPushGatekeeper(opts PushOpts) pushGatekeeper {
+	if opts.Linter == nil {
+		opts.Linter = defaultLinter
+	}
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = DefaultPushMaxBodyBytes
+	}
+
+	return pushGatekeeper{opts: opts}
+}
+
+// check reads the pushed body, up to the size limit, and returns it with the results failing the verdict
+// of the linter. Pushes other than PUT and POST, which delete or read metrics, have no body to lint.
+// It fails with a *http.MaxBytesError if the body exceeds the limit.
+func (g pushGatekeeper) check(w http.ResponseWriter, method string, header http.Header, body io.ReadCloser) ([]byte, []*metriclint.LintResult, error) {
+	if body == nil {
+		return nil, nil, nil
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, body, g.opts.MaxBodyBytes))
+	if err != nil || method != http.MethodPut && method != http.MethodPost {
+		return data, nil, err
+	}
+
+	format := expfmt.ResponseFormat(header)
+	if format == expfmt.FmtUnknown {
+		format = expfmt.FmtText
+	}
+	decoder := expfmt.NewDecoder(bytes.NewReader(data), format)
+
+	var failed []*metriclint.LintResult
+	for {
+		mf := &dto.MetricFamily{}
+		if err := decoder.Decode(mf); err == io.EOF {
+			break
+		} else if err != nil {
+			return data, nil, fmt.Errorf("metriclint: invalid push: %v", err)
+		}
+		for _, result := range g.opts.Linter.LintMetricFamily(mf) {
+			if g.opts.Linter.Judge([]*metriclint.LintResult{result}).Failed() {
+				failed = append(failed, result)
+			}
+		}
+	}
+
+	return data, failed, nil
+}
+
+// tooLarge returns the error message of a push exceeding the size limit, if err is a *http.MaxBytesError.
+func tooLarge(err error) (string, bool) {
+	var maxBytes *http.MaxBytesError
+	if !errors.As(err, &maxBytes) {
+		return "", false
+	}
+
+	return fmt.Sprintf("metriclint: push larger than %d bytes", maxBytes.Limit), true
+}
+
+// rejection returns the body of a rejected push.
+func rejection(results []*metriclint.LintResult) string {
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		lines = append(lines, result.String())
+	}
+
+	return "metriclint: push rejected:\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// NewPushGatekeeper returns a handler in front of a Pushgateway, next, linting each pushed payload and
+// rejecting or annotating the pushes failing the verdict of the linter according to the options, so that
+// batch jobs can't push metrics breaking the conventions. Malformed payloads are rejected with PushReject
+// and forwarded as is with PushAnnotate.
+func NewPushGatekeeper(next http.Handler, opts PushOpts) http.Handler {
+	g := newPushGatekeeper(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, failed, err := g.check(w, r.Method, r.Header, r.Body)
+		if msg, ok := tooLarge(err); ok {
+			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if r.Body != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		}
+
+		switch {
+		case g.opts.Action == PushAnnotate:
+			if err == nil {
+				r.Header.Set(PushErrorsHeader, strconv.Itoa(len(failed)))
+				w.Header().Set(PushErrorsHeader, strconv.Itoa(len(failed)))
+			}
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case len(failed) > 0:
+			http.Error(w, rejection(failed), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// pushTransport is the http.RoundTripper of NewPushGatekeeperTransport.
+type pushTransport struct {
+	gatekeeper pushGatekeeper
+	next       http.RoundTripper
+}
+
+// NewPushGatekeeperTransport returns a RoundTripper linting the payloads pushed through it, like
+// NewPushGatekeeper but on the side of the batch job, e.g. as the client of a push.Pusher. Rejected
+// pushes get a 400 Bad Request, or 413 Request Entity Too Large, response without being sent. next is
+// http.DefaultTransport if nil.
+func NewPushGatekeeperTransport(next http.RoundTripper, opts PushOpts) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return pushTransport{gatekeeper: newPushGatekeeper(opts), next: next}
+}
+
+func (t pushTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	data, failed, err := t.gatekeeper.check(nil, req.Method, req.Header, req.Body)
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if msg, ok := tooLarge(err); ok {
+		return rejectedResponse(req, http.StatusRequestEntityTooLarge, msg), nil
+	}
+
+	// A RoundTripper must not modify the request, the body is replaced on a clone.
+	clone := req.Clone(req.Context())
+	if req.Body != nil {
+		clone.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	switch {
+	case t.gatekeeper.opts.Action == PushAnnotate:
+		if err == nil {
+			clone.Header.Set(PushErrorsHeader, strconv.Itoa(len(failed)))
+		}
+		resp, rtErr := t.next.RoundTrip(clone)
+		if rtErr == nil && err == nil {
+			resp.Header.Set(PushErrorsHeader, strconv.Itoa(len(failed)))
+		}
+		return resp, rtErr
+	case err != nil:
+		return rejectedResponse(req, http.StatusBadRequest, err.Error()), nil
+	case len(failed) > 0:
+		return rejectedResponse(req, http.StatusBadRequest, rejection(failed)), nil
+	}
+
+	return t.next.RoundTrip(clone)
+}
+
+// rejectedResponse returns the response of a rejected push, with the status code.
+func rejectedResponse(req *http.Request, code int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
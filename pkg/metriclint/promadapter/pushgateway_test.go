@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const (
+	goodPush = "# HELP job_last_success_timestamp_seconds Last success of the job.\n# TYPE job_last_success_timestamp_seconds gauge\njob_last_success_timestamp_seconds 1.6e+09\n"
+	badPush  = "# TYPE jobDuration gauge\njobDuration 3\n"
+	// warningPush only has a high cardinality label warning.
+	warningPush = "# HELP job_requests_in_flight Requests in flight.\n# TYPE job_requests_in_flight gauge\njob_requests_in_flight{path=\"/\"} 1\n"
+)
+
+func TestPushGatekeeper(t *testing.T) {
+	var tests = []struct {
+		name         string
+		action       PushAction
+		linter       *Linter
+		maxBodyBytes int64
+		method       string
+		body         string
+		status       int
+		errors       string
+		received     bool
+	}{
+		{
+			name:     "valid push",
+			action:   PushReject,
+			method:   http.MethodPut,
+			body:     goodPush,
+			status:   http.StatusOK,
+			received: true,
+		},
+		{
+			name:   "rejected push",
+			action: PushReject,
+			method: http.MethodPost,
+			body:   badPush,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:     "warning push",
+			action:   PushReject,
+			method:   http.MethodPut,
+			body:     warningPush,
+			status:   http.StatusOK,
+			received: true,
+		},
+		{
+			name:   "warning push failing on warnings",
+			action: PushReject,
+			linter: NewLinter(metriclint.NewLinter(metriclint.FailOn(metriclint.SeverityWarning))),
+			method: http.MethodPut,
+			body:   warningPush,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:         "oversized push",
+			action:       PushAnnotate,
+			maxBodyBytes: 16,
+			method:       http.MethodPut,
+			body:         goodPush,
+			status:       http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "malformed push",
+			action: PushReject,
+			method: http.MethodPut,
+			body:   "jobDuration{ 3\n",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:     "annotated push",
+			action:   PushAnnotate,
+			method:   http.MethodPost,
+			body:     badPush,
+			status:   http.StatusOK,
+			errors:   "1",
+			received: true,
+		},
+		{
+			name:     "delete",
+			action:   PushReject,
+			method:   http.MethodDelete,
+			status:   http.StatusOK,
+			received: true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			var received string
+			pushgateway := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := ioutil.ReadAll(r.Body)
+				received = string(data) + r.Header.Get(PushErrorsHeader)
+			})
+
+			check := func(t *testing.T, resp *http.Response) {
+				if resp.StatusCode != tc.status {
+					t.Errorf("expected: %d, but got: %d", tc.status, resp.StatusCode)
+				}
+				if errors := resp.Header.Get(PushErrorsHeader); errors != tc.errors {
+					t.Errorf("expected: %q, but got: %q", tc.errors, errors)
+				}
+				if expected := tc.body + tc.errors; tc.received && received != expected {
+					t.Errorf("expected: %q, but got: %q", expected, received)
+				}
+				if !tc.received && received != "" {
+					t.Errorf("expected the push not to be forwarded, but got: %q", received)
+				}
+			}
+
+			t.Run("handler", func(t *testing.T) {
+				received = ""
+				recorder := httptest.NewRecorder()
+				req := httptest.NewRequest(tc.method, "/metrics/job/backup", strings.NewReader(tc.body))
+				NewPushGatekeeper(pushgateway, PushOpts{Action: tc.action, Linter: tc.linter, MaxBodyBytes: tc.maxBodyBytes}).ServeHTTP(recorder, req)
+				check(t, recorder.Result())
+			})
+
+			t.Run("transport", func(t *testing.T) {
+				received = ""
+				server := httptest.NewServer(pushgateway)
+				defer server.Close()
+				client := &http.Client{Transport: NewPushGatekeeperTransport(nil, PushOpts{Action: tc.action, Linter: tc.linter, MaxBodyBytes: tc.maxBodyBytes})}
+				req, err := http.NewRequest(tc.method, server.URL+"/metrics/job/backup", strings.NewReader(tc.body))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				defer resp.Body.Close()
+				check(t, resp)
+			})
+		})
+	}
+}
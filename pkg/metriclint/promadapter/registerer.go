@@ -0,0 +1,325 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const (
+	LintErrMsgHelpDrift  = `help text %q differs from the already registered %q`
+	LintErrMsgLabelDrift = `labels %v differ from the already registered %v`
+	LintErrMsgTypeDrift  = `type %s differs from the already registered %s`
+
+	LintErrMsgLabelShadowsDroppedConstLabel = `a variable label of %v shadows a const label`
+)
+
+// Action is what a LintingRegisterer does with a collector having issues.
+type Action int
+
+const (
+	// ActionLog logs the issues and registers the collector.
+	ActionLog Action = iota
+
+	// ActionRecord only records the issues, see LintingRegisterer.Results, and registers the collector.
+	ActionRecord
+
+	// ActionReject refuses to register the collector if its issues fail the verdict of the linter, see
+	// metriclint.Linter.Judge, Register returns a *RejectedError. The other issues are logged.
+	ActionReject
+)
+
+// Policy configures a LintingRegisterer.
+type Policy struct {
+	Action Action
+
+	// Linter linting the collectors, the default rules if nil.
+	Linter *Linter
+
+	// Logf logs the issues for ActionLog, log.Printf if nil.
+	Logf func(format string, args ...interface{})
+
+	// Results counts the issues of the linted collectors, whatever the action, if not nil.
+	Results *ResultsCollector
+
+	// EnforcePercent soft-launches ActionReject: only the metrics whose name hashes into this percentage
+	// are rejected, the issues of the others are logged. Zero or 100 enforces all metrics. The hash is
+	// stable, raising the percentage keeps enforcing the metrics enforced so far.
+	EnforcePercent int
+}
+
+// enforced reports whether ActionReject applies to the metric under the rollout percentage.
+func (p Policy) enforced(metricName string) bool {
+	if p.EnforcePercent <= 0 || p.EnforcePercent >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(metricName))
+
+	return int(h.Sum32()%100) < p.EnforcePercent
+}
+
+// RejectedError is returned when a LintingRegisterer refuses to register a collector.
+type RejectedError struct {
+	// Results with issues of the collector's metrics.
+	Results []*metriclint.LintResult
+}
+
+func (e *RejectedError) Error() string {
+	results := make([]string, 0, len(e.Results))
+	for _, result := range e.Results {
+		results = append(results, result.String())
+	}
+
+	return "metriclint: collector rejected: " + strings.Join(results, "; ")
+}
+
+// Unwrap returns the results as a *metriclint.LintError, so that errors.As extracts them from the errors
+// of Register and from the panics of MustRegister like from the other strict entry points.
+func (e *RejectedError) Unwrap() error {
+	return &metriclint.LintError{Results: e.Results}
+}
+
+// LintingRegisterer is a prometheus.Registerer linting the Descs of the collectors on registration,
+// so that bad metrics are caught at the registry rather than in review.
+//
+// When the inner Registerer returns a prometheus.AlreadyRegisteredError, the differences between the
+// help, labels and type of the existing collector and the new one are reported, logged unless the
+// action is ActionRecord, and the error is returned as is, so callers can still reuse the existing one.
+//
+// Metrics scheduled for removal, see metriclint.Tombstone, are registered with a warning whatever
+// the action, and their issues are not reported: they are tracked apart, see Tombstoned.
+//
+// The metrics and vectors of client_golang are linted with the rules of their type, the collectors are
+// not collected before their registration, the Descs of hand-written collectors are linted as untyped.
+type LintingRegisterer struct {
+	inner  prometheus.Registerer
+	policy Policy
+
+	mu         sync.Mutex
+	results    []*metriclint.LintResult
+	tombstoned []metriclint.Tombstone
+}
+
+var _ prometheus.Registerer = &LintingRegisterer{}
+
+// NewLintingRegisterer returns a LintingRegisterer registering into inner according to the policy.
+func NewLintingRegisterer(inner prometheus.Registerer, policy Policy) *LintingRegisterer {
+	if policy.Linter == nil {
+		policy.Linter = defaultLinter
+	}
+	if policy.Logf == nil {
+		policy.Logf = log.Printf
+	}
+
+	return &LintingRegisterer{inner: inner, policy: policy}
+}
+
+// Register lints the collector, then registers it into the inner Registerer unless the policy rejects it.
+func (r *LintingRegisterer) Register(c prometheus.Collector) error {
+	results, tombstoned, err := r.policy.Linter.lintCollector(c)
+	if err != nil {
+		return err
+	}
+	metriclint.Debugf("linted collector %T: %d metrics with issues", c, len(results))
+
+	if len(tombstoned) > 0 {
+		r.mu.Lock()
+		r.tombstoned = append(r.tombstoned, tombstoned...)
+		r.mu.Unlock()
+
+		for _, t := range tombstoned {
+			r.policy.Logf("metriclint: %s", tombstoneWarning(t))
+		}
+	}
+
+	if len(results) > 0 {
+		r.mu.Lock()
+		r.results = append(r.results, results...)
+		r.mu.Unlock()
+		r.observe(results)
+
+		switch r.policy.Action {
+		case ActionLog:
+			for _, result := range results {
+				r.policy.Logf("metriclint: %s", result)
+			}
+		case ActionReject:
+			var enforced []*metriclint.LintResult
+			for _, result := range results {
+				switch {
+				case !r.policy.Linter.Judge([]*metriclint.LintResult{result}).Failed():
+					r.policy.Logf("metriclint: %s", result)
+				case r.policy.enforced(result.MetricName):
+					enforced = append(enforced, result)
+				default:
+					r.policy.Logf("metriclint: %s (not enforced yet)", result)
+				}
+			}
+			if len(enforced) > 0 {
+				return &RejectedError{Results: enforced}
+			}
+		}
+	}
+
+	err = r.inner.Register(c)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		r.reportDrift(are)
+	}
+
+	return err
+}
+
+// observe feeds the results to the ResultsCollector of the policy, if any.
+func (r *LintingRegisterer) observe(results []*metriclint.LintResult) {
+	if r.policy.Results != nil {
+		r.policy.Results.Observe(results...)
+	}
+}
+
+// reportDrift records the differences between the existing and the new collector of the error.
+func (r *LintingRegisterer) reportDrift(are prometheus.AlreadyRegisteredError) {
+	results, err := registrationDrift(are.ExistingCollector, are.NewCollector)
+	if err != nil {
+		metriclint.Debugf("skipping registration drift check: %v", err)
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.results = append(r.results, results...)
+	r.mu.Unlock()
+	r.observe(results)
+
+	if r.policy.Action != ActionRecord {
+		for _, result := range results {
+			r.policy.Logf("metriclint: %s", result)
+		}
+	}
+}
+
+// registrationDrift compares the metrics the new collector has in common with the existing one.
+// Types are only compared when both collectors collect the metric.
+func registrationDrift(existing, c prometheus.Collector) ([]*metriclint.LintResult, error) {
+	existingSpecs, err := collectorSpecs(existing, true)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]metriclint.MetricSpec{}
+	for _, spec := range existingSpecs {
+		byName[spec.FQName()] = spec
+	}
+
+	specs, err := collectorSpecs(c, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*metriclint.LintResult
+	for _, spec := range specs {
+		old, ok := byName[spec.FQName()]
+		if !ok {
+			continue
+		}
+
+		var issues []string
+		if spec.Help != old.Help {
+			issues = append(issues, fmt.Sprintf(LintErrMsgHelpDrift, spec.Help, old.Help))
+		}
+		if labels, oldLabels := specLabelNames(spec), specLabelNames(old); !reflect.DeepEqual(labels, oldLabels) {
+			issues = append(issues, fmt.Sprintf(LintErrMsgLabelDrift, labels, oldLabels))
+		}
+		if spec.Type != old.Type && spec.Type != metriclint.MetricTypeUntyped && old.Type != metriclint.MetricTypeUntyped {
+			issues = append(issues, fmt.Sprintf(LintErrMsgTypeDrift, spec.Type, old.Type))
+		}
+		if len(issues) > 0 {
+			result := &metriclint.LintResult{MetricName: spec.FQName()}
+			result.AddRuleMessages(metriclint.RuleRegistrationDrift, issues...)
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// specLabelNames returns the sorted const and variable label names of the spec.
+func specLabelNames(spec metriclint.MetricSpec) []string {
+	names := append([]string{}, spec.VariableLabels...)
+	for name := range spec.ConstLabels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// MustRegister registers the collectors like Register and panics on the first error.
+func (r *LintingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Unregister unregisters the collector from the inner Registerer.
+func (r *LintingRegisterer) Unregister(c prometheus.Collector) bool {
+	return r.inner.Unregister(c)
+}
+
+// Results returns the results with issues of all the collectors linted so far, whatever the action.
+func (r *LintingRegisterer) Results() []*metriclint.LintResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*metriclint.LintResult(nil), r.results...)
+}
+
+// Tombstoned returns the tombstones of the metrics scheduled for removal registered so far.
+func (r *LintingRegisterer) Tombstoned() []metriclint.Tombstone {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]metriclint.Tombstone(nil), r.tombstoned...)
+}
+
+func tombstoneWarning(t metriclint.Tombstone) string {
+	msg := fmt.Sprintf("metric %s is scheduled for removal", t.Metric)
+	if t.RemovedIn != "" {
+		msg += " in " + t.RemovedIn
+	}
+	if t.Replacement != "" {
+		msg += ", use " + t.Replacement + " instead"
+	}
+	if t.Reason != "" {
+		msg += ": " + t.Reason
+	}
+
+	return msg
+}
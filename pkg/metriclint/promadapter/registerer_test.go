@@ -0,0 +1,366 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+	"github.com/promlint/promlint/pkg/metriclint/linttest"
+)
+
+func TestLintingRegisterer(t *testing.T) {
+	var tests = []struct {
+		name       string
+		action     Action
+		registered bool
+		logged     int
+	}{
+		{name: "log", action: ActionLog, registered: true, logged: 1},
+		{name: "record", action: ActionRecord, registered: true},
+		{name: "reject", action: ActionReject},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			var logged []string
+			reg := prometheus.NewRegistry()
+			r := NewLintingRegisterer(reg, Policy{Action: tc.action, Logf: func(format string, args ...interface{}) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}})
+
+			good := prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_good_total", Help: "this is help message"})
+			bad := prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_bad", Help: "this is help message"})
+			if err := r.Register(good); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := r.Register(bad)
+			if tc.registered != (err == nil) {
+				t.Errorf("expected registered: %v, but got: %v", tc.registered, err)
+			}
+			if rejected, ok := err.(*RejectedError); err != nil && (!ok || len(rejected.Results) != 1) {
+				t.Errorf("expected: *RejectedError, but got: %v", err)
+			}
+			if len(logged) != tc.logged {
+				t.Errorf("expected: %d logged, but got: %v", tc.logged, logged)
+			}
+
+			expected := "lint_bad:" + metriclint.LintErrMsgCounterShouldHaveTotalSuffix
+			if results := r.Results(); len(results) != 1 || results[0].String() != expected {
+				t.Errorf("expected: %s, but got: %v", expected, results)
+			}
+			if !r.Unregister(good) {
+				t.Errorf("expected the good collector to be registered")
+			}
+		})
+	}
+}
+
+func TestLintingRegistererRejectWarnings(t *testing.T) {
+	var logged []string
+	policy := Policy{Action: ActionReject, Logf: func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}}
+
+	// The high cardinality label is a warning, it doesn't fail the default fail-on threshold.
+	requests := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "lint_requests_in_flight", Help: "this is help message"}, []string{"path"})
+	if err := NewLintingRegisterer(prometheus.NewRegistry(), policy).Register(requests); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected the warning to be logged, but got: %v", logged)
+	}
+
+	policy.Linter = NewLinter(metriclint.NewLinter(metriclint.FailOn(metriclint.SeverityWarning)))
+	err := NewLintingRegisterer(prometheus.NewRegistry(), policy).Register(requests)
+	if _, ok := err.(*RejectedError); !ok {
+		t.Errorf("expected: *RejectedError, but got: %v", err)
+	}
+}
+
+// uncollectable is a hand-written collector failing the test if it's collected.
+type uncollectable struct {
+	t *testing.T
+}
+
+func (c uncollectable) Describe(ch chan<- *prometheus.Desc) {
+	ch <- prometheus.NewDesc("lint_jobs", "this is help message", nil, nil)
+}
+
+func (c uncollectable) Collect(chan<- prometheus.Metric) {
+	c.t.Errorf("unexpected collection before registration")
+}
+
+func TestLintingRegistererEmptyVector(t *testing.T) {
+	var tests = []struct {
+		name      string
+		collector prometheus.Collector
+		expected  string
+	}{
+		{
+			name:      "counter",
+			collector: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests", Help: "this is help message"}, []string{"code"}),
+			expected:  metriclint.LintErrMsgCounterShouldHaveTotalSuffix,
+		},
+		{
+			name:      "gauge",
+			collector: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "lint_requests_total", Help: "this is help message"}, []string{"code"}),
+			expected:  metriclint.LintErrMsgNonCounterShouldNotHaveTotalSuffix,
+		},
+		{
+			name:      "histogram",
+			collector: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "lint_request_duration_seconds", Help: "this is help message", Buckets: []float64{3, 2, 1}}, []string{"le"}),
+			expected:  metriclint.LintErrMsgHistogramLeLabel,
+		},
+		{
+			name:      "summary",
+			collector: prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "lint_request_duration_seconds", Help: "this is help message"}, []string{"le"}),
+			expected:  metriclint.LintErrMsgNonHistogramShouldNotHaveLeLabel,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{Action: ActionReject})
+
+			// A vector without children collects nothing, its type comes from its Go type.
+			err := r.Register(tc.collector)
+			var rejected *RejectedError
+			if !errors.As(err, &rejected) || !strings.Contains(rejected.Error(), tc.expected) {
+				t.Errorf("expected: a rejection for %s, but got: %v", tc.expected, err)
+			}
+		})
+	}
+
+	// Hand-written collectors are linted as untyped, without collecting them.
+	r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{Action: ActionReject})
+	if err := r.Register(uncollectable{t: t}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLintingRegistererRollout(t *testing.T) {
+	names := []string{"lint_a", "lint_b", "lint_c", "lint_d", "lint_e", "lint_f", "lint_g", "lint_h"}
+
+	rejected := func(percent int) map[string]bool {
+		var logged int
+		r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{
+			Action:         ActionReject,
+			EnforcePercent: percent,
+			Logf:           func(string, ...interface{}) { logged++ },
+		})
+
+		result := map[string]bool{}
+		for _, name := range names {
+			c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: "this is help message"})
+			if err := r.Register(c); err != nil {
+				result[name] = true
+			}
+		}
+		if logged+len(result) != len(names) {
+			t.Errorf("expected every metric to be rejected or logged, but got: %d rejected, %d logged", len(result), logged)
+		}
+
+		return result
+	}
+
+	if all := rejected(0); len(all) != len(names) {
+		t.Errorf("expected: %d rejected, but got: %v", len(names), all)
+	}
+
+	half := rejected(50)
+	if len(half) == 0 || len(half) == len(names) {
+		t.Errorf("expected some metrics rejected at 50%%, but got: %v", half)
+	}
+	for name := range half {
+		if !rejected(80)[name] {
+			t.Errorf("expected %s to stay rejected when raising the percentage", name)
+		}
+	}
+}
+
+func TestLintingRegistererTombstones(t *testing.T) {
+	var logged []string
+	r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{
+		Action: ActionReject,
+		Linter: NewLinter(metriclint.NewLinter(metriclint.WithTombstones(metriclint.Tombstone{
+			Metric:      "lint_legacy_requests",
+			Replacement: "lint_requests_total",
+			RemovedIn:   "v2.0",
+		}))),
+		Logf: func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		},
+	})
+
+	// The tombstoned metric misses the total suffix, it is registered anyway.
+	legacy := prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_legacy_requests", Help: "this is help message"})
+	if err := r.Register(legacy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "metriclint: metric lint_legacy_requests is scheduled for removal in v2.0, use lint_requests_total instead"
+	if len(logged) != 1 || logged[0] != expected {
+		t.Errorf("expected: %s, but got: %v", expected, logged)
+	}
+	if results := r.Results(); len(results) != 0 {
+		t.Errorf("expected no result, but got: %v", results)
+	}
+	if tombstoned := r.Tombstoned(); len(tombstoned) != 1 || tombstoned[0].Metric != "lint_legacy_requests" {
+		t.Errorf("expected: lint_legacy_requests, but got: %v", tombstoned)
+	}
+}
+
+func TestLintingRegistererDebugLogs(t *testing.T) {
+	var logs []string
+	metriclint.SetLogger(metriclint.LoggerFunc(func(format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}))
+	defer metriclint.SetLogger(nil)
+
+	r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{
+		Action: ActionRecord,
+		Linter: NewLinter(metriclint.NewLinter(
+			metriclint.DisableRules(metriclint.RuleCounterTotalSuffix),
+			metriclint.WithTombstones(metriclint.Tombstone{Metric: "lint_legacy_requests"}),
+		)),
+	})
+	r.MustRegister(
+		prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_requests", Help: "this is help message"}),
+		prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_legacy_requests", Help: "this is help message"}),
+	)
+
+	for _, expected := range []string{
+		"skipping disabled rule " + metriclint.RuleCounterTotalSuffix + " for lint_requests",
+		"skipping rules of tombstoned metric lint_legacy_requests",
+	} {
+		found := false
+		for _, log := range logs {
+			found = found || log == expected
+		}
+		if !found {
+			t.Errorf("expected log: %s, but got: %v", expected, logs)
+		}
+	}
+}
+
+// alreadyRegistered is a Registerer reporting every collector as already registered as existing.
+type alreadyRegistered struct {
+	prometheus.Registerer
+	existing prometheus.Collector
+}
+
+func (r alreadyRegistered) Register(c prometheus.Collector) error {
+	return prometheus.AlreadyRegisteredError{ExistingCollector: r.existing, NewCollector: c}
+}
+
+func TestLintingRegistererAlreadyRegistered(t *testing.T) {
+	existing := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "Total number of requests."}, []string{"code"})
+	existing.WithLabelValues("200").Inc()
+
+	var tests = []struct {
+		name     string
+		inner    prometheus.Registerer
+		new      prometheus.Collector
+		expected []string
+	}{
+		{
+			name:  "same collector",
+			inner: alreadyRegistered{existing: existing},
+			new:   prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "Total number of requests."}, []string{"code"}),
+		},
+		{
+			name:  "help and label drift",
+			inner: alreadyRegistered{existing: existing},
+			new:   prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "Requests served."}, []string{"code", "method"}),
+			expected: []string{
+				fmt.Sprintf(LintErrMsgHelpDrift, "Requests served.", "Total number of requests."),
+				fmt.Sprintf(LintErrMsgLabelDrift, []string{"code", "method"}, []string{"code"}),
+			},
+		},
+		{
+			name: "type drift",
+			inner: func() prometheus.Registerer {
+				reg := prometheus.NewRegistry()
+				reg.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_queue_length", Help: "Length of the queue."}))
+				return reg
+			}(),
+			new:      prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_queue_length", Help: "Length of the queue."}),
+			expected: []string{fmt.Sprintf(LintErrMsgTypeDrift, metriclint.MetricTypeGauge, metriclint.MetricTypeCounter)},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			var logged int
+			r := NewLintingRegisterer(tc.inner, Policy{Logf: func(string, ...interface{}) { logged++ }})
+
+			err := r.Register(tc.new)
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				t.Fatalf("expected: prometheus.AlreadyRegisteredError, but got: %v", err)
+			}
+
+			var issues []string
+			for _, result := range r.Results() {
+				for _, issue := range result.Findings {
+					if issue.ID != metriclint.RuleRegistrationDrift {
+						t.Errorf("expected: %s, but got: %s", metriclint.RuleRegistrationDrift, issue.ID)
+					}
+					issues = append(issues, issue.Message)
+				}
+			}
+			if !reflect.DeepEqual(issues, tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, issues)
+			}
+			if logged != len(r.Results()) {
+				t.Errorf("expected: %d logged, but got: %d", len(r.Results()), logged)
+			}
+		})
+	}
+}
+
+func TestLintingRegistererShadowedConstLabel(t *testing.T) {
+	r := NewLintingRegisterer(prometheus.NewRegistry(), Policy{Action: ActionReject})
+
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message", ConstLabels: prometheus.Labels{"zone": "a"}}, []string{"zone"})
+	err := r.Register(vec)
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) || len(rejected.Results) != 1 {
+		t.Fatalf("expected: a rejection, but got: %v", err)
+	}
+	linttest.AssertIssues(t, rejected.Results[0], metriclint.RuleLabelShadowsConstLabel)
+	expected := fmt.Sprintf(LintErrMsgLabelShadowsDroppedConstLabel, []string{"zone"})
+	if rejected.Results[0].Issues[0] != expected {
+		t.Errorf("expected: %s, but got: %s", expected, rejected.Results[0].Issues[0])
+	}
+
+	// Distinct label names don't shadow.
+	vec = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_jobs_total", Help: "this is help message", ConstLabels: prometheus.Labels{"zone": "a"}}, []string{"code"})
+	if err := r.Register(vec); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"math"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// familyTypes maps the gathered metric types to the metriclint ones.
+var familyTypes = map[dto.MetricType]metriclint.MetricType{
+	dto.MetricType_COUNTER:   metriclint.MetricTypeCounter,
+	dto.MetricType_GAUGE:     metriclint.MetricTypeGauge,
+	dto.MetricType_HISTOGRAM: metriclint.MetricTypeHistogram,
+	dto.MetricType_SUMMARY:   metriclint.MetricTypeSummary,
+	dto.MetricType_UNTYPED:   metriclint.MetricTypeUntyped,
+}
+
+// FamilySpec converts a gathered metric family into a MetricSpec.
+//
+// A gathered family doesn't tell const labels from variable ones, the names of all labels found on
+// its metrics become VariableLabels. The buckets and the quantiles come from the first metric having
+// some, the allowed errors of the quantiles are not exposed and are zero.
+func FamilySpec(mf *dto.MetricFamily) metriclint.MetricSpec {
+	var labelNames []string
+	var buckets []float64
+	var objectives map[float64]float64
+	for _, m := range mf.GetMetric() {
+		for _, lp := range m.GetLabel() {
+			labelNames = append(labelNames, lp.GetName())
+		}
+		if buckets == nil {
+			for _, b := range m.GetHistogram().GetBucket() {
+				if !math.IsInf(b.GetUpperBound(), 1) {
+					buckets = append(buckets, b.GetUpperBound())
+				}
+			}
+		}
+		if objectives == nil && len(m.GetSummary().GetQuantile()) > 0 {
+			objectives = map[float64]float64{}
+			for _, q := range m.GetSummary().GetQuantile() {
+				objectives[q.GetQuantile()] = 0
+			}
+		}
+	}
+
+	return metriclint.MetricSpec{
+		Name:           mf.GetName(),
+		Help:           mf.GetHelp(),
+		Type:           familyTypes[mf.GetType()],
+		VariableLabels: metriclint.CanonicalLabelNames(nil, labelNames),
+		Buckets:        buckets,
+		Objectives:     objectives,
+	}
+}
+
+// LintRegistry gathers all metric families from the gatherer and lints each of them, in the order
+// of the gathered families. Families whose series have different label names are reported as duplicate
+// metrics, see metriclint.LintDuplicates.
+func (l *Linter) LintRegistry(gatherer prometheus.Gatherer) ([]*metriclint.LintResult, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	metriclint.Debugf("linting %d gathered metric families", len(families))
+
+	return l.lintFamilies(families), nil
+}
+
+// lintFamilies lints the families, in parallel with metriclint.WithParallelism, and reports with
+// metriclint.RuleDuplicateMetric the families whose series don't all have the same label names, as if
+// each label set was declared apart.
+func (l *Linter) lintFamilies(families []*dto.MetricFamily) []*metriclint.LintResult {
+	specs := make([]metriclint.MetricSpec, 0, len(families))
+	for _, mf := range families {
+		specs = append(specs, FamilySpec(mf))
+	}
+	results := l.LintSpecs(specs)
+
+	var declarations []metriclint.MetricSpec
+	var declarationResults []*metriclint.LintResult
+	for i, mf := range families {
+		for _, spec := range labelSetSpecs(mf) {
+			declarations = append(declarations, spec)
+			declarationResults = append(declarationResults, results[i])
+		}
+	}
+	l.LintDuplicates(declarations, declarationResults)
+
+	return results
+}
+
+// lintSeries checks the structure of the series of histogram and summary families. Decoded protobuf
+// families leave the +Inf bucket implicit, their sample count, parsed text payloads have it explicitly.
+func (l *Linter) lintSeries(result *metriclint.LintResult, mf *dto.MetricFamily, implicitInf bool) {
+	switch mf.GetType() {
+	case dto.MetricType_HISTOGRAM, dto.MetricType_SUMMARY:
+		l.LintScrapedSeries(result, familyTypes[mf.GetType()], scrapedSeries(mf, implicitInf))
+	}
+}
+
+// scrapedSeries returns the structure of the series of a histogram or summary family. The text parser
+// only sets the sum and the count of a series from its _sum and _count samples. With implicitInf, a +Inf bucket
+// counting the samples is added to the histogram series which don't have one.
+func scrapedSeries(mf *dto.MetricFamily, implicitInf bool) []metriclint.ScrapedSeries {
+	series := make([]metriclint.ScrapedSeries, 0, len(mf.GetMetric()))
+	for _, m := range mf.GetMetric() {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, lp := range m.GetLabel() {
+			labels[lp.GetName()] = lp.GetValue()
+		}
+
+		s := metriclint.ScrapedSeries{Labels: labels}
+		if h := m.GetHistogram(); h != nil {
+			for _, b := range h.GetBucket() {
+				s.Buckets = append(s.Buckets, metriclint.ScrapedBucket{UpperBound: b.GetUpperBound(), Count: float64(b.GetCumulativeCount())})
+			}
+			s.HasSum, s.HasCount, s.Count = h.SampleSum != nil, h.SampleCount != nil, float64(h.GetSampleCount())
+			if n := len(s.Buckets); implicitInf && (n == 0 || !math.IsInf(s.Buckets[n-1].UpperBound, 1)) {
+				s.Buckets = append(s.Buckets, metriclint.ScrapedBucket{UpperBound: math.Inf(1), Count: s.Count})
+			}
+		} else if sm := m.GetSummary(); sm != nil {
+			s.HasSum, s.HasCount, s.Count = sm.SampleSum != nil, sm.SampleCount != nil, float64(sm.GetSampleCount())
+		}
+		series = append(series, s)
+	}
+
+	return series
+}
+
+// labelSetSpecs returns a spec per distinct set of label names of the series of the family, in the
+// order of the series, or a spec without label if the family has no series.
+func labelSetSpecs(mf *dto.MetricFamily) []metriclint.MetricSpec {
+	base := metriclint.MetricSpec{Name: mf.GetName(), Help: mf.GetHelp(), Type: familyTypes[mf.GetType()]}
+	if len(mf.GetMetric()) == 0 {
+		return []metriclint.MetricSpec{base}
+	}
+
+	var specs []metriclint.MetricSpec
+	seen := map[string]bool{}
+	for _, m := range mf.GetMetric() {
+		var labelNames []string
+		for _, lp := range m.GetLabel() {
+			labelNames = append(labelNames, lp.GetName())
+		}
+		labelNames = metriclint.CanonicalLabelNames(nil, labelNames)
+		key := strings.Join(labelNames, ",")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		spec := base
+		spec.VariableLabels = labelNames
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+// LintMetricFamily lints a decoded family, e.g. pushed to a Pushgateway or filtered by a federation proxy, like
+// LintExposition lints each family of a payload: the label names of all its samples are linted, a family whose
+// samples have different label names is reported as a duplicate metric, and the structure of histogram and
+// summary series is checked. The family gets a single result, returned in a slice so that it can be appended
+// to the results of other families.
+func (l *Linter) LintMetricFamily(mf *dto.MetricFamily) []*metriclint.LintResult {
+	results := l.lintFamilies([]*dto.MetricFamily{mf})
+	l.lintSeries(results[0], mf, true)
+
+	return results
+}
+
+// LintMetricFamily lints a decoded family with the default rules.
+func LintMetricFamily(mf *dto.MetricFamily) []*metriclint.LintResult {
+	return defaultLinter.LintMetricFamily(mf)
+}
+
+// LintRegistry lints all metric families of the gatherer with the default rules, e.g. once at the end
+// of the startup of an application.
+func LintRegistry(gatherer prometheus.Gatherer) ([]*metriclint.LintResult, error) {
+	return defaultLinter.LintRegistry(gatherer)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestLintRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests", Help: "this is help message"}, []string{"code"})
+	inflight := prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_inflight_requests"})
+	reg.MustRegister(requests, inflight)
+	requests.WithLabelValues("200")
+
+	results, err := LintRegistry(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"lint_inflight_requests:" + metriclint.LintErrMsgNoHelp,
+		"lint_requests:" + metriclint.LintErrMsgCounterShouldHaveTotalSuffix,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i := range expected {
+		if results[i].String() != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], results[i])
+		}
+	}
+}
+
+func TestLintRegistryGatherError(t *testing.T) {
+	gatherer := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return nil, errors.New("collect failed")
+	})
+
+	if _, err := LintRegistry(gatherer); err == nil || err.Error() != "collect failed" {
+		t.Errorf("expected: %s, but got: %v", "collect failed", err)
+	}
+}
+
+func TestLintMetricFamily(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lint_duration_seconds",
+		Help:    "this is help message",
+		Buckets: []float64{0.1, 1},
+	}, []string{"method"})
+	reg.MustRegister(vec)
+	vec.WithLabelValues("get").Observe(0.05)
+	vec.WithLabelValues("put").Observe(0.5)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mf := families[0]
+	if results := LintMetricFamily(mf); len(results) != 1 || len(results[0].Findings) != 0 {
+		t.Fatalf("expected no issue, but got: %v", results)
+	}
+
+	// A pushed family whose samples have different label names and decreasing buckets.
+	labelName, labelValue := "userID", "1"
+	mf.Metric[1].Label = append(mf.Metric[1].Label, &dto.LabelPair{Name: &labelName, Value: &labelValue})
+	*mf.Metric[0].Histogram.Bucket[1].CumulativeCount = 0
+
+	results := LintMetricFamily(mf)
+	if len(results) != 1 {
+		t.Fatalf("expected a single result, but got: %v", results)
+	}
+	issues := map[string]bool{}
+	for _, issue := range results[0].Findings {
+		issues[issue.ID] = true
+	}
+	for _, id := range []string{metriclint.RuleLabelCamelCase, metriclint.RuleDuplicateMetric, metriclint.RuleHistogramBucketsCumulative} {
+		if !issues[id] {
+			t.Errorf("expected: %s, but got: %v", id, results[0].Findings)
+		}
+	}
+}
+
+func TestFamilySpec(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:        "lint_duration_seconds",
+		Help:        "this is help message",
+		ConstLabels: prometheus.Labels{"zone": "a"},
+	}, []string{"method"})
+	reg.MustRegister(vec)
+	vec.WithLabelValues("get")
+	vec.WithLabelValues("put")
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec := FamilySpec(families[0])
+	if spec.Type != metriclint.MetricTypeHistogram || fmt.Sprint(spec.VariableLabels) != "[method zone]" {
+		t.Errorf("expected: histogram [method zone], but got: %s %v", spec.Type, spec.VariableLabels)
+	}
+	if !reflect.DeepEqual(spec.Buckets, prometheus.DefBuckets) {
+		t.Errorf("expected: %v, but got: %v", prometheus.DefBuckets, spec.Buckets)
+	}
+}
+
+func TestFamilySpecObjectives(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "lint_duration_seconds",
+		Help:       "this is help message",
+		Objectives: map[float64]float64{0.5: 0.05, 0.99: 0.001},
+	})
+	reg.MustRegister(summary)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[float64]float64{0.5: 0, 0.99: 0}
+	if spec := FamilySpec(families[0]); !reflect.DeepEqual(spec.Objectives, expected) {
+		t.Errorf("expected: %v, but got: %v", expected, spec.Objectives)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+var issuesTotalDesc = prometheus.NewDesc(
+	"metriclint_issues_total",
+	"Total number of lint issues found in the metrics of this process.",
+	[]string{"rule", "severity", "metric"}, nil,
+)
+
+// ResultsCollector exposes the issues of the lint results it observes as the metriclint_issues_total
+// counter, by rule, severity and metric, so that fleets can alert on services shipping non-conforming
+// metrics without scraping their logs.
+type ResultsCollector struct {
+	mu     sync.Mutex
+	counts map[issueKey]int
+}
+
+// issueKey identifies the series of an issue.
+type issueKey struct {
+	rule, severity, metric string
+}
+
+var _ prometheus.Collector = &ResultsCollector{}
+
+// NewResultsCollector returns a ResultsCollector without issue, see Policy.Results to feed it with
+// the results of a LintingRegisterer.
+func NewResultsCollector() *ResultsCollector {
+	return &ResultsCollector{counts: map[issueKey]int{}}
+}
+
+// Observe counts the issues of the results. Nil results are skipped.
+func (c *ResultsCollector) Observe(results ...*metriclint.LintResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		for _, issue := range result.Findings {
+			c.counts[issueKey{rule: issue.ID, severity: string(issue.Severity), metric: result.MetricName}]++
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ResultsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- issuesTotalDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ResultsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(issuesTotalDesc, prometheus.CounterValue, float64(count), k.rule, k.severity, k.metric)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestResultsCollector(t *testing.T) {
+	collector := NewResultsCollector()
+	registerer := NewLintingRegisterer(prometheus.NewRegistry(), Policy{Action: ActionRecord, Results: collector})
+
+	registerer.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_requests", Help: "Number of requests."}))
+	registerer.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_up", Help: "Whether the target is up."}))
+	registerer.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "lint_errors"}))
+	collector.Observe(nil, registerer.Results()[0])
+
+	expected := `
+# HELP metriclint_issues_total Total number of lint issues found in the metrics of this process.
+# TYPE metriclint_issues_total counter
+metriclint_issues_total{metric="lint_errors",rule="counter-total-suffix",severity="error"} 1
+metriclint_issues_total{metric="lint_errors",rule="help-missing",severity="error"} 1
+metriclint_issues_total{metric="lint_requests",rule="counter-total-suffix",severity="error"} 2
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestResultsCollectorFollowsConventions(t *testing.T) {
+	results, err := LintCollector(NewResultsCollector())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if len(result.Findings) > 0 {
+			t.Errorf("expected no issue, but got: %v", result.Findings)
+		}
+	}
+}
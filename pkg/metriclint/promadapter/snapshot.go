@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const (
+	LintErrMsgConstantZeroFamily = `all series have been zero for %d snapshots, consider registering the metric lazily or removing it`
+	LintErrMsgUnboundedLabel     = `label %q has %d distinct values, more than %d, top values: %s`
+	LintErrMsgUnboundedLabelCap  = `label %q has at least %d distinct values, more than %d, top values: %s`
+)
+
+// SnapshotRule is a stateful rule observing the gathered metric families over time.
+type SnapshotRule interface {
+	// Observe records a snapshot and returns the results of the metrics violating the rule so far.
+	Observe(families []*dto.MetricFamily) []*metriclint.LintResult
+}
+
+// SnapshotLinter gathers snapshots from a Gatherer and feeds them to stateful rules.
+type SnapshotLinter struct {
+	gatherer prometheus.Gatherer
+	rules    []SnapshotRule
+
+	mu sync.Mutex
+}
+
+// NewSnapshotLinter returns a SnapshotLinter applying the rules to snapshots of the gatherer.
+func NewSnapshotLinter(gatherer prometheus.Gatherer, rules ...SnapshotRule) *SnapshotLinter {
+	return &SnapshotLinter{gatherer: gatherer, rules: rules}
+}
+
+// Snapshot gathers the metric families once and returns the results of all rules.
+// It's safe for concurrent use, snapshots are observed one at a time.
+func (l *SnapshotLinter) Snapshot() ([]*metriclint.LintResult, error) {
+	families, err := l.gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var results []*metriclint.LintResult
+	for _, rule := range l.rules {
+		results = append(results, rule.Observe(families)...)
+	}
+
+	return results, nil
+}
+
+// ConstantZeroRule is an advisory rule flagging families where every series has been zero for a number
+// of consecutive snapshots. Such speculative metrics only bloat scrapes.
+type ConstantZeroRule struct {
+	// Number of consecutive all-zero snapshots before a family is flagged, at least 1.
+	Snapshots int
+
+	zeroSnapshots map[string]int
+}
+
+// NewConstantZeroRule returns a ConstantZeroRule flagging families zero for the given number of snapshots,
+// a number below 1 flags them on the first all-zero snapshot.
+func NewConstantZeroRule(snapshots int) *ConstantZeroRule {
+	return &ConstantZeroRule{Snapshots: snapshots, zeroSnapshots: map[string]int{}}
+}
+
+// Observe records a snapshot. Families missing from the snapshot are forgotten.
+func (r *ConstantZeroRule) Observe(families []*dto.MetricFamily) (results []*metriclint.LintResult) {
+	snapshots := r.Snapshots
+	if snapshots < 1 {
+		snapshots = 1
+	}
+
+	seen := make(map[string]int, len(families))
+	for _, mf := range families {
+		name := mf.GetName()
+		if familyIsZero(mf) {
+			seen[name] = r.zeroSnapshots[name] + 1
+		} else {
+			seen[name] = 0
+		}
+
+		if seen[name] >= snapshots {
+			result := &metriclint.LintResult{MetricName: name}
+			result.AddRuleMessages(metriclint.RuleConstantZero, fmt.Sprintf(LintErrMsgConstantZeroFamily, seen[name]))
+			results = append(results, result)
+		}
+	}
+	r.zeroSnapshots = seen
+
+	return results
+}
+
+// familyIsZero reports whether every series of the family is zero, i.e. has never been observed.
+func familyIsZero(mf *dto.MetricFamily) bool {
+	for _, m := range mf.GetMetric() {
+		switch {
+		case m.Counter != nil && m.Counter.GetValue() != 0,
+			m.Gauge != nil && m.Gauge.GetValue() != 0,
+			m.Untyped != nil && m.Untyped.GetValue() != 0,
+			m.Histogram != nil && m.Histogram.GetSampleCount() != 0,
+			m.Summary != nil && m.Summary.GetSampleCount() != 0:
+			return false
+		}
+	}
+
+	return true
+}
+
+// DefaultUnboundedLabelNames are label names that classically carry unbounded values.
+var DefaultUnboundedLabelNames = []string{"path", "url", "uri", "id", "user"}
+
+// UnboundedLabelRule is a runtime rule flagging labels whose number of distinct values, accumulated
+// across snapshots, exceeds a threshold. It only watches the configured label names.
+type UnboundedLabelRule struct {
+	// Label names to watch, DefaultUnboundedLabelNames if empty.
+	LabelNames []string
+
+	// Maximum number of distinct values a watched label may take.
+	MaxValues int
+
+	// Number of offending values reported in the message.
+	TopValues int
+
+	// Maximum number of distinct values stored per label, 10 times MaxValues if zero. Once a label
+	// reaches it, its new values are no longer recorded and it's reported with at least that many values.
+	MaxStoredValues int
+
+	// distinct values seen per metric and label, with the number of series carrying them in the last snapshot.
+	values map[string]map[string]map[string]int
+}
+
+// NewUnboundedLabelRule returns an UnboundedLabelRule watching DefaultUnboundedLabelNames.
+func NewUnboundedLabelRule(maxValues int) *UnboundedLabelRule {
+	return &UnboundedLabelRule{MaxValues: maxValues, TopValues: 5}
+}
+
+// Observe records a snapshot and returns a result for every metric having a watched label above the threshold.
+func (r *UnboundedLabelRule) Observe(families []*dto.MetricFamily) (results []*metriclint.LintResult) {
+	if r.values == nil {
+		r.values = map[string]map[string]map[string]int{}
+	}
+	watched := r.LabelNames
+	if len(watched) == 0 {
+		watched = DefaultUnboundedLabelNames
+	}
+	maxStored := r.MaxStoredValues
+	if maxStored <= 0 {
+		maxStored = 10 * r.MaxValues
+	}
+	if maxStored <= r.MaxValues {
+		maxStored = r.MaxValues + 1
+	}
+
+	for _, mf := range families {
+		name := mf.GetName()
+		labels := r.values[name]
+		if labels == nil {
+			labels = map[string]map[string]int{}
+			r.values[name] = labels
+		}
+		for _, values := range labels {
+			for v := range values {
+				values[v] = 0
+			}
+		}
+
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if !containsString(watched, lp.GetName()) {
+					continue
+				}
+				values := labels[lp.GetName()]
+				if values == nil {
+					values = map[string]int{}
+					labels[lp.GetName()] = values
+				}
+				if _, ok := values[lp.GetValue()]; !ok && len(values) >= maxStored {
+					continue
+				}
+				values[lp.GetValue()]++
+			}
+		}
+
+		var result *metriclint.LintResult
+		for _, label := range sortedLabelKeys(labels) {
+			values := labels[label]
+			if len(values) <= r.MaxValues {
+				continue
+			}
+			if result == nil {
+				result = &metriclint.LintResult{MetricName: name}
+			}
+			format := LintErrMsgUnboundedLabel
+			if len(values) >= maxStored {
+				format = LintErrMsgUnboundedLabelCap
+			}
+			result.AddRuleMessages(metriclint.RuleUnboundedLabel, fmt.Sprintf(format, label, len(values), r.MaxValues,
+				strings.Join(topValues(values, r.TopValues), ", ")))
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// topValues returns the n values carried by most series, ties broken by value.
+func topValues(values map[string]int, n int) []string {
+	sorted := make([]string, 0, len(values))
+	for v := range values {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if values[sorted[i]] != values[sorted[j]] {
+			return values[sorted[i]] > values[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	for i, v := range sorted {
+		sorted[i] = strconv.Quote(v)
+	}
+
+	return sorted
+}
+
+func sortedLabelKeys(m map[string]map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
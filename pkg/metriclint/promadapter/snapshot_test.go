@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestConstantZeroRule(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	idle := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_idle_total", Help: "this is help message"}, []string{"code"})
+	busy := prometheus.NewGauge(prometheus.GaugeOpts{Name: "lint_busy", Help: "this is help message"})
+	reg.MustRegister(idle, busy)
+	idle.WithLabelValues("200")
+
+	linter := NewSnapshotLinter(reg, NewConstantZeroRule(2))
+
+	results, err := linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no result after the first snapshot, but got: %v", results)
+	}
+
+	busy.Set(1)
+	results, err = linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fmt.Sprintf("lint_idle_total:"+LintErrMsgConstantZeroFamily, 2)
+	if len(results) != 1 || results[0].String() != expected {
+		t.Fatalf("expected: %s, but got: %v", expected, results)
+	}
+
+	idle.WithLabelValues("200").Inc()
+	results, err = linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no result once the family is non-zero, but got: %v", results)
+	}
+
+	// A rule without number of snapshots flags the families on the first all-zero snapshot.
+	busy.Set(0)
+	results, err = NewSnapshotLinter(reg, &ConstantZeroRule{}).Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = fmt.Sprintf("lint_busy:"+LintErrMsgConstantZeroFamily, 1)
+	if len(results) != 1 || results[0].String() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, results)
+	}
+}
+
+func TestUnboundedLabelRule(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message"}, []string{"path", "code"})
+	reg.MustRegister(requests)
+
+	rule := NewUnboundedLabelRule(2)
+	rule.TopValues = 2
+	linter := NewSnapshotLinter(reg, rule)
+
+	requests.WithLabelValues("/a", "200").Inc()
+	requests.WithLabelValues("/a", "500").Inc()
+	requests.WithLabelValues("/b", "200").Inc()
+	requests.WithLabelValues("/b", "404").Inc()
+	requests.WithLabelValues("/b", "503").Inc()
+	results, err := linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no result below the threshold, but got: %v", results)
+	}
+
+	// Distinct values accumulate across snapshots even if series get deleted.
+	requests.DeleteLabelValues("/a", "500")
+	requests.WithLabelValues("/c", "200").Inc()
+	results, err = linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fmt.Sprintf("lint_requests_total:"+LintErrMsgUnboundedLabel, "path", 3, 2, `"/b", "/a"`)
+	if len(results) != 1 || results[0].String() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, results)
+	}
+}
+
+func TestUnboundedLabelRuleMaxStoredValues(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "lint_requests_total", Help: "this is help message"}, []string{"path"})
+	reg.MustRegister(requests)
+
+	rule := &UnboundedLabelRule{MaxValues: 2, TopValues: 1, MaxStoredValues: 4}
+	linter := NewSnapshotLinter(reg, rule)
+	for i := 0; i < 10; i++ {
+		requests.WithLabelValues(fmt.Sprintf("/%d", i)).Inc()
+	}
+	requests.WithLabelValues("/0").Inc()
+
+	results, err := linter.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fmt.Sprintf("lint_requests_total:"+LintErrMsgUnboundedLabelCap, "path", 4, 2, `"/0"`)
+	if len(results) != 1 || results[0].String() != expected {
+		t.Errorf("expected: %s, but got: %v", expected, results)
+	}
+	if stored := len(rule.values["lint_requests_total"]["path"]); stored != 4 {
+		t.Errorf("expected: 4 stored values, but got: %d", stored)
+	}
+}
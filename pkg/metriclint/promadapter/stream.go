@@ -0,0 +1,166 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// LintExpositionStream lints a text format payload like LintExposition, but one family at a time, as it
+// is read, so that only the lines of the current family are held in memory, e.g. for federation dumps
+// of hundreds of megabytes. fn is called with the result of each family in the order of the payload,
+// and its error stops the linting and is returned.
+//
+// The lines of a family must be contiguous, as the text format requires: a family whose lines are
+// split by another family's is an error, since its series can't be linted together.
+func (l *Linter) LintExpositionStream(r io.Reader, fn func(*metriclint.LintResult) error) error {
+	r, err := metriclint.NewExpositionReader(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		br      = bufio.NewReader(r)
+		chunk   bytes.Buffer
+		family  string
+		typ     string
+		start   int
+		line    int
+		flushed = map[string]bool{}
+	)
+	flush := func() error {
+		if chunk.Len() == 0 {
+			return nil
+		}
+		defer chunk.Reset()
+		if flushed[family] {
+			return fmt.Errorf("line %d: lines of family %s are not contiguous", start, family)
+		}
+		flushed[family] = true
+
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(&chunk)
+		if err != nil {
+			if perr, ok := err.(expfmt.ParseError); ok {
+				perr.Line += start - 1
+				return perr
+			}
+			return err
+		}
+		for _, mf := range families {
+			if err := fn(l.lintStreamFamily(mf)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		text, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if text == "" && err == io.EOF {
+			break
+		}
+		line++
+
+		if name, newType, ok := streamFamily(text, family, typ); ok && name != family {
+			if ferr := flush(); ferr != nil {
+				return ferr
+			}
+			family, typ = name, newType
+		} else if ok && newType != "" {
+			typ = newType
+		}
+		if chunk.Len() == 0 {
+			start = line
+		}
+		chunk.WriteString(text)
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return flush()
+}
+
+// LintExpositionStream lints a text format payload, one family at a time, with the default rules.
+func LintExpositionStream(r io.Reader, fn func(*metriclint.LintResult) error) error {
+	return defaultLinter.LintExpositionStream(r, fn)
+}
+
+// lintStreamFamily lints a family of a streamed payload like LintExposition lints each of its families.
+func (l *Linter) lintStreamFamily(mf *dto.MetricFamily) *metriclint.LintResult {
+	result := l.lintFamilies([]*dto.MetricFamily{mf})[0]
+	l.lintSeries(result, mf, false)
+
+	return result
+}
+
+// seriesSuffixes are the suffixes of the sample names of the families of each type, besides their name.
+var seriesSuffixes = map[string][]string{
+	"histogram": {"_bucket", "_sum", "_count"},
+	"summary":   {"_sum", "_count"},
+}
+
+// streamFamily returns the family of a line of a text format payload whose current family has the
+// given type, with the type declared by the line if it's a TYPE line. ok is false for the lines
+// which don't name a family, such as blank lines and comments other than HELP and TYPE.
+func streamFamily(text, current, currentType string) (name, typ string, ok bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	if fields[0] == "#" {
+		if len(fields) < 3 || (fields[1] != "HELP" && fields[1] != "TYPE") {
+			return "", "", false
+		}
+		if fields[1] == "TYPE" && len(fields) > 3 {
+			typ = strings.ToLower(fields[3])
+		}
+		return fields[2], typ, true
+	}
+	if strings.HasPrefix(fields[0], "#") {
+		return "", "", false
+	}
+
+	name = fields[0]
+	if i := strings.IndexByte(name, '{'); i >= 0 {
+		name = name[:i]
+	}
+	for _, suffix := range seriesSuffixes[currentType] {
+		if name == current+suffix {
+			return current, "", true
+		}
+	}
+
+	return name, "", true
+}
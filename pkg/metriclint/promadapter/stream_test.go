@@ -0,0 +1,126 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promadapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestLintExpositionStream(t *testing.T) {
+	payload := strings.Join([]string{
+		"# HELP http_requests Number of requests.",
+		"# TYPE http_requests counter",
+		`http_requests{code="200"} 3`,
+		`http_requests{code="500",method="GET"} 1`,
+		"",
+		"# TYPE queue_length gauge",
+		"queue_length 1",
+		"# HELP request_duration_seconds Distribution of request durations.",
+		"# TYPE request_duration_seconds histogram",
+		`request_duration_seconds_bucket{le="1"} 1`,
+		"request_duration_seconds_sum 0.1",
+		"request_duration_seconds_count 1",
+		"# HELP rpc_latency_seconds Latency of RPCs.",
+		"# TYPE rpc_latency_seconds summary",
+		`rpc_latency_seconds{quantile="0.5"} 0.2`,
+		"rpc_latency_seconds_sum 1",
+		"rpc_latency_seconds_count 5",
+		"# a comment",
+		"up 1",
+		"",
+	}, "\r\n")
+
+	expected, err := LintExposition(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var results []*metriclint.LintResult
+	err = LintExpositionStream(strings.NewReader(payload), func(result *metriclint.LintResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(results) != fmt.Sprint(expected) {
+		t.Errorf("expected: %v, but got: %v", expected, results)
+	}
+}
+
+func TestLintExpositionStreamErrors(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name     string
+		payload  string
+		fn       func(*metriclint.LintResult) error
+		expected string
+	}{
+		{
+			name:     "not contiguous",
+			payload:  "a_total 1\nb_total 1\na_total{code=\"500\"} 1\n",
+			expected: "line 3: lines of family a_total are not contiguous",
+		},
+		{
+			name:     "parse error line",
+			payload:  "a_total 1\n# TYPE b_total counter\nb_total one\n",
+			expected: "text format parsing error in line 3: expected float as value, got \"one\"",
+		},
+		{
+			name:    "callback error",
+			payload: "a_total 1\nb_total 1\n",
+			fn: func(result *metriclint.LintResult) error {
+				if result.MetricName == "a_total" {
+					return errStop
+				}
+				return fmt.Errorf("unexpected family %s", result.MetricName)
+			},
+			expected: errStop.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tc.fn
+			if fn == nil {
+				fn = func(*metriclint.LintResult) error { return nil }
+			}
+			err := LintExpositionStream(strings.NewReader(tc.payload), fn)
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("expected: %s, but got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func BenchmarkLintExpositionStream(b *testing.B) {
+	payload := kubeStateMetrics(1000, 50)
+	for i := 0; i < b.N; i++ {
+		err := LintExpositionStream(strings.NewReader(payload), func(*metriclint.LintResult) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
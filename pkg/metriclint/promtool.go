@@ -0,0 +1,109 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// promtoolErrorPrefix starts the line promtool prints when it fails to parse its input.
+const promtoolErrorPrefix = "error while linting:"
+
+// promtoolProblems maps the problems printed by promtool to the rules reporting the same issues.
+// The messages of these rules are the ones of promtool, so the patterns are derived from them.
+var promtoolProblems = []struct {
+	pattern *regexp.Regexp
+	id      string
+}{
+	{promtoolPattern(LintErrMsgNoHelp), RuleHelpMissing},
+	{promtoolPattern(LintErrMsgNonBaseUnit), RuleNonBaseUnit},
+	{promtoolPattern(LintErrMsgCounterShouldHaveTotalSuffix), RuleCounterTotalSuffix},
+	{promtoolPattern(LintErrMsgNonCounterShouldNotHaveTotalSuffix), RuleNonCounterTotalSuffix},
+	{promtoolPattern(LintErrMsgNonHistogramShouldNotHaveBucketSuffix), RuleNonHistogramBucketSuffix},
+	{promtoolPattern(LintErrMsgNonHistogramSummaryShouldNotHaveCountSuffix), RuleNonHistogramCountSuffix},
+	{promtoolPattern(LintErrMsgMonHistogramSummaryShouldNotHaveSumSuffix), RuleNonHistogramSumSuffix},
+	{promtoolPattern(LintErrMsgNonHistogramShouldNotHaveLeLabel), RuleNonHistogramLeLabel},
+	{promtoolPattern(LintErrMsgNonSummaryShouldNotHaveQuantileLabel), RuleNonSummaryQuantileLabel},
+	{promtoolPattern(LintErrMsgNoMetricType), RuleNameHasType},
+	{promtoolPattern(LintErrMsgNoReservedChars), RuleNameReservedChars},
+	{promtoolPattern(LintErrMsgNameShouldBeSnakeCase), RuleNameCamelCase},
+	{promtoolPattern(LintErrMsgLabelShouldBeSnakeCase), RuleLabelCamelCase},
+	{promtoolPattern(LintErrMsgNameShouldNotHaveAbbr), RuleNameAbbreviatedUnit},
+}
+
+// promtoolPattern turns a message format into a regular expression matching the messages it produces.
+func promtoolPattern(format string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.Replace(regexp.QuoteMeta(format), "%s", ".*", -1) + "$")
+}
+
+// ParsePromtoolOutput converts the output of "promtool check metrics" into lint results, one per
+// metric in the order they first appear, so that they can be reported along with the metriclint results.
+//
+// Problems matching a built-in rule get its ID and default severity, other problems are kept as plain
+// messages. The error line promtool prints when it can't parse the metrics is returned as an error.
+func ParsePromtoolOutput(r io.Reader) ([]*LintResult, error) {
+	var results []*LintResult
+	byMetric := map[string]*LintResult{}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, promtoolErrorPrefix) {
+			return nil, fmt.Errorf("line %d: promtool failed: %s", lineNo, strings.TrimSpace(strings.TrimPrefix(line, promtoolErrorPrefix)))
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a metric name followed by a problem, got %q", lineNo, line)
+		}
+		metric, problem := fields[0], strings.TrimSpace(fields[1])
+
+		result, ok := byMetric[metric]
+		if !ok {
+			result = &LintResult{MetricName: metric}
+			byMetric[metric] = result
+			results = append(results, result)
+		}
+		result.AddIssues(promtoolIssue(problem))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// promtoolIssue converts a promtool problem into an issue.
+func promtoolIssue(problem string) Issue {
+	for _, p := range promtoolProblems {
+		if p.pattern.MatchString(problem) {
+			return ruleIssues(p.id, []string{problem})[0]
+		}
+	}
+
+	return Issue{Message: problem}
+}
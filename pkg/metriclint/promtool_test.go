@@ -0,0 +1,92 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParsePromtoolOutput(t *testing.T) {
+	output := strings.Join([]string{
+		`http_requests counter metrics should have "_total" suffix`,
+		"http_requests no help text",
+		"",
+		`request_duration_milliseconds use base unit "seconds" instead of "milliseconds"`,
+		"queue_depth some problem of a newer promtool",
+	}, "\n")
+
+	results, err := ParsePromtoolOutput(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []*LintResult{
+		{MetricName: "http_requests", Findings: []Issue{
+			{ID: RuleCounterTotalSuffix, Message: LintErrMsgCounterShouldHaveTotalSuffix, Severity: SeverityError},
+			{ID: RuleHelpMissing, Message: LintErrMsgNoHelp, Severity: SeverityError},
+		}},
+		{MetricName: "request_duration_milliseconds", Findings: []Issue{
+			{ID: RuleNonBaseUnit, Message: fmt.Sprintf(LintErrMsgNonBaseUnit, "seconds", "milliseconds"), Severity: SeverityError},
+		}},
+		{MetricName: "queue_depth", Findings: []Issue{
+			{Message: "some problem of a newer promtool"},
+		}},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i := range expected {
+		if results[i].MetricName != expected[i].MetricName || fmt.Sprint(results[i].Findings) != fmt.Sprint(expected[i].Findings) {
+			t.Errorf("expected: %v %v, but got: %v %v", expected[i].MetricName, expected[i].Findings, results[i].MetricName, results[i].Findings)
+		}
+		assertConsistent(t, results[i])
+	}
+}
+
+func TestParsePromtoolOutputError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "promtool failure",
+			output:   "error while linting: text format parsing error in line 1: invalid metric name",
+			expected: "line 1: promtool failed: text format parsing error in line 1: invalid metric name",
+		},
+		{
+			name:     "missing problem",
+			output:   "http_requests_total\n",
+			expected: `line 1: expected a metric name followed by a problem, got "http_requests_total"`,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePromtoolOutput(strings.NewReader(tc.output))
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("expected: %s, but got: %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LintErrMsgRatioType   = `ratio metrics should be gauges, not %s`
+	LintErrMsgRatioUnit   = `ratio metrics are unitless, remove the unit %q`
+	LintErrMsgPercentName = `metric name should not contain %q, use "_ratio" with values from 0 to 1`
+)
+
+// lintRatio checks the metrics whose name ends with "_ratio": they should be gauges, or histograms and summaries
+// of ratios, and carry no unit since the units of a ratio cancel out.
+func lintRatio(name string, metricType MetricType) (issues []string) {
+	if !strings.HasSuffix(strings.TrimSuffix(name, "_total"), "_ratio") {
+		return nil
+	}
+
+	if metricType == MetricTypeCounter {
+		issues = append(issues, fmt.Sprintf(LintErrMsgRatioType, metricType))
+	}
+	if unit, _, ok := DetectUnit(name); ok {
+		issues = append(issues, fmt.Sprintf(LintErrMsgRatioUnit, unit))
+	}
+
+	return issues
+}
+
+// lintPercentName checks that the name doesn't express a ratio as a percentage.
+func lintPercentName(name string) (issues []string) {
+	for _, segment := range strings.Split(name, "_") {
+		if segment == "percent" || segment == "percentage" {
+			issues = append(issues, fmt.Sprintf(LintErrMsgPercentName, segment))
+		}
+	}
+
+	return issues
+}
+
+// suggestRatioName replaces the percent segments of the name by "ratio", e.g. "cpu_usage_ratio" for "cpu_usage_percent".
+func suggestRatioName(name string) string {
+	return replaceSegment(replaceSegment(name, "percentage", "ratio"), "percent", "ratio")
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintRatio(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		metricType     MetricType
+		expectedResult string
+	}{
+		{
+			name:       "gauge",
+			metric:     "lint_cache_hit_ratio",
+			metricType: MetricTypeGauge,
+		},
+		{
+			name:       "histogram of ratios",
+			metric:     "lint_compression_ratio",
+			metricType: MetricTypeHistogram,
+		},
+		{
+			name:       "not a ratio",
+			metric:     "lint_requests_total",
+			metricType: MetricTypeCounter,
+		},
+		{
+			name:           "counter",
+			metric:         "lint_cache_hit_ratio_total",
+			metricType:     MetricTypeCounter,
+			expectedResult: fmt.Sprintf(LintErrMsgRatioType, MetricTypeCounter),
+		},
+		{
+			name:           "unit",
+			metric:         "lint_memory_usage_bytes_ratio",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgRatioUnit, "bytes"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintRatio(tc.metric, tc.metricType), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestLintPercentName(t *testing.T) {
+	tests := []struct {
+		name               string
+		metric             string
+		expectedResult     string
+		expectedSuggestion string
+	}{
+		{
+			name:   "ratio",
+			metric: "lint_cpu_usage_ratio",
+		},
+		{
+			name:               "percent",
+			metric:             "lint_cpu_usage_percent",
+			expectedResult:     fmt.Sprintf(LintErrMsgPercentName, "percent"),
+			expectedSuggestion: "lint_cpu_usage_ratio",
+		},
+		{
+			name:               "percentage",
+			metric:             "lint_disk_percentage_used",
+			expectedResult:     fmt.Sprintf(LintErrMsgPercentName, "percentage"),
+			expectedSuggestion: "lint_disk_ratio_used",
+		},
+		{
+			name:   "percentile",
+			metric: "lint_latency_percentile",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintPercentName(tc.metric), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+
+			for _, issue := range LintSpec(MetricSpec{Name: tc.metric, Help: "Usage.", Type: MetricTypeGauge}).Findings {
+				if issue.ID == RulePercentName && issue.Suggestion != tc.expectedSuggestion {
+					t.Errorf("expected: %s, but got: %s", tc.expectedSuggestion, issue.Suggestion)
+				}
+			}
+		})
+	}
+}
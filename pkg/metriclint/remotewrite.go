@@ -0,0 +1,229 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Protobuf wire types used by the remote write messages.
+const (
+	wireVarint = 0
+	wire64Bit  = 1
+	wireBytes  = 2
+	wire32Bit  = 5
+)
+
+// rwTypes maps the metric types of prompb.MetricMetadata to the OpenMetrics types.
+var rwTypes = map[uint64]string{
+	0: omUnknown,
+	1: omCounter,
+	2: omGauge,
+	3: omHistogram,
+	4: omGaugeHistogram,
+	5: omSummary,
+	6: omInfo,
+	7: omStateSet,
+}
+
+var errRemoteWriteTruncated = errors.New("remote write: truncated message")
+
+// LintRemoteWrite decodes a remote write request and lints the series it contains, with the default
+// rules, see Linter.LintRemoteWrite.
+func LintRemoteWrite(data []byte) ([]*LintResult, error) {
+	return NewLinter().LintRemoteWrite(data)
+}
+
+// LintRemoteWrite decodes a prompb.WriteRequest, as sent by remote write once snappy decompressed,
+// and lints each of its metrics, sorted by name, with the rules of the linter.
+//
+// The series are grouped by the families of the metadata of the request, e.g. "http_request_duration_seconds_bucket"
+// belongs to the histogram "http_request_duration_seconds". Series without metadata, which remote write sends
+// apart from the samples, are linted by their name as untyped metrics.
+func (l *Linter) LintRemoteWrite(data []byte) ([]*LintResult, error) {
+	families, err := parseRemoteWrite(data)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	results := make([]*LintResult, len(families))
+	l.parallel(len(families), func(i int) {
+		results[i] = l.Lint(families[i].spec())
+	})
+
+	return results, nil
+}
+
+// parseRemoteWrite decodes the series of a write request into families, the samples only
+// carrying the labels of the series.
+func parseRemoteWrite(data []byte) ([]*omFamily, error) {
+	var families []*omFamily
+	var series []omSample
+	byName := map[string]*omFamily{}
+	err := decodeMessage(data, func(field uint64, value []byte) error {
+		switch field {
+		case 1:
+			s, err := decodeTimeSeries(value)
+			series = append(series, s)
+			return err
+		case 3:
+			f, err := decodeMetadata(value)
+			if err == nil && byName[f.name] == nil {
+				families = append(families, f)
+				byName[f.name] = f
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range series {
+		f := byName[s.name]
+		if f == nil {
+			f = familyOwning(families, s.name)
+		}
+		if f == nil {
+			f = &omFamily{name: s.name, typ: omUnknown}
+			families = append(families, f)
+			byName[s.name] = f
+		}
+		f.samples = append(f.samples, s)
+	}
+
+	return families, nil
+}
+
+// familyOwning returns the family the sample name belongs to, nil if none does.
+func familyOwning(families []*omFamily, name string) *omFamily {
+	for _, f := range families {
+		if f.typ != omUnknown && f.owns(name) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// decodeTimeSeries decodes the labels of a prompb.TimeSeries, its samples are skipped.
+func decodeTimeSeries(data []byte) (omSample, error) {
+	s := omSample{labels: map[string]string{}}
+	err := decodeMessage(data, func(field uint64, value []byte) error {
+		if field != 1 {
+			return nil
+		}
+		var name, labelValue string
+		err := decodeMessage(value, func(field uint64, value []byte) error {
+			switch field {
+			case 1:
+				name = string(value)
+			case 2:
+				labelValue = string(value)
+			}
+			return nil
+		})
+		if name == "__name__" {
+			s.name = labelValue
+		} else {
+			s.labels[name] = labelValue
+		}
+		return err
+	})
+	if err == nil && s.name == "" {
+		err = errors.New("remote write: series without __name__ label")
+	}
+
+	return s, err
+}
+
+// decodeMetadata decodes a prompb.MetricMetadata into an empty family.
+func decodeMetadata(data []byte) (*omFamily, error) {
+	f := &omFamily{typ: omUnknown}
+	err := decodeMessage(data, func(field uint64, value []byte) error {
+		switch field {
+		case 1:
+			typ, n := binary.Uvarint(value)
+			if n <= 0 || rwTypes[typ] == "" {
+				return fmt.Errorf("remote write: unknown metric type %d", typ)
+			}
+			f.typ = rwTypes[typ]
+		case 2:
+			f.name = string(value)
+		case 4:
+			f.help = string(value)
+		case 5:
+			f.unit = string(value)
+		}
+		return nil
+	})
+
+	return f, err
+}
+
+// decodeMessage calls fn with the number and the value of each field of a protobuf message. Varint
+// values are passed encoded, fixed size values are skipped.
+func decodeMessage(data []byte, fn func(field uint64, value []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errRemoteWriteTruncated
+		}
+		data = data[n:]
+
+		var value []byte
+		switch key & 7 {
+		case wireVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return errRemoteWriteTruncated
+			}
+			value, data = data[:n], data[n:]
+		case wire64Bit, wire32Bit:
+			size := 8
+			if key&7 == wire32Bit {
+				size = 4
+			}
+			if len(data) < size {
+				return errRemoteWriteTruncated
+			}
+			data = data[size:]
+			continue
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errRemoteWriteTruncated
+			}
+			value, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("remote write: unsupported wire type %d", key&7)
+		}
+
+		if err := fn(key>>3, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
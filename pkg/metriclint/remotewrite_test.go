@@ -0,0 +1,102 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// pbField encodes a length delimited protobuf field.
+func pbField(field uint64, value []byte) []byte {
+	b := make([]byte, 2*binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, field<<3|wireBytes)
+	n += binary.PutUvarint(b[n:], uint64(len(value)))
+	return append(b[:n], value...)
+}
+
+// pbSeries encodes a prompb.TimeSeries with the label pairs and a sample.
+func pbSeries(pairs ...string) []byte {
+	var b []byte
+	for i := 0; i < len(pairs); i += 2 {
+		b = append(b, pbField(1, append(pbField(1, []byte(pairs[i])), pbField(2, []byte(pairs[i+1]))...))...)
+	}
+	// Sample{value: 1, timestamp: 1}
+	sample := []byte{wire64Bit | 1<<3, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, wireVarint | 2<<3, 1}
+	return pbField(1, append(b, pbField(2, sample)...))
+}
+
+// pbMetadata encodes a prompb.MetricMetadata.
+func pbMetadata(typ uint64, name, help string) []byte {
+	b := []byte{wireVarint | 1<<3, byte(typ)}
+	b = append(b, pbField(2, []byte(name))...)
+	b = append(b, pbField(4, []byte(help))...)
+	return pbField(3, b)
+}
+
+func TestLintRemoteWrite(t *testing.T) {
+	var request []byte
+	request = append(request, pbSeries("__name__", "http_requests_total", "code", "200")...)
+	request = append(request, pbSeries("__name__", "request_latency_seconds_bucket", "le", "0.1", "methodName", "GET")...)
+	request = append(request, pbSeries("__name__", "request_latency_seconds_count", "methodName", "GET")...)
+	request = append(request, pbSeries("__name__", "queueDepth")...)
+	request = append(request, pbMetadata(1, "http_requests_total", "Total number of requests.")...)
+	request = append(request, pbMetadata(3, "request_latency_seconds", "Latency of requests.")...)
+	request = append(request, pbMetadata(3, "request_latency_seconds", "Latency of requests.")...)
+
+	results, err := LintRemoteWrite(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	issues := map[string][]string{}
+	for _, result := range results {
+		names = append(names, result.MetricName)
+		for _, issue := range result.Findings {
+			issues[result.MetricName] = append(issues[result.MetricName], issue.ID)
+		}
+	}
+	expectedNames := []string{"http_requests_total", "queueDepth", "request_latency_seconds"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("expected: %v, but got: %v", expectedNames, names)
+	}
+	if len(issues["http_requests_total"]) != 0 {
+		t.Errorf("expected no issue, but got: %v", issues["http_requests_total"])
+	}
+	if expected := []string{RuleHelpMissing, RuleNameCamelCase}; !reflect.DeepEqual(issues["queueDepth"], expected) {
+		t.Errorf("expected: %v, but got: %v", expected, issues["queueDepth"])
+	}
+	if !reflect.DeepEqual(issues["request_latency_seconds"], []string{RuleLabelCamelCase}) {
+		t.Errorf("expected: %s, but got: %v", RuleLabelCamelCase, issues["request_latency_seconds"])
+	}
+
+	for name, data := range map[string][]byte{
+		"truncated":        request[:len(request)-3],
+		"unknown type":     pbMetadata(9, "up", "Up."),
+		"series w/o name":  pbSeries("job", "api"),
+		"unsupported wire": {0x0b},
+	} {
+		if _, err := LintRemoteWrite(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReportBuilder collects lint results into a Report. It's safe for concurrent use,
+// so parallel linters can add their results as they go.
+type ReportBuilder struct {
+	mu         sync.Mutex
+	results    []*LintResult
+	tombstoned []Tombstone
+	built      bool
+}
+
+// NewReportBuilder returns an empty ReportBuilder.
+func NewReportBuilder() *ReportBuilder {
+	return &ReportBuilder{}
+}
+
+// Add appends results to the report. Nil results are skipped.
+// It panics if the report has already been built.
+func (b *ReportBuilder) Add(results ...*LintResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.built {
+		panic("metriclint: add to a report which has already been built")
+	}
+	for _, result := range results {
+		if result != nil {
+			b.results = append(b.results, result)
+		}
+	}
+}
+
+// AddTombstoned records metrics scheduled for removal which are still present, apart from the results.
+// It panics if the report has already been built.
+func (b *ReportBuilder) AddTombstoned(tombstones ...Tombstone) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.built {
+		panic("metriclint: add to a report which has already been built")
+	}
+	b.tombstoned = append(b.tombstoned, tombstones...)
+}
+
+// Build finalizes the report, stamped with the current time. Results are ordered by metric name,
+// so the report doesn't depend on the order they were added in, and copied, so later changes to
+// the added results don't leak into the report. Build may only be called once.
+func (b *ReportBuilder) Build() *Report {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.built {
+		panic("metriclint: report has already been built")
+	}
+	b.built = true
+
+	results := make([]*LintResult, 0, len(b.results))
+	for _, r := range b.results {
+		results = append(results, &LintResult{
+			MetricName: r.MetricName,
+			Issues:     append([]string(nil), r.Issues...),
+			Findings:   append([]Issue(nil), r.Findings...),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].MetricName < results[j].MetricName })
+	b.results = nil
+
+	tombstoned := append([]Tombstone(nil), b.tombstoned...)
+	sortTombstones(tombstoned)
+	b.tombstoned = nil
+
+	return &Report{Timestamp: time.Now(), Results: results, Tombstoned: tombstoned}
+}
@@ -0,0 +1,72 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestReportBuilder(t *testing.T) {
+	b := NewReportBuilder()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.Add(LintSpec(MetricSpec{Name: fmt.Sprintf("lint_test_%02d_total", i), Type: MetricTypeCounter}), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	report := b.Build()
+	if len(report.Results) != 20 || report.Timestamp.IsZero() {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for i, result := range report.Results {
+		if expected := fmt.Sprintf("lint_test_%02d_total", i); result.MetricName != expected {
+			t.Errorf("expected: %s, but got: %s", expected, result.MetricName)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic when adding to a built report")
+		}
+	}()
+	b.Add(&LintResult{MetricName: "lint_late_total"})
+}
+
+func TestReportBuilderCopiesResults(t *testing.T) {
+	b := NewReportBuilder()
+	result := &LintResult{MetricName: "lint_test_total"}
+	result.AddMessages(LintErrMsgNoHelp)
+	b.Add(result)
+
+	report := b.Build()
+	result.AddMessages(LintErrMsgNameShouldNotHaveAbbr)
+	result.Findings[0].Message = "changed"
+
+	if len(report.Results[0].Findings) != 1 || report.Results[0].Findings[0].Message != LintErrMsgNoHelp {
+		t.Errorf("expected report to be unaffected, but got: %v", report.Results[0].Findings)
+	}
+}
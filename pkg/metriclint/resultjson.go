@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+)
+
+// resultJSON is the JSON schema of a LintResult.
+type resultJSON struct {
+	Metric string      `json:"metric"`
+	Issues []issueJSON `json:"issues"`
+}
+
+// issueJSON is the JSON schema of an Issue.
+type issueJSON struct {
+	Rule       string          `json:"rule,omitempty"`
+	Severity   Severity        `json:"severity,omitempty"`
+	Message    string          `json:"message"`
+	Suggestion string          `json:"suggestion,omitempty"`
+	Location   *SourceLocation `json:"location,omitempty"`
+}
+
+// MarshalJSON encodes the result with a stable schema, e.g.
+// {"metric":"http_requests","issues":[{"rule":"counter-total-suffix","severity":"error","message":"..."}]}.
+// Issues is always an array, empty for a result without issue.
+func (lr LintResult) MarshalJSON() ([]byte, error) {
+	out := resultJSON{Metric: lr.MetricName, Issues: []issueJSON{}}
+	for _, issue := range lr.findings() {
+		out.Issues = append(out.Issues, issueJSON{
+			Rule:       issue.ID,
+			Severity:   issue.Severity,
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+			Location:   issue.Location,
+		})
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON, or by earlier versions which encoded the
+// fields as is, so that stored reports stay readable.
+func (lr *LintResult) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if _, ok := fields["metric"]; !ok {
+		// The legacy schema, decoded without the methods of LintResult.
+		type legacyResult LintResult
+		return json.Unmarshal(data, (*legacyResult)(lr))
+	}
+
+	var in resultJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*lr = LintResult{MetricName: in.Metric}
+	for _, issue := range in.Issues {
+		lr.AddIssues(Issue{ID: issue.Rule, Message: issue.Message, Severity: issue.Severity, Suggestion: issue.Suggestion, Location: issue.Location})
+	}
+
+	return nil
+}
+
+// Results is a list of lint results, encoded in JSON as {"issueCount":1,"results":[...]}.
+type Results []*LintResult
+
+// IssueCount returns the number of issues of all results.
+func (rs Results) IssueCount() int {
+	count := 0
+	for _, r := range rs {
+		count += len(r.findings())
+	}
+
+	return count
+}
+
+// MarshalJSON encodes the results with their issue count.
+func (rs Results) MarshalJSON() ([]byte, error) {
+	results := []*LintResult(rs)
+	if results == nil {
+		results = []*LintResult{}
+	}
+
+	return json.Marshal(struct {
+		IssueCount int           `json:"issueCount"`
+		Results    []*LintResult `json:"results"`
+	}{rs.IssueCount(), results})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLintResultJSON(t *testing.T) {
+	result := LintSpec(MetricSpec{Name: "lint_requests", Help: "this is help message", Type: MetricTypeCounter})
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"metric":"lint_requests","issues":[{"rule":"counter-total-suffix","severity":"error","message":"counter metrics should have \"_total\" suffix","suggestion":"lint_requests_total"}]}`
+	if string(data) != expected {
+		t.Errorf("expected: %s, but got: %s", expected, data)
+	}
+
+	decoded := &LintResult{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, result) {
+		t.Errorf("expected: %+v, but got: %+v", result, decoded)
+	}
+}
+
+func TestLintResultJSONLegacy(t *testing.T) {
+	var tests = []struct {
+		name     string
+		data     string
+		expected *LintResult
+	}{
+		{
+			name:     "messages only",
+			data:     `{"MetricName":"lint_requests","Issues":["no help text"]}`,
+			expected: &LintResult{MetricName: "lint_requests", Issues: []string{LintErrMsgNoHelp}},
+		},
+		{
+			name: "with findings",
+			data: `{"MetricName":"lint_requests","Issues":["no help text"],"Findings":[{"ID":"help-missing","Message":"no help text","Severity":"error"}]}`,
+			expected: &LintResult{MetricName: "lint_requests", Issues: []string{LintErrMsgNoHelp},
+				Findings: []Issue{{ID: RuleHelpMissing, Message: LintErrMsgNoHelp, Severity: SeverityError}}},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			decoded := &LintResult{}
+			if err := json.Unmarshal([]byte(tc.data), decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tc.expected) {
+				t.Errorf("expected: %+v, but got: %+v", tc.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestResultsJSON(t *testing.T) {
+	var tests = []struct {
+		name     string
+		results  Results
+		expected string
+	}{
+		{
+			name:     "empty",
+			expected: `{"issueCount":0,"results":[]}`,
+		},
+		{
+			name:     "clean and failing results",
+			results:  Results{{MetricName: "lint_a_total"}, {MetricName: "lint_b", Issues: []string{LintErrMsgNoHelp}}},
+			expected: `{"issueCount":1,"results":[{"metric":"lint_a_total","issues":[]},{"metric":"lint_b","issues":[{"message":"no help text"}]}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.results)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("expected: %s, but got: %s", tc.expected, data)
+			}
+		})
+	}
+}
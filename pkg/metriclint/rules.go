@@ -0,0 +1,879 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule categories, matching the sections of docs/MetricsLint.md.
+const (
+	RuleCategoryCommon          = "common"
+	RuleCategoryCounter         = "counter"
+	RuleCategoryHistogram       = "histogram"
+	RuleCategorySummary         = "summary"
+	RuleCategoryNativeHistogram = "native-histogram"
+	RuleCategoryBatch           = "batch"
+	RuleCategoryOptIn           = "opt-in"
+	RuleCategoryRuntime         = "runtime"
+	RuleCategoryOpenMetrics     = "openmetrics"
+	RuleCategoryExposition      = "exposition"
+	RuleCategoryDeclarative     = "declarative"
+)
+
+// Stable IDs of the built-in rules.
+const (
+	RuleHelpMissing                     = "help-missing"
+	RuleNonBaseUnit                     = "non-base-unit"
+	RuleNameHasType                     = "name-has-type"
+	RuleNameReservedChars               = "name-reserved-chars"
+	RuleNameCamelCase                   = "name-camel-case"
+	RuleLabelCamelCase                  = "label-camel-case"
+	RuleLabelShadowsConstLabel          = "label-shadows-const-label"
+	RuleLabelDuplicate                  = "label-duplicate"
+	RuleLabelReservedPrefix             = "label-reserved-prefix"
+	RuleLabelRepeatsName                = "label-repeats-name"
+	RuleNameAbbreviatedUnit             = "name-abbreviated-unit"
+	RuleNameSuffixTypo                  = "name-suffix-typo"
+	RuleDeclaredUnit                    = "declared-unit"
+	RuleInvalidName                     = "invalid-name"
+	RuleHighCardinalityLabel            = "high-cardinality-label"
+	RuleUntypedMetric                   = "untyped-metric"
+	RuleRatio                           = "ratio"
+	RulePercentName                     = "name-percent"
+	RuleInfoMetric                      = "info-metric"
+	RuleInfoName                        = "info-name"
+	RuleTimestampName                   = "timestamp-name"
+	RuleNameEmpty                       = "name-empty"
+	RuleNamespaceEqualsSubsystem        = "namespace-equals-subsystem"
+	RuleNameDoubleUnderscore            = "name-double-underscore"
+	RuleCounterTotalSuffix              = "counter-total-suffix"
+	RuleNonCounterTotalSuffix           = "non-counter-total-suffix"
+	RuleCounterFuncDecreasing           = "counter-func-decreasing"
+	RuleNonHistogramBucketSuffix        = "non-histogram-bucket-suffix"
+	RuleNonHistogramCountSuffix         = "non-histogram-count-suffix"
+	RuleNonHistogramSumSuffix           = "non-histogram-sum-suffix"
+	RuleNonHistogramLeLabel             = "non-histogram-le-label"
+	RuleNonSummaryQuantileLabel         = "non-summary-quantile-label"
+	RuleHistogramLeLabel                = "histogram-le-label"
+	RuleSummaryQuantileLabel            = "summary-quantile-label"
+	RuleVectorLabels                    = "vector-labels"
+	RuleHistogramBucketsOrder           = "histogram-buckets-order"
+	RuleHistogramBuckets                = "histogram-buckets"
+	RuleHistogramBucketScale            = "histogram-bucket-scale"
+	RuleSummaryQuantiles                = "summary-quantiles"
+	RuleSummaryMaxAge                   = "summary-max-age"
+	RuleNativeHistogramBucketFactor     = "native-histogram-bucket-factor"
+	RuleNativeHistogramZeroThreshold    = "native-histogram-zero-threshold"
+	RuleNativeHistogramMaxZeroThreshold = "native-histogram-max-zero-threshold"
+	RuleNativeHistogramMinResetDuration = "native-histogram-min-reset-duration"
+	RuleSynonymNames                    = "synonym-names"
+	RuleErrorRatio                      = "error-ratio"
+	RuleAcronymMixedStyle               = "acronym-mixed-style"
+	RuleExporterPrefix                  = "exporter-prefix"
+	RuleDuplicateMetric                 = "duplicate-metric"
+	RuleLabelSchema                     = "label-schema"
+	RuleUnitSuffix                      = "unit-suffix"
+	RuleAcronymLowercase                = "acronym-lowercase"
+	RuleBooleanLabel                    = "boolean-label"
+	RuleNumericFragment                 = "numeric-fragment"
+	RuleHelpPrefix                      = "help-prefix"
+	RuleLabelDocumented                 = "label-documented"
+	RuleNamespacePolicy                 = "namespace-policy"
+	RuleSummaryObjectives               = "summary-objectives"
+	RuleConstantZero                    = "constant-zero"
+	RuleUnboundedLabel                  = "unbounded-label"
+	RuleRegistrationDrift               = "registration-drift"
+	RuleCounterDecreased                = "counter-decreased"
+	RulePossiblyUnusedMetric            = "possibly-unused-metric"
+	RuleOpenMetricsCounterTotal         = "openmetrics-counter-total"
+	RuleOpenMetricsCreated              = "openmetrics-created"
+	RuleOpenMetricsInfo                 = "openmetrics-info"
+	RuleOpenMetricsUnit                 = "openmetrics-unit"
+	RuleSeriesIncomplete                = "series-incomplete"
+	RuleHistogramBucketsCumulative      = "histogram-buckets-cumulative"
+)
+
+// RuleInfo describes a lint rule, for tools presenting the available rules.
+type RuleInfo struct {
+	// Stable identifier of the rule.
+	ID string `json:"id"`
+
+	// One of the RuleCategory constants.
+	Category string `json:"category"`
+
+	// Severity of the issues reported by the rule, unless overridden.
+	Severity Severity `json:"severity"`
+
+	// Short human readable description of what the rule checks.
+	Description string `json:"description"`
+
+	// Why the rule exists.
+	Rationale string `json:"rationale,omitempty"`
+
+	// Examples of a declaration violating the rule and of a compliant one.
+	Bad  string `json:"bad,omitempty"`
+	Good string `json:"good,omitempty"`
+
+	// How to fix a violation.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+var rules = []RuleInfo{
+	{
+		ID:          RuleHelpMissing,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric should contain help text",
+		Rationale:   "Help text is shown by Prometheus and Grafana, metrics without it can't be understood outside of their code.",
+		Bad:         `prometheus.CounterOpts{Name: "http_requests_total"}`,
+		Good:        `prometheus.CounterOpts{Name: "http_requests_total", Help: "Total number of HTTP requests."}`,
+		Remediation: "Describe what the metric measures in the Help field.",
+	},
+	{
+		ID:          RuleNonBaseUnit,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric unit should be a base unit",
+		Rationale:   "Mixing units such as milliseconds and seconds across metrics makes queries and dashboards error prone.",
+		Bad:         "http_request_duration_milliseconds",
+		Good:        "http_request_duration_seconds",
+		Remediation: "Use the base unit in the name and convert the observed values.",
+	},
+	{
+		ID:          RuleNameHasType,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric name should not include the metric type",
+		Rationale:   "The type is already part of the metadata, repeating it in the name is noise.",
+		Bad:         "http_requests_counter",
+		Good:        "http_requests_total",
+		Remediation: "Remove the type from the name.",
+	},
+	{
+		ID:          RuleNameReservedChars,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric name should not contain ':'",
+		Rationale:   "Colons are reserved for recording rules.",
+		Bad:         "http:requests_total",
+		Good:        "http_requests_total",
+		Remediation: "Replace ':' with '_'.",
+	},
+	{
+		ID:          RuleNameCamelCase,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric name should be written in snake_case",
+		Rationale:   "Prometheus metric names are snake_case by convention.",
+		Bad:         "httpRequestsTotal",
+		Good:        "http_requests_total",
+		Remediation: "Rename the metric in snake_case.",
+	},
+	{
+		ID:          RuleLabelCamelCase,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "label name should be written in snake_case",
+		Rationale:   "Prometheus label names are snake_case by convention.",
+		Bad:         `[]string{"statusCode"}`,
+		Good:        `[]string{"status_code"}`,
+		Remediation: "Rename the label in snake_case.",
+	},
+	{
+		ID:          RuleLabelShadowsConstLabel,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "variable label should not shadow a const label",
+		Rationale:   "A variable label with the name of a const label makes the registration fail or the series ambiguous.",
+		Bad:         `ConstLabels: prometheus.Labels{"zone": "a"} with []string{"zone"}`,
+		Good:        `ConstLabels: prometheus.Labels{"zone": "a"} with []string{"code"}`,
+		Remediation: "Drop the const label or rename the variable label.",
+	},
+	{
+		ID:          RuleLabelDuplicate,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "variable label names should not be repeated",
+		Rationale:   "client_golang fails the registration with \"duplicate label names\" without naming the label.",
+		Bad:         `[]string{"code", "method", "code"}`,
+		Good:        `[]string{"code", "method"}`,
+		Remediation: "Remove the repeated label name.",
+	},
+	{
+		ID:          RuleLabelReservedPrefix,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "label names should not start with \"__\"",
+		Rationale:   "Labels starting with \"__\" are reserved for Prometheus internal use and dropped or overwritten by relabeling.",
+		Bad:         `[]string{"__code"}`,
+		Good:        `[]string{"code"}`,
+		Remediation: "Remove the leading underscores.",
+	},
+	{
+		ID:          RuleLabelRepeatsName,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "label name should not start with segments of the metric name",
+		Rationale:   "The metric name already gives the context, repeating it makes queries longer.",
+		Bad:         `http_requests_total with []string{"http_method"}`,
+		Good:        `http_requests_total with []string{"method"}`,
+		Remediation: "Remove the repeated segments from the label name.",
+	},
+	{
+		ID:          RuleNameAbbreviatedUnit,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric name should not contain abbreviated units",
+		Rationale:   "Abbreviations such as ms or sec are ambiguous and inconsistent across metrics.",
+		Bad:         "request_duration_ms",
+		Good:        "request_duration_seconds",
+		Remediation: "Spell out the base unit.",
+	},
+	{
+		ID:          RuleNameSuffixTypo,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "metric name should not contain typos of units and suffixes",
+		Rationale:   "Misspelled units and suffixes break queries relying on the conventions.",
+		Bad:         "request_duration_secconds",
+		Good:        "request_duration_seconds",
+		Remediation: "Fix the spelling.",
+	},
+	{
+		ID:          RuleDeclaredUnit,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "declared unit should be a base unit and the suffix of the metric name",
+		Rationale:   "The unit metadata and the name must agree, OpenMetrics rejects families whose name doesn't end with their unit.",
+		Bad:         `Name: "request_duration", Unit: "milliseconds"`,
+		Good:        `Name: "request_duration_seconds", Unit: "seconds"`,
+		Remediation: "Declare the base unit and add it as suffix of the name.",
+	},
+	{
+		ID:          RuleInvalidName,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric and label names should be valid Prometheus names",
+		Rationale:   "Invalid names fail at registration or are rejected by the Prometheus server.",
+		Bad:         "http-requests-total, 5xx_errors_total",
+		Good:        "http_requests_total, server_errors_total",
+		Remediation: "Use only letters, digits, underscores, and colons for metric names, and don't start with a digit.",
+	},
+	{
+		ID:          RuleHighCardinalityLabel,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "label names such as id, path, url, user or email should not be used",
+		Rationale:   "Such labels usually take unbounded values, every value is a new series.",
+		Bad:         `[]string{"path"}`,
+		Good:        `[]string{"handler"}`,
+		Remediation: "Use a bounded label such as the route template, or log the values instead.",
+	},
+	{
+		ID:          RuleUntypedMetric,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "metrics should be typed, untyped metrics are only linted with the common rules",
+		Rationale:   "Queries and alerts can't tell whether an untyped metric may decrease, and the type-specific rules can't check it.",
+		Bad:         "prometheus.NewUntyped(prometheus.UntypedOpts{Name: \"http_requests_total\"})",
+		Good:        "prometheus.NewCounter(prometheus.CounterOpts{Name: \"http_requests_total\"})",
+		Remediation: "Use a counter for values which only go up, a gauge otherwise.",
+	},
+	{
+		ID:          RuleRatio,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "metrics with the \"_ratio\" suffix should not be counters or carry a unit",
+		Rationale:   "A ratio can go down, so it's no counter, and the units of its terms cancel out.",
+		Bad:         "memory_usage_bytes_ratio, cache_hit_ratio_total counter",
+		Good:        "memory_usage_ratio gauge",
+		Remediation: "Make the metric a gauge and remove the unit, or count the terms of the ratio in counters.",
+	},
+	{
+		ID:          RulePercentName,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "metric name should not contain \"percent\" or \"percentage\"",
+		Rationale:   "Prometheus conventions express ratios from 0 to 1, mixing percentages makes queries off by a factor of 100.",
+		Bad:         "cpu_usage_percent",
+		Good:        "cpu_usage_ratio",
+		Remediation: "Report the value divided by 100 and use the \"_ratio\" suffix.",
+	},
+	{
+		ID:          RuleInfoMetric,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "metrics with the \"_info\" suffix should be gauges without unit",
+		Rationale:   "Info metrics carry their information in labels with the constant value 1, they are joined into other series by queries.",
+		Bad:         "build_info counter, build_info_seconds",
+		Good:        "build_info gauge set to 1",
+		Remediation: "Make the metric a gauge set to 1 and remove the unit.",
+	},
+	{
+		ID:          RuleInfoName,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "gauges with only identity labels, such as version or revision, should have the \"_info\" suffix",
+		Rationale:   "The suffix tells users and tools that the labels are the information and the value is always 1.",
+		Bad:         "build gauge with labels version and revision",
+		Good:        "build_info gauge with labels version and revision",
+		Remediation: "Add the \"_info\" suffix and set the gauge to 1.",
+	},
+	{
+		ID:          RuleTimestampName,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityWarning,
+		Description: "timestamps should be in seconds since the epoch, with the \"_timestamp_seconds\" suffix",
+		Rationale:   "time() and the other PromQL functions work in seconds, timestamps in other units or without unit are compared wrongly.",
+		Bad:         "job_last_success_unix, created_timestamp_ms",
+		Good:        "job_last_success_timestamp_seconds, process_start_time_seconds",
+		Remediation: "Report the Unix time in seconds and use the \"_timestamp_seconds\" suffix.",
+	},
+	{
+		ID:          RuleNameEmpty,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "metric name should not be empty",
+		Rationale:   "A metric without name can't be registered.",
+		Bad:         `prometheus.GaugeOpts{Help: "Queue length."}`,
+		Good:        `prometheus.GaugeOpts{Name: "queue_length", Help: "Queue length."}`,
+		Remediation: "Set the Name field.",
+	},
+	{
+		ID:          RuleNamespaceEqualsSubsystem,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "namespace and subsystem should not be the same",
+		Rationale:   "The repeated segment makes the name longer without adding information.",
+		Bad:         `Namespace: "kubelet", Subsystem: "kubelet"`,
+		Good:        `Namespace: "kubelet", Subsystem: "runtime"`,
+		Remediation: "Drop the subsystem or pick a more specific one.",
+	},
+	{
+		ID:          RuleNameDoubleUnderscore,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "name parts should not produce \"__\" when joined",
+		Rationale:   "Names containing \"__\" are reserved for Prometheus internal use.",
+		Bad:         `Namespace: "kubelet_", Name: "pods"`,
+		Good:        `Namespace: "kubelet", Name: "pods"`,
+		Remediation: "Remove the leading and trailing '_' from the name parts.",
+	},
+	{
+		ID:          RuleCounterTotalSuffix,
+		Category:    RuleCategoryCounter,
+		Severity:    SeverityError,
+		Description: "counter should have \"_total\" suffix",
+		Rationale:   "The suffix tells counters apart from gauges in queries.",
+		Bad:         "http_requests",
+		Good:        "http_requests_total",
+		Remediation: "Append \"_total\" to the counter name.",
+	},
+	{
+		ID:          RuleNonCounterTotalSuffix,
+		Category:    RuleCategoryCounter,
+		Severity:    SeverityError,
+		Description: "non-counter should not have \"_total\" suffix",
+		Rationale:   "The \"_total\" suffix promises a counter, rate() on a gauge gives wrong results.",
+		Bad:         "queue_length_total gauge",
+		Good:        "queue_length gauge",
+		Remediation: "Remove the suffix or make the metric a counter.",
+	},
+	{
+		ID:          RuleCounterFuncDecreasing,
+		Category:    RuleCategoryCounter,
+		Severity:    SeverityWarning,
+		Description: "a CounterFunc should not report a value which can decrease",
+		Rationale:   "rate() and increase() treat every drop of a counter as a reset, a decreasing value gives made up spikes.",
+		Bad:         "prometheus.NewCounterFunc(prometheus.CounterOpts{Name: \"queue_length_total\"}, queue.Len)",
+		Good:        "prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: \"queue_length\"}, queue.Len)",
+		Remediation: "Use a GaugeFunc for values which go down, or count the events in a Counter.",
+	},
+	{
+		ID:          RuleNonHistogramBucketSuffix,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "non-histogram should not have \"_bucket\" suffix",
+		Rationale:   "The suffix is generated for histogram buckets and collides with them.",
+		Bad:         "queue_bucket gauge",
+		Good:        "queue_buckets gauge",
+		Remediation: "Rename the metric.",
+	},
+	{
+		ID:          RuleNonHistogramCountSuffix,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "non-histogram and non-summary should not have \"_count\" suffix",
+		Rationale:   "The suffix is generated for histograms and summaries and collides with them.",
+		Bad:         "pods_count gauge",
+		Good:        "pods gauge",
+		Remediation: "Rename the metric.",
+	},
+	{
+		ID:          RuleNonHistogramSumSuffix,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "non-histogram and non-summary should not have \"_sum\" suffix",
+		Rationale:   "The suffix is generated for histograms and summaries and collides with them.",
+		Bad:         "bytes_sum gauge",
+		Good:        "bytes gauge",
+		Remediation: "Rename the metric.",
+	},
+	{
+		ID:          RuleNonHistogramLeLabel,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "non-histogram should not have \"le\" label",
+		Rationale:   "The \"le\" label holds histogram bucket bounds, other uses confuse histogram_quantile().",
+		Bad:         `[]string{"le"} on a gauge`,
+		Good:        `[]string{"limit"} on a gauge`,
+		Remediation: "Rename the label.",
+	},
+	{
+		ID:          RuleNonSummaryQuantileLabel,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "non-summary should not have \"quantile\" label",
+		Rationale:   "The \"quantile\" label holds summary quantiles.",
+		Bad:         `[]string{"quantile"} on a gauge`,
+		Good:        `[]string{"percentile_bucket"} on a gauge`,
+		Remediation: "Rename the label.",
+	},
+	{
+		ID:          RuleHistogramLeLabel,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "histogram vectors should not have an \"le\" variable label",
+		Rationale:   "The \"le\" label of the bucket series is generated, client_golang panics on a declared one.",
+		Bad:         `[]string{"le"} on a histogram vector`,
+		Good:        `[]string{"limit"} on a histogram vector`,
+		Remediation: "Rename the label.",
+	},
+	{
+		ID:          RuleSummaryQuantileLabel,
+		Category:    RuleCategorySummary,
+		Severity:    SeverityError,
+		Description: "summary vectors should not have a \"quantile\" variable label",
+		Rationale:   "The \"quantile\" label of the quantile series is generated, client_golang panics on a declared one.",
+		Bad:         `[]string{"quantile"} on a summary vector`,
+		Good:        `[]string{"percentile_bucket"} on a summary vector`,
+		Remediation: "Rename the label.",
+	},
+	{
+		ID:          RuleVectorLabels,
+		Category:    RuleCategoryCommon,
+		Severity:    SeverityError,
+		Description: "vector label names should be set, not empty and within the budget",
+		Rationale:   "A vector without labels is a plain metric, empty label names fail at registration and many labels multiply the series.",
+		Bad:         `prometheus.NewCounterVec(opts, []string{"code", ""})`,
+		Good:        `prometheus.NewCounterVec(opts, []string{"code"})`,
+		Remediation: "Pass the label names of the vector, each non empty, and split metrics with too many labels.",
+	},
+	{
+		ID:          RuleHistogramBucketsOrder,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityError,
+		Description: "histogram buckets should be strictly increasing",
+		Rationale:   "client_golang panics when registering a histogram with unsorted or duplicate buckets.",
+		Bad:         "Buckets: []float64{1, 0.5, 0.5}",
+		Good:        "Buckets: []float64{0.5, 1}",
+		Remediation: "Sort the buckets and remove the duplicates.",
+	},
+	{
+		ID:          RuleHistogramBuckets,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityWarning,
+		Description: "histogram buckets should not be empty, a single bucket, include +Inf or be too many",
+		Rationale:   "Empty buckets silently fall back to the defaults, a single bucket tells little, +Inf is always added and every bucket is a series.",
+		Bad:         "Buckets: prometheus.LinearBuckets(0, 1, 1000)",
+		Good:        "Buckets: prometheus.ExponentialBuckets(0.001, 2, 15)",
+		Remediation: "Pick a handful of buckets around the expected values, without +Inf.",
+	},
+	{
+		ID:          RuleHistogramBucketScale,
+		Category:    RuleCategoryHistogram,
+		Severity:    SeverityWarning,
+		Description: "histogram buckets should fit the unit of the metric name",
+		Rationale:   "Buckets in another unit than the name, e.g. milliseconds observed into a histogram in seconds, put every observation into the wrong bucket.",
+		Bad:         "request_duration_seconds with Buckets: []float64{100, 500, 1000}",
+		Good:        "request_duration_seconds with Buckets: []float64{0.1, 0.5, 1}",
+		Remediation: "Observe values in the unit of the name and scale the buckets accordingly.",
+	},
+	{
+		ID:          RuleSummaryQuantiles,
+		Category:    RuleCategorySummary,
+		Severity:    SeverityError,
+		Description: "summary quantiles should be between 0 and 1, with errors smaller than the distance between them",
+		Rationale:   "Quantiles outside (0, 1) are meaningless and overlapping errors make quantiles indistinguishable.",
+		Bad:         "Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.5}",
+		Good:        "Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01}",
+		Remediation: "Use quantiles in (0, 1) and tighten the errors.",
+	},
+	{
+		ID:          RuleSummaryMaxAge,
+		Category:    RuleCategorySummary,
+		Severity:    SeverityError,
+		Description: "summary max age should not be negative",
+		Rationale:   "client_golang panics on a negative max age, zero selects the default.",
+		Bad:         "MaxAge: -time.Minute",
+		Good:        "MaxAge: 10 * time.Minute",
+		Remediation: "Use a positive max age.",
+	},
+	{
+		ID:          RuleNativeHistogramBucketFactor,
+		Category:    RuleCategoryNativeHistogram,
+		Severity:    SeverityError,
+		Description: "bucket factor should be greater than 1",
+		Rationale:   "Native histograms are disabled with a factor of 1 or less.",
+		Bad:         "NativeHistogramBucketFactor: 1",
+		Good:        "NativeHistogramBucketFactor: 1.1",
+		Remediation: "Set a factor greater than 1.",
+	},
+	{
+		ID:          RuleNativeHistogramZeroThreshold,
+		Category:    RuleCategoryNativeHistogram,
+		Severity:    SeverityError,
+		Description: "zero threshold should not be negative",
+		Rationale:   "A negative threshold is meaningless, use NativeHistogramZeroThresholdZero for a zero width bucket.",
+		Bad:         "NativeHistogramZeroThreshold: -0.5",
+		Good:        "NativeHistogramZeroThreshold: 1e-128",
+		Remediation: "Use a positive threshold.",
+	},
+	{
+		ID:          RuleNativeHistogramMaxZeroThreshold,
+		Category:    RuleCategoryNativeHistogram,
+		Severity:    SeverityError,
+		Description: "max zero threshold should not be lower than zero threshold",
+		Rationale:   "The zero bucket could never widen up to the maximum.",
+		Bad:         "ZeroThreshold: 0.1, MaxZeroThreshold: 0.01",
+		Good:        "ZeroThreshold: 0.01, MaxZeroThreshold: 0.1",
+		Remediation: "Raise the maximum or lower the threshold.",
+	},
+	{
+		ID:          RuleNativeHistogramMinResetDuration,
+		Category:    RuleCategoryNativeHistogram,
+		Severity:    SeverityError,
+		Description: "min reset duration should not be shorter than the scrape interval",
+		Rationale:   "Resetting more often than scraped loses observations.",
+		Bad:         "NativeHistogramMinResetDuration: 5 * time.Second",
+		Good:        "NativeHistogramMinResetDuration: time.Hour",
+		Remediation: "Use a duration of at least the scrape interval.",
+	},
+	{
+		ID:          RuleSynonymNames,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityWarning,
+		Description: "metric names should not differ only by plural forms or token order",
+		Rationale:   "Nearly identical names usually mean parallel instrumentation of the same thing.",
+		Bad:         "http_request_duration_seconds and http_requests_duration_seconds",
+		Good:        "http_request_duration_seconds",
+		Remediation: "Consolidate the metrics.",
+	},
+	{
+		ID:          RuleErrorRatio,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityWarning,
+		Description: "error counter should have a counter of all attempts with the same labels",
+		Rationale:   "Error ratios need the total number of attempts with matching labels.",
+		Bad:         "foo_errors_total alone",
+		Good:        "foo_errors_total and foo_total with the same labels",
+		Remediation: "Add the total counter or align the labels.",
+	},
+	{
+		ID:          RuleAcronymMixedStyle,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityWarning,
+		Description: "acronym should be written the same way across metrics",
+		Rationale:   "Mixed spellings split what should be one family of names.",
+		Bad:         "grpc_requests_total and g_rpc_errors_total",
+		Good:        "grpc_requests_total and grpc_errors_total",
+		Remediation: "Use one spelling.",
+	},
+	{
+		ID:          RuleExporterPrefix,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityWarning,
+		Description: "metric name should not share the prefix of a widely deployed exporter",
+		Rationale:   "Dashboards and alerts written for the exporter would also match the metric and mix both sources.",
+		Bad:         "node_queue_length in an application",
+		Good:        "myapp_queue_length",
+		Remediation: "Use the namespace of the application, or set ExporterPrefixRule.Identity if the binary is that exporter.",
+	},
+	{
+		ID:          RuleDuplicateMetric,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityError,
+		Description: "metric should not be declared more than once with a different help, type or label names",
+		Rationale:   "A registry rejects the second declaration, and the series of a scrape can't tell which declaration they belong to.",
+		Bad:         `"queue_length" gauge with labels "queue", and "queue_length" gauge with labels "queue", "shard"`,
+		Good:        `"queue_length" gauge with labels "queue", "shard" in both places`,
+		Remediation: "Declare the metric once and share it, or give the declarations distinct names.",
+	},
+	{
+		ID:          RuleLabelSchema,
+		Category:    RuleCategoryBatch,
+		Severity:    SeverityWarning,
+		Description: "metrics of a namespace should use the same label name for the same concept",
+		Rationale:   "Series labeled \"code\" in one metric and \"status_code\" in another can't be joined or aggregated together without relabeling.",
+		Bad:         `apiserver_requests_total{code}, apiserver_errors_total{status_code}`,
+		Good:        `apiserver_requests_total{code}, apiserver_errors_total{code}`,
+		Remediation: "Rename the label after the name the other metrics of the namespace use, or configure LabelSchemaRule.Synonyms.",
+	},
+	{
+		ID:          RuleUnitSuffix,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "metric name should end with a unit or an allowed noun",
+		Rationale:   "The unit suffix tells how to read the value.",
+		Bad:         "request_duration",
+		Good:        "request_duration_seconds",
+		Remediation: "Append the base unit.",
+	},
+	{
+		ID:          RuleAcronymLowercase,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "acronym should be written as lowercase segment",
+		Rationale:   "Names are lowercase, acronyms included.",
+		Bad:         "HTTP_requests_total",
+		Good:        "http_requests_total",
+		Remediation: "Lowercase the acronym.",
+	},
+	{
+		ID:          RuleBooleanLabel,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "label values should not only be boolean",
+		Rationale:   "Labels such as success=\"true\" read badly in queries.",
+		Bad:         `requests_total{success="true"}`,
+		Good:        `requests_total{result="success"}`,
+		Remediation: "Split the metric or use a label naming the state.",
+	},
+	{
+		ID:          RuleNumericFragment,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "name segment should not look like a date, version, percentile or number",
+		Rationale:   "Such segments belong in labels or are computed by queries.",
+		Bad:         "api_v1_latency_p95_seconds",
+		Good:        "api_latency_seconds with a version label",
+		Remediation: "Move the fragment to a label or remove it.",
+	},
+	{
+		ID:          RuleHelpPrefix,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "help text should start with the prefix configured for the metric type",
+		Rationale:   "Consistent help text is easier to scan.",
+		Bad:         `Help: "Requests."`,
+		Good:        `Help: "Total number of requests."`,
+		Remediation: "Start the help with the configured prefix.",
+	},
+	{
+		ID:          RuleLabelDocumented,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "help text of a vector should mention its label names",
+		Rationale:   "Dashboard authors rely on the help text to know which dimensions a metric has.",
+		Bad:         `Help: "Total number of requests." with []string{"code"}`,
+		Good:        `Help: "Total number of requests by code." with []string{"code"}`,
+		Remediation: "Mention every label name in the help text.",
+	},
+	{
+		ID:          RuleNamespacePolicy,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "metric should have a namespace, and optionally a subsystem, allowed by the namespace policy",
+		Rationale:   "A prefix naming the owning component tells who to ask about a metric and keeps components from clashing.",
+		Bad:         `Name: "requests_total"`,
+		Good:        `Namespace: "apiserver", Name: "requests_total"`,
+		Remediation: "Set the namespace of the owning component, see WithNamespacePolicy to configure the allowed ones.",
+	},
+	{
+		ID:          RuleSummaryObjectives,
+		Category:    RuleCategoryOptIn,
+		Severity:    SeverityWarning,
+		Description: "summary should have objectives",
+		Rationale:   "Since client_golang v0.10 a summary has no quantile by default, it only exposes a sum and a count.",
+		Bad:         "prometheus.SummaryOpts{Name: \"request_duration_seconds\"}",
+		Good:        "Objectives: map[float64]float64{0.5: 0.05, 0.99: 0.001}",
+		Remediation: "Set the objectives, or use a histogram which can be aggregated.",
+	},
+	{
+		ID:          RuleConstantZero,
+		Category:    RuleCategoryRuntime,
+		Severity:    SeverityWarning,
+		Description: "family should not stay zero across snapshots",
+		Rationale:   "Metrics that never fire only bloat scrapes.",
+		Bad:         "a counter of a feature which is disabled",
+		Good:        "a counter registered once the feature is enabled",
+		Remediation: "Register the metric lazily or remove it.",
+	},
+	{
+		ID:          RuleUnboundedLabel,
+		Category:    RuleCategoryRuntime,
+		Severity:    SeverityWarning,
+		Description: "path, url, uri, id and user labels should not take many distinct values",
+		Rationale:   "Unbounded label values explode the number of series.",
+		Bad:         `requests_total{path="/users/42"}`,
+		Good:        `requests_total{handler="/users/{id}"}`,
+		Remediation: "Use a bounded value such as the route template.",
+	},
+	{
+		ID:          RuleRegistrationDrift,
+		Category:    RuleCategoryRuntime,
+		Severity:    SeverityWarning,
+		Description: "a collector registered again should match the already registered one",
+		Rationale:   "Code reusing the registered collector silently drops the help, labels or type of the new one.",
+		Bad:         "two plugins registering requests_total with different help texts",
+		Good:        "a single definition of requests_total shared by the plugins",
+		Remediation: "Define the metric once, or give the metrics distinct names.",
+	},
+	{
+		ID:          RuleCounterDecreased,
+		Category:    RuleCategoryRuntime,
+		Severity:    SeverityError,
+		Description: "counter series should not decrease between two scrapes, except when reset to zero",
+		Rationale:   "rate() and increase() read any decrease as a reset, a counter going down is usually a gauge mislabeled as a counter.",
+		Bad:         "queue_items_total 5, then queue_items_total 3",
+		Good:        "queue_items 5, then queue_items 3",
+		Remediation: "Declare the metric as a gauge, or only ever add to the counter.",
+	},
+	{
+		ID:          RulePossiblyUnusedMetric,
+		Category:    RuleCategoryRuntime,
+		Severity:    SeverityWarning,
+		Description: "a metric should be referenced by a rule or change over time",
+		Rationale:   "Metrics nobody queries and whose value never changes cost series and scrape time for nothing.",
+		Bad:         "legacy_cache_hits_total constant for a week and absent from all rules",
+		Good:        "removing legacy_cache_hits_total, or alerting on it",
+		Remediation: "Check the dashboards for the metric, then remove it or the constant label combinations.",
+	},
+	{
+		ID:          RuleOpenMetricsCounterTotal,
+		Category:    RuleCategoryOpenMetrics,
+		Severity:    SeverityError,
+		Description: "counter samples should be named after the family with a \"_total\" suffix",
+		Rationale:   "OpenMetrics requires the \"_total\" suffix on counter samples and forbids it on the family name.",
+		Bad:         "# TYPE http_requests_total counter\nhttp_requests_total 3",
+		Good:        "# TYPE http_requests counter\nhttp_requests_total 3",
+		Remediation: "Name the family without \"_total\" and its samples with it.",
+	},
+	{
+		ID:          RuleOpenMetricsCreated,
+		Category:    RuleCategoryOpenMetrics,
+		Severity:    SeverityError,
+		Description: "\"_created\" series should belong to a counter, histogram or summary series",
+		Rationale:   "The creation time of a series is only defined for counters, histograms and summaries, and must match an existing series.",
+		Bad:         "# TYPE queue_length gauge\nqueue_length_created 1.6e9",
+		Good:        "# TYPE http_requests counter\nhttp_requests_total 3\nhttp_requests_created 1.6e9",
+		Remediation: "Drop the \"_created\" series or give it the labels of its series.",
+	},
+	{
+		ID:          RuleOpenMetricsInfo,
+		Category:    RuleCategoryOpenMetrics,
+		Severity:    SeverityError,
+		Description: "\"_info\" metrics should be info or gauge metrics with value 1",
+		Rationale:   "Info metrics carry their data in labels, joins on them rely on the value being 1.",
+		Bad:         "# TYPE build_info counter\nbuild_info_total 2",
+		Good:        "# TYPE build info\nbuild_info{version=\"1.0\"} 1",
+		Remediation: "Use the info type, or a gauge, and expose the value 1.",
+	},
+	{
+		ID:          RuleOpenMetricsUnit,
+		Category:    RuleCategoryOpenMetrics,
+		Severity:    SeverityError,
+		Description: "metric name should end with the unit of its UNIT metadata",
+		Rationale:   "OpenMetrics requires the unit to be the suffix of the family name, so the name alone tells the unit.",
+		Bad:         "# TYPE request_duration gauge\n# UNIT request_duration seconds",
+		Good:        "# TYPE request_duration_seconds gauge\n# UNIT request_duration_seconds seconds",
+		Remediation: "Add the unit as suffix of the name.",
+	},
+	{
+		ID:          RuleSeriesIncomplete,
+		Category:    RuleCategoryExposition,
+		Severity:    SeverityError,
+		Description: "histogram series should have _bucket, _sum and _count samples and a +Inf bucket, summary series _sum and _count samples",
+		Rationale:   "Queries such as histogram_quantile and rate(_sum) / rate(_count) silently return nothing on incomplete series.",
+		Bad:         "request_duration_seconds_bucket{le=\"1\"} 3\nrequest_duration_seconds_count 3",
+		Good:        "request_duration_seconds_bucket{le=\"1\"} 3\nrequest_duration_seconds_bucket{le=\"+Inf\"} 3\nrequest_duration_seconds_sum 1.2\nrequest_duration_seconds_count 3",
+		Remediation: "Expose every sample of the series, client libraries do so for their histograms and summaries.",
+	},
+	{
+		ID:          RuleHistogramBucketsCumulative,
+		Category:    RuleCategoryExposition,
+		Severity:    SeverityError,
+		Description: "histogram bucket counts should not decrease as the upper bound grows, and the +Inf bucket should equal the _count",
+		Rationale:   "Buckets are cumulative, histogram_quantile returns wrong quantiles for buckets counted per interval.",
+		Bad:         "request_duration_seconds_bucket{le=\"1\"} 3\nrequest_duration_seconds_bucket{le=\"+Inf\"} 1",
+		Good:        "request_duration_seconds_bucket{le=\"1\"} 1\nrequest_duration_seconds_bucket{le=\"+Inf\"} 3",
+		Remediation: "Count every observation in all the buckets whose upper bound it doesn't exceed.",
+	},
+}
+
+// Rules returns the metadata of all rules provided by metriclint, grouped by category.
+func Rules() []RuleInfo {
+	return append([]RuleInfo(nil), rules...)
+}
+
+// RuleByID returns the metadata of the built-in rule with the given ID.
+func RuleByID(id string) (RuleInfo, bool) {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+
+	return RuleInfo{}, false
+}
+
+// Explain describes a rule for developers unfamiliar with the Prometheus conventions: its rationale,
+// examples of bad and good declarations, and how to fix a violation. Declarative rules of the linter
+// are looked up by name.
+func (l *Linter) Explain(ruleID string) (string, error) {
+	rule, ok := RuleByID(ruleID)
+	if !ok {
+		for _, r := range l.declarative {
+			if r.Name == ruleID {
+				rule, ok = r.info(), true
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", fmt.Errorf("unknown rule %q", ruleID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s): %s\n", rule.ID, rule.Category, rule.Description)
+	if rule.Rationale != "" {
+		fmt.Fprintf(&b, "\nWhy:\n    %s\n", rule.Rationale)
+	}
+	if rule.Bad != "" {
+		fmt.Fprintf(&b, "\nBad:\n    %s\n", rule.Bad)
+	}
+	if rule.Good != "" {
+		fmt.Fprintf(&b, "\nGood:\n    %s\n", rule.Good)
+	}
+	if rule.Remediation != "" {
+		fmt.Fprintf(&b, "\nFix:\n    %s\n", rule.Remediation)
+	}
+
+	return b.String(), nil
+}
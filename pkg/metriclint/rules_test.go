@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	categories := map[string]bool{
+		RuleCategoryCommon:          true,
+		RuleCategoryCounter:         true,
+		RuleCategoryHistogram:       true,
+		RuleCategorySummary:         true,
+		RuleCategoryNativeHistogram: true,
+		RuleCategoryBatch:           true,
+		RuleCategoryOptIn:           true,
+		RuleCategoryRuntime:         true,
+		RuleCategoryOpenMetrics:     true,
+		RuleCategoryExposition:      true,
+	}
+
+	seen := map[string]bool{}
+	for _, rule := range Rules() {
+		if seen[rule.ID] {
+			t.Errorf("duplicate rule id %q", rule.ID)
+		}
+		seen[rule.ID] = true
+
+		if !categories[rule.Category] {
+			t.Errorf("rule %q has unknown category %q", rule.ID, rule.Category)
+		}
+		if rule.Description == "" || rule.Rationale == "" || rule.Bad == "" || rule.Good == "" || rule.Remediation == "" {
+			t.Errorf("rule %q misses metadata: %+v", rule.ID, rule)
+		}
+	}
+
+	// Callers must not be able to modify the rule list.
+	Rules()[0].ID = "modified"
+	if Rules()[0].ID == "modified" {
+		t.Errorf("Rules should return a copy")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	linter, err := NewLinterFromConfig(&Config{Rules: []DeclarativeRule{
+		{Name: "component-label", Target: DeclarativeTargetLabel, Pattern: "component", Mode: DeclarativeModeRequired},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		id       string
+		contains []string
+		err      bool
+	}{
+		{
+			id:       "counter-total-suffix",
+			contains: []string{"counter-total-suffix (counter):", "\nWhy:\n", "\nBad:\n    http_requests\n", "\nGood:\n    http_requests_total\n", "\nFix:\n"},
+		},
+		{
+			id:       "component-label",
+			contains: []string{`component-label (declarative): label should match "component"`},
+		},
+		{
+			id:  "ML007",
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.id, func(t *testing.T) {
+			text, err := linter.Explain(tc.id)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(text, s) {
+					t.Errorf("expected explanation to contain: %q, but got:\n%s", s, text)
+				}
+			}
+		})
+	}
+}
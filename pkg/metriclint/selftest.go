@@ -0,0 +1,201 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"time"
+)
+
+// SelfTestStatus is the outcome of a rule in a self test.
+type SelfTestStatus string
+
+const (
+	// SelfTestActive means the rule reports the known-bad declaration.
+	SelfTestActive SelfTestStatus = "active"
+
+	// SelfTestDisabled means the rule is disabled, or opt-in and not enabled, and reports nothing.
+	SelfTestDisabled SelfTestStatus = "disabled"
+
+	// SelfTestMisconfigured means the rule doesn't behave as configured, e.g. a declarative rule
+	// forbidding every known-good declaration.
+	SelfTestMisconfigured SelfTestStatus = "misconfigured"
+
+	// SelfTestSkipped means the rule can't be checked on single declarations, e.g. a batch rule.
+	SelfTestSkipped SelfTestStatus = "skipped"
+)
+
+// SelfTestResult is the outcome of a rule in a self test.
+type SelfTestResult struct {
+	Rule   string         `json:"rule"`
+	Status SelfTestStatus `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+}
+
+// selfTestBad is a known-bad declaration per rule, each violating the rule it's keyed by.
+var selfTestBad = map[string]MetricSpec{
+	RuleHelpMissing:              {Name: "http_requests_total", Type: MetricTypeCounter},
+	RuleNonBaseUnit:              {Name: "http_request_duration_milliseconds", Help: "Duration of requests.", Type: MetricTypeGauge},
+	RuleNameHasType:              {Name: "http_requests_counter", Help: "Number of requests.", Type: MetricTypeGauge},
+	RuleNameReservedChars:        {Name: "http:requests", Help: "Number of requests.", Type: MetricTypeGauge},
+	RuleNameCamelCase:            {Name: "httpRequests", Help: "Number of requests.", Type: MetricTypeGauge},
+	RuleLabelCamelCase:           {Name: "http_requests", Help: "Number of requests.", Type: MetricTypeGauge, VariableLabels: []string{"statusCode"}},
+	RuleLabelReservedPrefix:      {Name: "http_requests", Help: "Number of requests.", Type: MetricTypeGauge, ConstLabels: map[string]string{"__zone": "a"}},
+	RuleLabelDuplicate:           {Name: "http_requests", Help: "Number of requests.", Type: MetricTypeGauge, VariableLabels: []string{"code", "code"}},
+	RuleLabelShadowsConstLabel:   {Name: "http_requests", Help: "Number of requests.", Type: MetricTypeGauge, ConstLabels: map[string]string{"zone": "a"}, VariableLabels: []string{"zone"}},
+	RuleLabelRepeatsName:         {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter, VariableLabels: []string{"http_method"}},
+	RuleNameAbbreviatedUnit:      {Name: "request_duration_ms", Help: "Duration of requests.", Type: MetricTypeGauge},
+	RuleNameSuffixTypo:           {Name: "request_duration_secconds", Help: "Duration of requests.", Type: MetricTypeGauge},
+	RuleHighCardinalityLabel:     {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter, VariableLabels: []string{"path"}},
+	RuleInvalidName:              {Name: "http-requests", Help: "Number of requests.", Type: MetricTypeGauge},
+	RuleRatio:                    {Name: "cache_hit_ratio_total", Help: "Ratio of cache hits.", Type: MetricTypeCounter},
+	RulePercentName:              {Name: "cpu_usage_percent", Help: "CPU usage.", Type: MetricTypeGauge},
+	RuleInfoMetric:               {Name: "build_info", Help: "Build information.", Type: MetricTypeCounter},
+	RuleInfoName:                 {Name: "build", Help: "Build information.", Type: MetricTypeGauge, ConstLabels: map[string]string{"version": "1.0"}},
+	RuleTimestampName:            {Name: "job_last_success_unix", Help: "Last time the job succeeded.", Type: MetricTypeGauge},
+	RuleUntypedMetric:            {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeUntyped},
+	RuleDeclaredUnit:             {Name: "request_duration", Help: "Duration of requests.", Type: MetricTypeGauge, Unit: "milliseconds"},
+	RuleNameEmpty:                {Help: "Queue length.", Type: MetricTypeGauge},
+	RuleNamespaceEqualsSubsystem: {Namespace: "kubelet", Subsystem: "kubelet", Name: "pods", Help: "Number of pods.", Type: MetricTypeGauge},
+	RuleNameDoubleUnderscore:     {Namespace: "kubelet_", Name: "pods", Help: "Number of pods.", Type: MetricTypeGauge},
+	RuleCounterTotalSuffix:       {Name: "http_requests", Help: "Total number of requests.", Type: MetricTypeCounter},
+	RuleNonCounterTotalSuffix:    {Name: "queue_length_total", Help: "Queue length.", Type: MetricTypeGauge},
+	RuleCounterFuncDecreasing:    {Name: "queue_length_total", Help: "Current length of the queue.", Type: MetricTypeCounter},
+	RuleNonHistogramBucketSuffix: {Name: "queue_bucket", Help: "Queue length.", Type: MetricTypeGauge},
+	RuleNonHistogramCountSuffix:  {Name: "pods_count", Help: "Number of pods.", Type: MetricTypeGauge},
+	RuleNonHistogramSumSuffix:    {Name: "bytes_sum", Help: "Number of bytes.", Type: MetricTypeGauge},
+	RuleNonHistogramLeLabel:      {Name: "queue_length", Help: "Queue length.", Type: MetricTypeGauge, VariableLabels: []string{"le"}},
+	RuleNonSummaryQuantileLabel:  {Name: "queue_length", Help: "Queue length.", Type: MetricTypeGauge, VariableLabels: []string{"quantile"}},
+	RuleHistogramLeLabel:         {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram, VariableLabels: []string{"le"}},
+	RuleSummaryQuantileLabel:     {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeSummary, Objectives: map[float64]float64{0.5: 0.05}, VariableLabels: []string{"quantile"}},
+	RuleVectorLabels:             {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter, VariableLabels: []string{"code", ""}},
+	RuleSummaryQuantiles: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeSummary,
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.5}},
+	RuleSummaryMaxAge: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeSummary,
+		Objectives: map[float64]float64{0.5: 0.05}, MaxAge: -time.Minute},
+	RuleHistogramBucketsOrder: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		Buckets: []float64{1, 0.5}},
+	RuleHistogramBuckets: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		Buckets: []float64{}},
+	RuleHistogramBucketScale: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		Buckets: []float64{100, 500, 1000}},
+	RuleNativeHistogramBucketFactor: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		NativeHistogram: &NativeHistogramSpec{BucketFactor: 1}},
+	RuleNativeHistogramZeroThreshold: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		NativeHistogram: &NativeHistogramSpec{BucketFactor: 1.1, ZeroThreshold: -0.5}},
+	RuleNativeHistogramMaxZeroThreshold: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		NativeHistogram: &NativeHistogramSpec{BucketFactor: 1.1, ZeroThreshold: 0.1, MaxZeroThreshold: 0.01}},
+	RuleNativeHistogramMinResetDuration: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeHistogram,
+		NativeHistogram: &NativeHistogramSpec{BucketFactor: 1.1, MinResetDuration: 5 * time.Second}},
+	RuleUnitSuffix:        {Name: "queue_depth", Help: "Depth of the queue.", Type: MetricTypeGauge},
+	RuleAcronymLowercase:  {Name: "HTTP_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter},
+	RuleBooleanLabel:      {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter, ConstLabels: map[string]string{"tls": "true"}},
+	RuleNumericFragment:   {Name: "http_requests_v1_total", Help: "Total number of requests.", Type: MetricTypeCounter},
+	RuleHelpPrefix:        {Name: "http_requests_total", Help: "Requests served.", Type: MetricTypeCounter},
+	RuleSummaryObjectives: {Name: "request_duration_seconds", Help: "Distribution of request durations.", Type: MetricTypeSummary},
+	RuleNamespacePolicy:   {Name: "requests", Help: "Number of requests.", Type: MetricTypeGauge},
+	RuleLabelDocumented:   {Name: "http_requests_total", Help: "Total number of requests.", Type: MetricTypeCounter, VariableLabels: []string{"code"}},
+}
+
+// selfTestEntryPoints lint the known-bad declarations of the rules only reported by a dedicated entry point,
+// the others are linted with LintVector.
+var selfTestEntryPoints = map[string]func(l *Linter, spec MetricSpec) *LintResult{
+	RuleUntypedMetric:         (*Linter).LintUntypedVector,
+	RuleCounterFuncDecreasing: (*Linter).LintCounterFunc,
+}
+
+// selfTestGood are known-good declarations, no built-in rule reports them.
+var selfTestGood = []MetricSpec{
+	{Name: "http_requests_total", Help: "Total number of HTTP requests by code and method.", Type: MetricTypeCounter, VariableLabels: []string{"code", "method"}},
+	{Namespace: "kubelet", Subsystem: "runtime", Name: "operations_total", Help: "Total number of runtime operations.", Type: MetricTypeCounter},
+	{Name: "queue_length_bytes", Help: "Size of the queue.", Type: MetricTypeGauge, ConstLabels: map[string]string{"queue": "default"}},
+	{Name: "request_duration_seconds", Help: "Distribution of request durations by handler.", Type: MetricTypeHistogram, VariableLabels: []string{"handler"}},
+	{Name: "response_size_bytes", Help: "Distribution of response sizes.", Type: MetricTypeSummary,
+		Objectives: map[float64]float64{0.5: 0.05, 0.99: 0.001}},
+}
+
+// SelfTest runs the linter against a built-in corpus of known-good and known-bad declarations and reports,
+// for every rule, whether it's active, disabled or misconfigured, in the order of Rules followed by the
+// declarative rules. It lets operators check the effective config before gating on it.
+func (l *Linter) SelfTest() []SelfTestResult {
+	var results []SelfTestResult
+
+	for _, rule := range rules {
+		bad, ok := selfTestBad[rule.ID]
+		if !ok {
+			results = append(results, SelfTestResult{Rule: rule.ID, Status: SelfTestSkipped,
+				Detail: fmt.Sprintf("%s rules don't run on single declarations", rule.Category)})
+			continue
+		}
+
+		_, optIn := optInRules[rule.ID]
+		off := l.disabled[rule.ID] || optIn && !l.enabled[rule.ID]
+		lint := l.LintVector
+		if entry, ok := selfTestEntryPoints[rule.ID]; ok {
+			lint = func(spec MetricSpec) *LintResult { return entry(l, spec) }
+		}
+		reported := reportsRule(lint(bad), rule.ID)
+
+		switch {
+		case off && !reported:
+			results = append(results, SelfTestResult{Rule: rule.ID, Status: SelfTestDisabled})
+		case !off && reported:
+			results = append(results, SelfTestResult{Rule: rule.ID, Status: SelfTestActive})
+		case off:
+			results = append(results, SelfTestResult{Rule: rule.ID, Status: SelfTestMisconfigured, Detail: "disabled but reports " + bad.FQName()})
+		default:
+			results = append(results, SelfTestResult{Rule: rule.ID, Status: SelfTestMisconfigured, Detail: "enabled but misses " + bad.FQName()})
+		}
+	}
+
+	for _, rule := range l.declarative {
+		if l.disabled[rule.Name] {
+			results = append(results, SelfTestResult{Rule: rule.Name, Status: SelfTestDisabled})
+			continue
+		}
+
+		flagged := 0
+		for _, good := range selfTestGood {
+			if reportsRule(l.Lint(good), rule.Name) {
+				flagged++
+			}
+		}
+		// A required pattern may legitimately miss every declaration of the corpus, a forbidden one hardly.
+		if rule.Mode == DeclarativeModeForbidden && flagged == len(selfTestGood) {
+			results = append(results, SelfTestResult{Rule: rule.Name, Status: SelfTestMisconfigured,
+				Detail: "forbids every known-good declaration, check its pattern"})
+			continue
+		}
+		results = append(results, SelfTestResult{Rule: rule.Name, Status: SelfTestActive,
+			Detail: fmt.Sprintf("reports %d of %d known-good declarations", flagged, len(selfTestGood))})
+	}
+
+	return results
+}
+
+func reportsRule(result *LintResult, id string) bool {
+	for _, issue := range result.Findings {
+		if issue.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"testing"
+)
+
+func TestSelfTestCorpus(t *testing.T) {
+	var all []string
+	for id := range optInRules {
+		all = append(all, id)
+	}
+	linter := NewLinter(EnableRules(all...))
+
+	for id, bad := range selfTestBad {
+		lint := linter.LintVector
+		if entry, ok := selfTestEntryPoints[id]; ok {
+			lint = func(spec MetricSpec) *LintResult { return entry(linter, spec) }
+		}
+		if !reportsRule(lint(bad), id) {
+			t.Errorf("expected the known-bad declaration of %s to be reported, but got: %v", id, lint(bad).Findings)
+		}
+	}
+	for _, good := range selfTestGood {
+		if result := linter.Lint(good); len(result.Findings) != 0 {
+			t.Errorf("expected no issue for known-good %s, but got: %v", good.FQName(), result.Findings)
+		}
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	linter, err := NewLinterFromConfig(&Config{
+		Enable:  []string{RuleUnitSuffix},
+		Disable: []string{RuleHelpMissing},
+		Rules: []DeclarativeRule{
+			{Name: "component-label", Target: DeclarativeTargetLabel, Pattern: "component", Mode: DeclarativeModeRequired},
+			{Name: "no-anything", Target: DeclarativeTargetName, Pattern: ".*", Mode: DeclarativeModeForbidden},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses := map[string]SelfTestStatus{}
+	for _, result := range linter.SelfTest() {
+		statuses[result.Rule] = result.Status
+	}
+
+	expected := map[string]SelfTestStatus{
+		RuleHelpMissing:        SelfTestDisabled,
+		RuleUnitSuffix:         SelfTestActive,
+		RuleHelpPrefix:         SelfTestDisabled,
+		RuleCounterTotalSuffix: SelfTestActive,
+		RuleExporterPrefix:     SelfTestSkipped,
+		"component-label":      SelfTestActive,
+		"no-anything":          SelfTestMisconfigured,
+	}
+	for id, status := range expected {
+		if statuses[id] != status {
+			t.Errorf("expected %s: %s, but got: %s", id, status, statuses[id])
+		}
+	}
+	if len(statuses) != len(rules)+2 {
+		t.Errorf("expected: %d results, but got: %d", len(rules)+2, len(statuses))
+	}
+}
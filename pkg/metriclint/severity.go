@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "fmt"
+
+// Severity tells how blocking an issue is.
+type Severity string
+
+const (
+	// SeverityWarning issues are advisory.
+	SeverityWarning Severity = "warning"
+
+	// SeverityError issues are blocking.
+	SeverityError Severity = "error"
+)
+
+// validate returns an error if the severity is none of the known severities.
+func (s Severity) validate() error {
+	switch s {
+	case SeverityWarning, SeverityError:
+		return nil
+	default:
+		return fmt.Errorf("unknown severity %q", s)
+	}
+}
+
+// severityRanks orders the severities from the least to the most blocking.
+var severityRanks = map[Severity]int{
+	SeverityWarning: 1,
+	SeverityError:   2,
+}
+
+// AtLeast reports whether s is as blocking as threshold, or more. Unknown severities, e.g. of issues
+// without rule, are below any threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	rank := severityRanks[s]
+	return rank > 0 && rank >= severityRanks[threshold]
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package source lints metrics declared in Go source files, without building them.
+// It finds client_golang option literals such as prometheus.CounterOpts{...} and lints the
+// ones whose names are string literals, so that editors can report issues as the code is typed.
+package source
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const prometheusImportPath = "github.com/prometheus/client_golang/prometheus"
+
+// Diagnostic is a lint issue located in a source file.
+type Diagnostic struct {
+	// Position of the expression the issue is reported on, End is exclusive.
+	Pos token.Position
+	End token.Position
+
+	// Fully qualified name of the metric.
+	Metric string
+
+	// ID of the rule reporting the issue, and its severity.
+	Rule     string
+	Severity metriclint.Severity
+
+	// Human readable description of the issue.
+	Message string
+}
+
+// Analyzer lints the metric option literals of Go source files with a configured metriclint.Linter.
+type Analyzer struct {
+	linter *metriclint.Linter
+}
+
+// NewAnalyzer returns an Analyzer linting with l.
+func NewAnalyzer(l *metriclint.Linter) *Analyzer {
+	return &Analyzer{linter: l}
+}
+
+// defaultAnalyzer backs AnalyzeFile and LintFile, it runs the default rules.
+var defaultAnalyzer = NewAnalyzer(metriclint.NewLinter())
+
+// Types of the option literals, the metric type is derived from.
+var optsTypes = map[string]metriclint.MetricType{
+	"CounterOpts":   metriclint.MetricTypeCounter,
+	"GaugeOpts":     metriclint.MetricTypeGauge,
+	"HistogramOpts": metriclint.MetricTypeHistogram,
+	"SummaryOpts":   metriclint.MetricTypeSummary,
+}
+
+// AnalyzeFile parses the Go source and lints the metric option literals in it with the default rules,
+// see Analyzer.AnalyzeFile.
+func AnalyzeFile(filename string, src []byte) ([]Diagnostic, error) {
+	return defaultAnalyzer.AnalyzeFile(filename, src)
+}
+
+// AnalyzeFile parses the Go source and lints the metric option literals in it.
+// Literals whose names aren't built from string literals are skipped.
+func (a *Analyzer) AnalyzeFile(filename string, src []byte) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	err := a.analyze(filename, src, func(result *metriclint.LintResult, pos, end token.Position) {
+		for _, issue := range result.Findings {
+			diagnostics = append(diagnostics, Diagnostic{
+				Pos:      pos,
+				End:      end,
+				Metric:   result.MetricName,
+				Rule:     issue.ID,
+				Severity: issue.Severity,
+				Message:  issue.Message,
+			})
+		}
+	})
+
+	return diagnostics, err
+}
+
+// LintFile parses the Go source and lints the metric option literals in it with the default rules,
+// see Analyzer.LintFile.
+func LintFile(filename string, src []byte) ([]*metriclint.LintResult, error) {
+	return defaultAnalyzer.LintFile(filename, src)
+}
+
+// LintFile parses the Go source and returns the results of the metric option literals in it, one per
+// literal, whose issues are located at the literal's name. Literals whose names aren't built from string
+// literals are skipped.
+func (a *Analyzer) LintFile(filename string, src []byte) ([]*metriclint.LintResult, error) {
+	var results []*metriclint.LintResult
+	err := a.analyze(filename, src, func(result *metriclint.LintResult, pos, _ token.Position) {
+		for i := range result.Findings {
+			result.Findings[i].Location = &metriclint.SourceLocation{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+		}
+		results = append(results, result)
+	})
+
+	return results, err
+}
+
+// analyze parses the Go source and calls found with the result of every metric option literal in it, and
+// the position of the expression its issues are reported on.
+func (a *Analyzer) analyze(filename string, src []byte, found func(result *metriclint.LintResult, pos, end token.Position)) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	pkgName := prometheusPackageName(file)
+	if pkgName == "" {
+		return nil
+	}
+
+	// Option literals passed to a vector constructor, mapped to the label names.
+	labelNames := map[*ast.CompositeLit][]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		opts, ok := call.Args[0].(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if names, ok := stringSlice(call.Args[1]); ok {
+			labelNames[opts] = names
+		}
+		return true
+	})
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		metricType, ok := optsType(lit, pkgName)
+		if !ok {
+			return true
+		}
+
+		spec, at, ok := specFromLiteral(lit)
+		if !ok {
+			return true
+		}
+		spec.Type = metricType
+		spec.VariableLabels = labelNames[lit]
+
+		found(a.linter.Lint(spec), fset.Position(at.Pos()), fset.Position(at.End()))
+		return true
+	})
+
+	return nil
+}
+
+// prometheusPackageName returns the name the client_golang package is imported as, or "" if not imported.
+func prometheusPackageName(file *ast.File) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != prometheusImportPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "prometheus"
+	}
+
+	return ""
+}
+
+func optsType(lit *ast.CompositeLit, pkgName string) (metriclint.MetricType, bool) {
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != pkgName {
+		return "", false
+	}
+
+	metricType, ok := optsTypes[sel.Sel.Name]
+	return metricType, ok
+}
+
+// specFromLiteral builds the spec of an option literal, and returns the node issues are reported on.
+// ok is false if a name part isn't a string literal.
+func specFromLiteral(lit *ast.CompositeLit) (spec metriclint.MetricSpec, at ast.Node, ok bool) {
+	at = lit
+	for _, elt := range lit.Elts {
+		kv, isKV := elt.(*ast.KeyValueExpr)
+		if !isKV {
+			return spec, nil, false
+		}
+		key, isIdent := kv.Key.(*ast.Ident)
+		if !isIdent {
+			continue
+		}
+
+		switch key.Name {
+		case "Namespace", "Subsystem", "Name":
+			s, isString := stringLiteral(kv.Value)
+			if !isString {
+				return spec, nil, false
+			}
+			switch key.Name {
+			case "Namespace":
+				spec.Namespace = s
+			case "Subsystem":
+				spec.Subsystem = s
+			case "Name":
+				spec.Name = s
+				at = kv.Value
+			}
+		case "Help":
+			s, isString := stringLiteral(kv.Value)
+			if !isString {
+				// The help text is computed, assume it's there.
+				s = "-"
+			}
+			spec.Help = s
+		case "ConstLabels":
+			spec.ConstLabels = stringMap(kv.Value)
+		}
+	}
+
+	return spec, at, true
+}
+
+func stringLiteral(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// stringSlice returns the strings of a []string{...} literal.
+func stringSlice(e ast.Expr) ([]string, bool) {
+	lit, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	if arr, ok := lit.Type.(*ast.ArrayType); !ok || arr.Len != nil {
+		return nil, false
+	}
+
+	var values []string
+	for _, elt := range lit.Elts {
+		s, ok := stringLiteral(elt)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, s)
+	}
+
+	return values, true
+}
+
+// stringMap returns the literal entries of a map literal such as prometheus.Labels{...}.
+func stringMap(e ast.Expr) map[string]string {
+	lit, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	m := map[string]string{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		k, okKey := stringLiteral(kv.Key)
+		v, okValue := stringLiteral(kv.Value)
+		if okKey && okValue {
+			m[k] = v
+		}
+	}
+
+	return m
+}
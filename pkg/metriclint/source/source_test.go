@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+const testSource = `package test
+
+import prom "github.com/prometheus/client_golang/prometheus"
+
+var help = "computed help"
+
+var (
+	good = prom.NewCounterVec(prom.CounterOpts{
+		Name: "lint_requests_total",
+		Help: help,
+	}, []string{"code"})
+
+	noSuffix = prom.NewCounter(prom.CounterOpts{
+		Name: "lint_requests",
+		Help: "this is help message",
+	})
+
+	shadow = prom.NewGaugeVec(prom.GaugeOpts{
+		Name:        "lint_queue_length",
+		Help:        "this is help message",
+		ConstLabels: prom.Labels{"queue": "a"},
+	}, []string{"queue"})
+
+	computed = prom.NewGauge(prom.GaugeOpts{
+		Name: metricName,
+	})
+)
+`
+
+func TestAnalyzeFile(t *testing.T) {
+	diagnostics, err := AnalyzeFile("test.go", []byte(testSource))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Diagnostic{
+		{Metric: "lint_requests", Message: metriclint.LintErrMsgCounterShouldHaveTotalSuffix},
+		{Metric: "lint_queue_length", Message: fmt.Sprintf(metriclint.LintErrMsgLabelShadowsConstLabel, "queue", "a")},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, diagnostics)
+	}
+	for i := range expected {
+		if diagnostics[i].Metric != expected[i].Metric || diagnostics[i].Message != expected[i].Message {
+			t.Errorf("expected: %v, but got: %v", expected[i], diagnostics[i])
+		}
+	}
+
+	// Issues are reported on the name literal.
+	if diagnostics[0].Pos.Line != 14 || diagnostics[0].Pos.Column != 9 || diagnostics[0].End.Column != 24 {
+		t.Errorf("unexpected position: %v-%v", diagnostics[0].Pos, diagnostics[0].End)
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	results, err := LintFile("test.go", []byte(testSource))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"lint_requests_total", "lint_requests", "lint_queue_length"}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i := range expected {
+		if results[i].MetricName != expected[i] {
+			t.Errorf("expected: %s, but got: %s", expected[i], results[i].MetricName)
+		}
+	}
+
+	// Issues are located at the name literal.
+	location := metriclint.SourceLocation{File: "test.go", Line: 14, Column: 9}
+	if len(results[1].Findings) != 1 || results[1].Findings[0].Location == nil || *results[1].Findings[0].Location != location {
+		t.Errorf("expected: an issue at %v, but got: %+v", location, results[1].Findings)
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	linter := metriclint.NewLinter(
+		metriclint.DisableRules(metriclint.RuleCounterTotalSuffix),
+		metriclint.WithSeverities(map[string]metriclint.Severity{metriclint.RuleLabelShadowsConstLabel: metriclint.SeverityError}),
+	)
+	diagnostics, err := NewAnalyzer(linter).AnalyzeFile("test.go", []byte(testSource))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected a single diagnostic, but got: %v", diagnostics)
+	}
+	if d := diagnostics[0]; d.Metric != "lint_queue_length" || d.Rule != metriclint.RuleLabelShadowsConstLabel || d.Severity != metriclint.SeverityError {
+		t.Errorf("expected: lint_queue_length %s %s, but got: %v", metriclint.RuleLabelShadowsConstLabel, metriclint.SeverityError, d)
+	}
+}
+
+func TestAnalyzeFileWithoutPrometheus(t *testing.T) {
+	diagnostics, err := AnalyzeFile("test.go", []byte("package test\n\ntype CounterOpts struct{ Name string }\n\nvar _ = CounterOpts{Name: \"bad\"}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostic, but got: %v", diagnostics)
+	}
+
+	if _, err := AnalyzeFile("test.go", []byte("package")); err == nil {
+		t.Errorf("expected a syntax error")
+	}
+}
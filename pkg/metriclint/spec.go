@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"strings"
+	"time"
+)
+
+// MetricSpec is the declaration of a metric the rules operate on.
+// It's independent of any instrumentation library, adapters such as the promadapter package
+// convert their own metric options into a MetricSpec.
+type MetricSpec struct {
+	Namespace string
+	Subsystem string
+	Name      string
+
+	Help string
+
+	Type MetricType
+
+	// Unit of the metric, such as "seconds", mirroring the OpenMetrics UNIT metadata. Optional,
+	// when set it should be a base unit and the suffix of the name.
+	Unit string
+
+	// Labels with a constant value.
+	ConstLabels map[string]string
+
+	// Label names of a vector, their values are only known at runtime.
+	VariableLabels []string
+
+	// Upper bounds of the buckets of a histogram, without +Inf. Nil if the histogram uses the
+	// default buckets of its library or for other types.
+	Buckets []float64
+
+	// Quantiles of a summary mapped to their allowed error. Nil if the summary has no quantile or
+	// for other types. Specs converted from gathered metrics map the quantiles to a zero error.
+	Objectives map[float64]float64
+
+	// Duration observations stay relevant for a summary, zero for the default of the library.
+	MaxAge time.Duration
+
+	// Native histogram options of a histogram, nil if native histograms are not configured.
+	NativeHistogram *NativeHistogramSpec
+}
+
+// FQName joins the namespace, subsystem and name with "_", the same as prometheus.BuildFQName does.
+// It returns an empty string if the name is empty.
+func (s MetricSpec) FQName() string {
+	if s.Name == "" {
+		return ""
+	}
+
+	var parts []string
+	for _, p := range []string{s.Namespace, s.Subsystem, s.Name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, "_")
+}
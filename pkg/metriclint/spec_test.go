@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMetricSpecFQName(t *testing.T) {
+	tests := []struct {
+		spec     MetricSpec
+		expected string
+	}{
+		{spec: MetricSpec{Namespace: "lint", Subsystem: "test", Name: "total"}, expected: "lint_test_total"},
+		{spec: MetricSpec{Namespace: "lint", Name: "total"}, expected: "lint_total"},
+		{spec: MetricSpec{Subsystem: "test", Name: "total"}, expected: "test_total"},
+		{spec: MetricSpec{Namespace: "lint", Subsystem: "test"}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		if got := tc.spec.FQName(); got != tc.expected {
+			t.Errorf("expected: %s, but got: %s", tc.expected, got)
+		}
+	}
+}
+
+func TestLintSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           MetricSpec
+		expectedResult string
+	}{
+		{
+			name: "valid counter",
+			spec: MetricSpec{
+				Name:           "lint_test_total",
+				Help:           "this is help message",
+				Type:           MetricTypeCounter,
+				VariableLabels: []string{"code"},
+			},
+			expectedResult: "lint_test_total:",
+		},
+		{
+			name: "gauge with total suffix",
+			spec: MetricSpec{
+				Name: "lint_test_total",
+				Help: "this is help message",
+				Type: MetricTypeGauge,
+			},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNonCounterShouldNotHaveTotalSuffix),
+		},
+		{
+			name: "summary with le const label",
+			spec: MetricSpec{
+				Name:        "lint_test_seconds",
+				Help:        "this is help message",
+				Type:        MetricTypeSummary,
+				ConstLabels: map[string]string{"le": "1"},
+			},
+			expectedResult: fmt.Sprintf("lint_test_seconds:%s", LintErrMsgNonHistogramShouldNotHaveLeLabel),
+		},
+		{
+			name: "untyped with common issues only",
+			spec: MetricSpec{
+				Name: "lint_test_total",
+				Type: MetricTypeUntyped,
+			},
+			expectedResult: fmt.Sprintf("lint_test_total:%s", LintErrMsgNoHelp),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			lintResult := LintSpec(tc.spec)
+			if tc.expectedResult != lintResult.String() {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, lintResult.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoReport is returned by a Store when no report has been saved yet.
+var ErrNoReport = errors.New("no report found")
+
+// Report represents the lint results of a single lint run.
+type Report struct {
+	// The time the lint run happened.
+	Timestamp time.Time
+
+	// lint results of all metrics linted in the run.
+	Results []*LintResult
+
+	// Metrics scheduled for removal which were still present in the run. Their results are
+	// not part of Results.
+	Tombstoned []Tombstone `json:",omitempty"`
+
+	// Verdict of the linter on the results, see Linter.JudgeReport. Nil if the report wasn't judged.
+	// It's not stored, output formats render it.
+	Verdict *Verdict `json:"-"`
+}
+
+// HistoryEntry represents the lint result of a specific metric in a stored report.
+type HistoryEntry struct {
+	// The time of the report the entry comes from.
+	Timestamp time.Time
+
+	// lint errors of the metric in that report.
+	Issues []string
+}
+
+// Store persists reports so that results can be compared across runs.
+type Store interface {
+	// Save persists a report.
+	Save(report *Report) error
+
+	// LoadLatest returns the most recent report, or ErrNoReport if there is none.
+	LoadLatest() (*Report, error)
+
+	// History returns the results of the metric in all stored reports, oldest first.
+	// Reports that don't contain the metric are skipped.
+	History(metric string) ([]HistoryEntry, error)
+
+	// LoadSince returns all reports produced at or after the given time, oldest first.
+	LoadSince(since time.Time) ([]*Report, error)
+}
+
+const reportFilePrefix = "report-"
+const reportFileSuffix = ".json"
+
+// FileStore is a Store keeping one JSON file per report in a directory.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = &FileStore{}
+
+// NewFileStore returns a FileStore rooted at dir. The directory will be created on first Save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Save writes the report to the store directory.
+// A report without timestamp is stored with the current time, the report of the caller is left unchanged.
+func (fs *FileStore) Save(report *Report) error {
+	if report.Timestamp.IsZero() {
+		stamped := *report
+		stamped.Timestamp = time.Now()
+		report = &stamped
+	}
+
+	if err := os.MkdirAll(fs.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that readers never see a partial report.
+	name := filepath.Join(fs.dir, fmt.Sprintf("%s%d%s", reportFilePrefix, report.Timestamp.UnixNano(), reportFileSuffix))
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, name)
+}
+
+// LoadLatest returns the most recent report in the store directory.
+func (fs *FileStore) LoadLatest() (*Report, error) {
+	files, err := fs.reportFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, ErrNoReport
+	}
+
+	debugf("loading latest report from %s", files[len(files)-1])
+	return readReport(files[len(files)-1])
+}
+
+// History returns the results of the metric in all stored reports, oldest first.
+func (fs *FileStore) History(metric string) ([]HistoryEntry, error) {
+	reports, err := fs.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	for _, report := range reports {
+		for _, result := range report.Results {
+			if result.MetricName != metric {
+				continue
+			}
+			entries = append(entries, HistoryEntry{
+				Timestamp: report.Timestamp,
+				Issues:    result.Issues,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// LoadSince returns all reports produced at or after the given time, oldest first.
+func (fs *FileStore) LoadSince(since time.Time) ([]*Report, error) {
+	reports, err := fs.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*Report
+	for _, report := range reports {
+		if !report.Timestamp.Before(since) {
+			selected = append(selected, report)
+		}
+	}
+
+	return selected, nil
+}
+
+// loadAll reads all stored reports, oldest first.
+func (fs *FileStore) loadAll() ([]*Report, error) {
+	files, err := fs.reportFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*Report, 0, len(files))
+	for _, f := range files {
+		report, err := readReport(f)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// reportFiles lists the report files sorted by their timestamp, oldest first.
+func (fs *FileStore) reportFiles() ([]string, error) {
+	infos, err := ioutil.ReadDir(fs.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	type reportFile struct {
+		path      string
+		timestamp int64
+	}
+	var files []reportFile
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, reportFilePrefix) || !strings.HasSuffix(name, reportFileSuffix) {
+			continue
+		}
+
+		var ts int64
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, reportFilePrefix), reportFileSuffix), "%d", &ts); err != nil {
+			debugf("skipping unrecognized file %s in report store", name)
+			continue
+		}
+		files = append(files, reportFile{path: filepath.Join(fs.dir, name), timestamp: ts})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].timestamp < files[j].timestamp })
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+
+	return paths, nil
+}
+
+func readReport(path string) (*Report, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %v", path, err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,117 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint-store")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore(dir)
+
+	if _, err := store.LoadLatest(); err != ErrNoReport {
+		t.Fatalf("expected: %v, but got: %v", ErrNoReport, err)
+	}
+
+	base := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	reports := []*Report{
+		{
+			Timestamp: base,
+			Results: []*LintResult{
+				{MetricName: "lint_test_hours_total", Issues: []string{LintErrMsgNoHelp}},
+				{MetricName: "lint_test_other_total"},
+			},
+		},
+		{
+			Timestamp: base.Add(time.Hour),
+			Results: []*LintResult{
+				{MetricName: "lint_test_other_total"},
+			},
+		},
+		{
+			Timestamp: base.Add(2 * time.Hour),
+			Results: []*LintResult{
+				{MetricName: "lint_test_hours_total"},
+			},
+		},
+	}
+	// Save out of order to make sure the store orders by timestamp.
+	for _, i := range []int{2, 0, 1} {
+		if err := store.Save(reports[i]); err != nil {
+			t.Fatalf("failed to save report: %v", err)
+		}
+	}
+
+	latest, err := store.LoadLatest()
+	if err != nil {
+		t.Fatalf("failed to load latest report: %v", err)
+	}
+	if !latest.Timestamp.Equal(reports[2].Timestamp) {
+		t.Errorf("expected latest report at %v, but got: %v", reports[2].Timestamp, latest.Timestamp)
+	}
+
+	history, err := store.History("lint_test_hours_total")
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, but got: %d", len(history))
+	}
+	if !history[0].Timestamp.Equal(base) || len(history[0].Issues) != 1 || history[0].Issues[0] != LintErrMsgNoHelp {
+		t.Errorf("unexpected first history entry: %+v", history[0])
+	}
+	if len(history[1].Issues) != 0 {
+		t.Errorf("expected no issue in last history entry, but got: %v", history[1].Issues)
+	}
+}
+
+func TestFileStoreSaveStampsCopy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metriclint-store")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore(dir)
+	report := &Report{Results: []*LintResult{{MetricName: "queue_length"}}}
+	if err := store.Save(report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Timestamp.IsZero() {
+		t.Errorf("expected: the report of the caller unchanged, but got timestamp: %v", report.Timestamp)
+	}
+
+	latest, err := store.LoadLatest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest.Timestamp.IsZero() {
+		t.Errorf("expected: a stamped report, but got: %+v", latest)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	LintErrMsgSeriesIncomplete    = `%s series %s has no %s`
+	LintErrMsgBucketsDecreasing   = `histogram series %s has fewer observations in bucket le="%g" than in a lower bucket`
+	LintErrMsgBucketCountMismatch = `histogram series %s has a _count of %g, different from its +Inf bucket of %g`
+)
+
+// ScrapedSeries is the structure of a histogram or summary series of a scraped payload.
+type ScrapedSeries struct {
+	// Labels of the series, without "le" and "quantile".
+	Labels map[string]string
+
+	// Cumulative buckets of a histogram, in any order.
+	Buckets []ScrapedBucket
+
+	// Whether the series has a _sum and a _count sample, and the value of the latter.
+	HasSum   bool
+	HasCount bool
+	Count    float64
+}
+
+// ScrapedBucket is a _bucket sample of a histogram series.
+type ScrapedBucket struct {
+	UpperBound float64
+	Count      float64
+}
+
+// LintScrapedSeries checks the structure of the series of a scraped histogram or summary family, apart
+// from the naming rules: histogram series should have _bucket, _sum and _count samples and a +Inf bucket,
+// with bucket counts non-decreasing up to the _count, summary series a _sum and a _count sample.
+// The issues are added to the result of the family, following the policy of the linter.
+func (l *Linter) LintScrapedSeries(result *LintResult, metricType MetricType, series []ScrapedSeries) {
+	for _, s := range series {
+		id := SeriesID(result.MetricName, s.Labels)
+		l.AddRuleMessages(result, RuleSeriesIncomplete, lintSeriesIncomplete(metricType, id, s)...)
+		if metricType == MetricTypeHistogram {
+			l.AddRuleMessages(result, RuleHistogramBucketsCumulative, lintBucketsCumulative(id, s)...)
+		}
+	}
+}
+
+func lintSeriesIncomplete(metricType MetricType, id string, s ScrapedSeries) (issues []string) {
+	var missing []string
+	if metricType == MetricTypeHistogram {
+		if len(s.Buckets) == 0 {
+			missing = append(missing, "_bucket samples")
+		} else if !hasInfBucket(s.Buckets) {
+			missing = append(missing, "+Inf bucket")
+		}
+	}
+	if !s.HasSum {
+		missing = append(missing, "_sum sample")
+	}
+	if !s.HasCount {
+		missing = append(missing, "_count sample")
+	}
+
+	for _, m := range missing {
+		issues = append(issues, fmt.Sprintf(LintErrMsgSeriesIncomplete, metricType, id, m))
+	}
+
+	return issues
+}
+
+func lintBucketsCumulative(id string, s ScrapedSeries) (issues []string) {
+	buckets := append([]ScrapedBucket(nil), s.Buckets...)
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].UpperBound < buckets[j].UpperBound })
+
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i].Count < buckets[i-1].Count {
+			issues = append(issues, fmt.Sprintf(LintErrMsgBucketsDecreasing, id, buckets[i].UpperBound))
+			break
+		}
+	}
+	if n := len(buckets); n > 0 && math.IsInf(buckets[n-1].UpperBound, 1) && s.HasCount && s.Count != buckets[n-1].Count {
+		issues = append(issues, fmt.Sprintf(LintErrMsgBucketCountMismatch, id, s.Count, buckets[n-1].Count))
+	}
+
+	return issues
+}
+
+func hasInfBucket(buckets []ScrapedBucket) bool {
+	for _, b := range buckets {
+		if math.IsInf(b.UpperBound, 1) {
+			return true
+		}
+	}
+
+	return false
+}
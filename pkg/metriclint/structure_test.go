@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLintScrapedSeries(t *testing.T) {
+	inf := math.Inf(1)
+
+	var tests = []struct {
+		name       string
+		metricType MetricType
+		series     ScrapedSeries
+		expected   []string
+	}{
+		{
+			name:       "complete histogram",
+			metricType: MetricTypeHistogram,
+			series:     ScrapedSeries{Buckets: []ScrapedBucket{{UpperBound: inf, Count: 3}, {UpperBound: 1, Count: 2}}, HasSum: true, HasCount: true, Count: 3},
+		},
+		{
+			name:       "histogram without buckets",
+			metricType: MetricTypeHistogram,
+			series:     ScrapedSeries{HasSum: true, HasCount: true},
+			expected:   []string{fmt.Sprintf(LintErrMsgSeriesIncomplete, "histogram", "lint_seconds", "_bucket samples")},
+		},
+		{
+			name:       "complete summary",
+			metricType: MetricTypeSummary,
+			series:     ScrapedSeries{HasSum: true, HasCount: true, Count: 1},
+		},
+		{
+			name:       "summary without sum and count",
+			metricType: MetricTypeSummary,
+			series:     ScrapedSeries{Labels: map[string]string{"code": "200"}},
+			expected: []string{
+				fmt.Sprintf(LintErrMsgSeriesIncomplete, "summary", `lint_seconds{code="200"}`, "_sum sample"),
+				fmt.Sprintf(LintErrMsgSeriesIncomplete, "summary", `lint_seconds{code="200"}`, "_count sample"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := &LintResult{MetricName: "lint_seconds"}
+			NewLinter().LintScrapedSeries(result, tc.metricType, []ScrapedSeries{tc.series})
+			if !reflect.DeepEqual(result.Issues, tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, result.Issues)
+			}
+		})
+	}
+}
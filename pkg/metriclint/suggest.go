@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "strings"
+
+// suggest fills the suggestions of the issues reported for a spec, for the rules with an obvious fix:
+// the compliant metric name, or the compliant label name for label rules.
+func suggest(spec MetricSpec, issues []Issue) {
+	name := spec.FQName()
+	// The label issues are reported in the order of the labels.
+	labels := camelCaseLabels(spec.ConstLabels, spec.VariableLabels)
+	for i := range issues {
+		switch issues[i].ID {
+		case RuleCounterTotalSuffix:
+			issues[i].Suggestion = name + "_total"
+		case RuleNonCounterTotalSuffix:
+			issues[i].Suggestion = strings.TrimSuffix(name, "_total")
+		case RuleNameCamelCase:
+			issues[i].Suggestion = toSnakeCase(name)
+		case RuleNonBaseUnit:
+			issues[i].Suggestion = SuggestBaseUnitName(name)
+		case RuleTimestampName:
+			issues[i].Suggestion = suggestTimestampName(name)
+		case RulePercentName:
+			issues[i].Suggestion = suggestRatioName(name)
+		case RuleNameAbbreviatedUnit:
+			issues[i].Suggestion = SuggestBaseUnitName(expandAbbreviatedUnits(name))
+		case RuleLabelCamelCase:
+			if len(labels) > 0 {
+				issues[i].Suggestion = toSnakeCase(labels[0])
+				labels = labels[1:]
+			}
+		}
+	}
+}
+
+// SuggestBaseUnitName returns the metric name with its unit replaced by the base unit, e.g.
+// "http_request_duration_seconds" for "http_request_duration_milliseconds". A name without unit,
+// or already in a base unit, is returned unchanged.
+func SuggestBaseUnitName(name string) string {
+	unit, base, ok := DetectUnit(name)
+	if !ok || unit == base {
+		return name
+	}
+
+	return replaceSegment(name, unit, base)
+}
+
+// expandAbbreviatedUnits replaces the abbreviated units of the name, but its first segment, by the units,
+// e.g. "request_duration_milliseconds" for "request_duration_ms".
+func expandAbbreviatedUnits(name string) string {
+	segments := strings.Split(name, "_")
+	for i, s := range segments {
+		if unit, ok := abbreviatedUnits[s]; ok && i > 0 {
+			segments[i] = unit
+		}
+	}
+
+	return strings.Join(segments, "_")
+}
+
+// toSnakeCase splits camelCase words of s by "_" and lowercases it, e.g. "httpRequests" to "http_requests".
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && s[i-1] >= 'a' && s[i-1] <= 'z' {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// replaceSegment replaces the "_" separated segments of name equal to old.
+func replaceSegment(name, old, new string) string {
+	segments := strings.Split(name, "_")
+	for i, s := range segments {
+		if s == old {
+			segments[i] = new
+		}
+	}
+
+	return strings.Join(segments, "_")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"strings"
+)
+
+// Change is a step of a name suggestion, made to fix the issue of a rule.
+type Change struct {
+	// ID of the rule the change fixes, such as RuleNameCamelCase.
+	Rule string
+
+	// The name before and after the change.
+	From string
+	To   string
+}
+
+// abbreviatedUnits expands the unambiguous unit abbreviations, "m" may be meters or minutes.
+var abbreviatedUnits = map[string]string{
+	"s":   "seconds",
+	"sec": "seconds",
+	"ms":  "milliseconds",
+	"us":  "microseconds",
+	"ns":  "nanoseconds",
+	"b":   "bytes",
+	"kb":  "kilobytes",
+	"mb":  "megabytes",
+	"gb":  "gigabytes",
+	"tb":  "terabytes",
+	"pb":  "petabytes",
+	"h":   "hours",
+	"d":   "days",
+}
+
+// SuggestName applies all fixable rules to the name of the spec and returns the normalized name with
+// the changes made, in order: reserved characters and camelCase are turned into snake_case, type words
+// are removed, abbreviated units expanded and normalized to base units, and the unit and "_total" suffix
+// are moved to the end of the name. A compliant name is returned unchanged, without change.
+func SuggestName(spec MetricSpec) (string, []Change) {
+	name := spec.FQName()
+	var changes []Change
+	apply := func(rule string, fix func(segments []string) []string) {
+		fixed := strings.Join(fix(strings.Split(name, "_")), "_")
+		if fixed != name {
+			changes = append(changes, Change{Rule: rule, From: name, To: fixed})
+			name = fixed
+		}
+	}
+
+	apply(RuleNameReservedChars, func(segments []string) []string {
+		return strings.Split(strings.Replace(strings.Join(segments, "_"), ":", "_", -1), "_")
+	})
+	apply(RuleNameCamelCase, func(segments []string) []string {
+		return strings.Split(toSnakeCase(strings.Join(segments, "_")), "_")
+	})
+	apply(RuleNameHasType, func(segments []string) []string {
+		var kept []string
+		for _, s := range segments {
+			if !isMetricTypeWord(s) {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == 0 {
+			return segments
+		}
+		return kept
+	})
+	apply(RuleNameAbbreviatedUnit, func(segments []string) []string {
+		for i, s := range segments {
+			if unit, ok := abbreviatedUnits[s]; ok && i > 0 {
+				segments[i] = unit
+			}
+		}
+		return segments
+	})
+	apply(RuleNonBaseUnit, func(segments []string) []string {
+		for i, s := range segments {
+			if base, ok := baseUnit(s); ok {
+				segments[i] = base
+			}
+		}
+		return segments
+	})
+	apply(RuleUnitSuffix, func(segments []string) []string {
+		// Move the unit behind the other segments, "_total" is handled below.
+		end := len(segments)
+		if segments[end-1] == "total" {
+			end--
+		}
+		for i := 0; i < end-1; i++ {
+			if defaultUnits.isUnit(segments[i]) {
+				unit := segments[i]
+				copy(segments[i:end-1], segments[i+1:end])
+				segments[end-1] = unit
+				break
+			}
+		}
+		return segments
+	})
+
+	if spec.Type == MetricTypeCounter {
+		apply(RuleCounterTotalSuffix, func(segments []string) []string {
+			var kept []string
+			for _, s := range segments {
+				if s != "total" {
+					kept = append(kept, s)
+				}
+			}
+			return append(kept, "total")
+		})
+	} else if len(strings.Split(name, "_")) > 1 {
+		apply(RuleNonCounterTotalSuffix, func(segments []string) []string {
+			if segments[len(segments)-1] == "total" {
+				return segments[:len(segments)-1]
+			}
+			return segments
+		})
+	}
+
+	return name, changes
+}
+
+// FixName returns the name normalized by all fixable rules for a metric of the given type, see SuggestName,
+// e.g. "request_duration_seconds" for the histogram "requestDuration_ms". It lets code generators rename
+// metrics automatically.
+func FixName(name string, metricType MetricType) string {
+	fixed, _ := SuggestName(MetricSpec{Name: name, Type: metricType})
+
+	return fixed
+}
+
+func isMetricTypeWord(s string) bool {
+	for _, t := range typedMetricTypes {
+		if strings.ToLower(s) == string(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// baseUnit returns the base unit of a unit which isn't one, e.g. "seconds" for "milliseconds".
+func baseUnit(segment string) (string, bool) {
+	unit, base, ok := DetectUnit(segment)
+	if !ok || unit == base {
+		return "", false
+	}
+
+	return base, true
+}
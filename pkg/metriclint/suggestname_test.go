@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestName(t *testing.T) {
+	tests := []struct {
+		name            string
+		spec            MetricSpec
+		expectedName    string
+		expectedChanges []Change
+	}{
+		{
+			name:         "compliant",
+			spec:         MetricSpec{Name: "http_request_duration_seconds", Type: MetricTypeHistogram},
+			expectedName: "http_request_duration_seconds",
+		},
+		{
+			name:         "camel case counter",
+			spec:         MetricSpec{Namespace: "api", Name: "httpRequests", Type: MetricTypeCounter},
+			expectedName: "api_http_requests_total",
+			expectedChanges: []Change{
+				{Rule: RuleNameCamelCase, From: "api_httpRequests", To: "api_http_requests"},
+				{Rule: RuleCounterTotalSuffix, From: "api_http_requests", To: "api_http_requests_total"},
+			},
+		},
+		{
+			name:         "abbreviated unit before total",
+			spec:         MetricSpec{Name: "request_ms_total_counter", Type: MetricTypeCounter},
+			expectedName: "request_seconds_total",
+			expectedChanges: []Change{
+				{Rule: RuleNameHasType, From: "request_ms_total_counter", To: "request_ms_total"},
+				{Rule: RuleNameAbbreviatedUnit, From: "request_ms_total", To: "request_milliseconds_total"},
+				{Rule: RuleNonBaseUnit, From: "request_milliseconds_total", To: "request_seconds_total"},
+			},
+		},
+		{
+			name:         "unit in the middle of a gauge",
+			spec:         MetricSpec{Name: "cache:hours_age_total", Type: MetricTypeGauge},
+			expectedName: "cache_age_seconds",
+			expectedChanges: []Change{
+				{Rule: RuleNameReservedChars, From: "cache:hours_age_total", To: "cache_hours_age_total"},
+				{Rule: RuleNonBaseUnit, From: "cache_hours_age_total", To: "cache_seconds_age_total"},
+				{Rule: RuleUnitSuffix, From: "cache_seconds_age_total", To: "cache_age_seconds_total"},
+				{Rule: RuleNonCounterTotalSuffix, From: "cache_age_seconds_total", To: "cache_age_seconds"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			name, changes := SuggestName(tc.spec)
+			if name != tc.expectedName {
+				t.Errorf("expected: %s, but got: %s", tc.expectedName, name)
+			}
+			if !reflect.DeepEqual(changes, tc.expectedChanges) {
+				t.Errorf("expected: %v, but got: %v", tc.expectedChanges, changes)
+			}
+		})
+	}
+}
+
+func TestFixName(t *testing.T) {
+	tests := []struct {
+		name       string
+		metric     string
+		metricType MetricType
+		expected   string
+	}{
+		{
+			name:       "compliant",
+			metric:     "http_requests_total",
+			metricType: MetricTypeCounter,
+			expected:   "http_requests_total",
+		},
+		{
+			name:       "missing total suffix",
+			metric:     "http_requests",
+			metricType: MetricTypeCounter,
+			expected:   "http_requests_total",
+		},
+		{
+			name:       "abbreviated unit",
+			metric:     "request_ms",
+			metricType: MetricTypeHistogram,
+			expected:   "request_seconds",
+		},
+		{
+			name:       "camel case and non base unit",
+			metric:     "queueSize_kilobytes",
+			metricType: MetricTypeGauge,
+			expected:   "queue_size_bytes",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			if fixed := FixName(tc.metric, tc.metricType); fixed != tc.expected {
+				t.Errorf("expected: %s, but got: %s", tc.expected, fixed)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	LintErrMsgSummaryNoObjectives      = `summary has no objectives, it only exposes "_sum" and "_count"`
+	LintErrMsgSummaryQuantileRange     = `summary quantile %v should be between 0 and 1 exclusive`
+	LintErrMsgSummaryQuantileTolerance = `summary quantile %v has an error %v larger than the distance to quantile %v`
+	LintErrMsgSummaryMaxAge            = `summary max age %v should be positive`
+)
+
+// LintSummaryObjectives is an opt-in rule reporting summaries without objectives. Since client_golang
+// v0.10 summaries have no quantile by default, which is fine for a sum and a count, but usually an
+// oversight when the summary was meant for latencies.
+func LintSummaryObjectives(spec MetricSpec) (issues []string) {
+	if spec.Type == MetricTypeSummary && len(spec.Objectives) == 0 {
+		issues = append(issues, LintErrMsgSummaryNoObjectives)
+	}
+
+	return issues
+}
+
+// SummaryRule checks the objectives and the max age of a summary.
+type SummaryRule struct{}
+
+// Lint checks the objectives and the max age of the spec.
+func (r SummaryRule) Lint(spec MetricSpec) (issues []string) {
+	return IssueMessages(r.issues(spec))
+}
+
+// issues checks the objectives and the max age of the spec and reports the issues with their rule IDs.
+// A zero max age selects the default of the library and is not reported.
+func (r SummaryRule) issues(spec MetricSpec) (issues []Issue) {
+	quantiles := make([]float64, 0, len(spec.Objectives))
+	for q := range spec.Objectives {
+		quantiles = append(quantiles, q)
+	}
+	sort.Float64s(quantiles)
+
+	var messages []string
+	for i, q := range quantiles {
+		if q <= 0 || q >= 1 {
+			messages = append(messages, fmt.Sprintf(LintErrMsgSummaryQuantileRange, q))
+		}
+		// The error of a quantile should not reach its neighbours, which would make them indistinguishable.
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(quantiles) {
+				continue
+			}
+			if e, n := spec.Objectives[q], quantiles[j]; e > abs(n-q) {
+				messages = append(messages, fmt.Sprintf(LintErrMsgSummaryQuantileTolerance, q, e, n))
+				break
+			}
+		}
+	}
+	issues = append(issues, ruleIssues(RuleSummaryQuantiles, messages)...)
+
+	if spec.MaxAge < 0 {
+		issues = append(issues, ruleIssues(RuleSummaryMaxAge, []string{fmt.Sprintf(LintErrMsgSummaryMaxAge, spec.MaxAge)})...)
+	}
+
+	return issues
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+
+	return f
+}
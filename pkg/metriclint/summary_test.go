@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummaryRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		objectives     map[float64]float64
+		maxAge         time.Duration
+		expectedResult string
+	}{
+		{
+			name: "no objectives",
+		},
+		{
+			name:       "valid objectives",
+			objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			maxAge:     10 * time.Minute,
+		},
+		{
+			name:       "quantiles out of range",
+			objectives: map[float64]float64{0: 0, 1: 0},
+			expectedResult: strings.Join([]string{
+				fmt.Sprintf(LintErrMsgSummaryQuantileRange, 0.0),
+				fmt.Sprintf(LintErrMsgSummaryQuantileRange, 1.0),
+			}, ","),
+		},
+		{
+			name:           "error larger than quantile distance",
+			objectives:     map[float64]float64{0.5: 0.05, 0.9: 0.5},
+			expectedResult: fmt.Sprintf(LintErrMsgSummaryQuantileTolerance, 0.9, 0.5, 0.5),
+		},
+		{
+			name:           "negative max age",
+			objectives:     map[float64]float64{0.5: 0.05},
+			maxAge:         -time.Minute,
+			expectedResult: fmt.Sprintf(LintErrMsgSummaryMaxAge, -time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			spec := MetricSpec{Name: "lint_test_seconds", Type: MetricTypeSummary, Objectives: tc.objectives, MaxAge: tc.maxAge}
+			issues := strings.Join(SummaryRule{}.Lint(spec), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
+
+func TestLintSummaryObjectives(t *testing.T) {
+	spec := MetricSpec{Name: "lint_test_seconds", Help: "this is help message", Type: MetricTypeSummary}
+
+	if result := NewLinter().Lint(spec); len(result.Findings) != 0 {
+		t.Errorf("expected no issue by default, but got: %v", result.Findings)
+	}
+
+	result := NewLinter(EnableRules(RuleSummaryObjectives)).Lint(spec)
+	if len(result.Findings) != 1 || result.Findings[0].Message != LintErrMsgSummaryNoObjectives {
+		t.Errorf("expected: %s, but got: %v", LintErrMsgSummaryNoObjectives, result.Findings)
+	}
+}
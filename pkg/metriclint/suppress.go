@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// suppression is a compiled Suppression, or an issue suppressed by a Baseline.
+type suppression struct {
+	metric  *regexp.Regexp
+	rules   map[string]bool
+	expires time.Time
+	// message of the suppressed issue, any message if empty.
+	message string
+}
+
+// WithSuppressions suppresses rules for the metrics matching the patterns of the suppressions, until they expire.
+// It panics on malformed patterns, which are programming errors.
+func WithSuppressions(suppressions ...Suppression) Option {
+	return func(l *Linter) {
+		for _, s := range suppressions {
+			l.suppressions = append(l.suppressions, compileSuppression(s))
+		}
+	}
+}
+
+// Ignore suppresses rules by ID for the metrics matching the regular expression, anchored to the whole metric name,
+// e.g. l.Ignore("legacy_.*", RuleCounterTotalSuffix), so that legacy metrics don't fail the rules enforced on new
+// ones. Without rule ID, every rule is suppressed. It must not be called while the linter is in use. It panics on
+// malformed patterns and unknown rule IDs, which are programming errors.
+func (l *Linter) Ignore(pattern string, ruleIDs ...string) {
+	for _, id := range ruleIDs {
+		if !l.knows(id) {
+			panic(fmt.Sprintf("metriclint: unknown rule %q", id))
+		}
+	}
+
+	l.suppressions = append(l.suppressions, compileSuppression(Suppression{Metric: pattern, Rules: ruleIDs}))
+}
+
+func compileSuppression(s Suppression) suppression {
+	compiled := suppression{
+		metric:  regexp.MustCompile("^(?:" + s.Metric + ")$"),
+		expires: s.Expires,
+	}
+	if len(s.Rules) > 0 {
+		compiled.rules = map[string]bool{}
+		for _, id := range s.Rules {
+			compiled.rules[id] = true
+		}
+	}
+
+	return compiled
+}
+
+// suppresses reports whether the suppression silences the issue of the metric at the given time.
+func (s suppression) suppresses(metricName string, issue Issue, now time.Time) bool {
+	if !s.expires.IsZero() && !now.Before(s.expires) {
+		return false
+	}
+	if s.rules != nil && !s.rules[issue.ID] {
+		return false
+	}
+	if s.message != "" && s.message != issue.Message {
+		return false
+	}
+
+	return s.metric.MatchString(metricName)
+}
+
+// dropSuppressed removes the issues of the suppressed rules from the result.
+func (l *Linter) dropSuppressed(result *LintResult) {
+	if len(l.suppressions) == 0 {
+		return
+	}
+
+	now := time.Now()
+	var kept []Issue
+next:
+	for _, issue := range result.Findings {
+		for _, s := range l.suppressions {
+			if s.suppresses(result.MetricName, issue, now) {
+				continue next
+			}
+		}
+		kept = append(kept, issue)
+	}
+	result.Findings = kept
+	result.Issues = IssueMessages(kept)
+}
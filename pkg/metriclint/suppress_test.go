@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLinterIgnore(t *testing.T) {
+	// Both metrics lack help and the "_total" suffix.
+	legacy := MetricSpec{Name: "legacy_requests", Type: MetricTypeCounter}
+	current := MetricSpec{Name: "http_requests", Type: MetricTypeCounter}
+
+	var tests = []struct {
+		name    string
+		ignore  func(l *Linter)
+		legacy  []string
+		current []string
+	}{
+		{
+			name:    "no suppression",
+			ignore:  func(l *Linter) {},
+			legacy:  []string{RuleHelpMissing, RuleCounterTotalSuffix},
+			current: []string{RuleHelpMissing, RuleCounterTotalSuffix},
+		},
+		{
+			name:    "rule suppressed for matching metrics",
+			ignore:  func(l *Linter) { l.Ignore("legacy_.*", RuleCounterTotalSuffix) },
+			legacy:  []string{RuleHelpMissing},
+			current: []string{RuleHelpMissing, RuleCounterTotalSuffix},
+		},
+		{
+			name:    "pattern anchored to the whole name",
+			ignore:  func(l *Linter) { l.Ignore("legacy", RuleCounterTotalSuffix) },
+			legacy:  []string{RuleHelpMissing, RuleCounterTotalSuffix},
+			current: []string{RuleHelpMissing, RuleCounterTotalSuffix},
+		},
+		{
+			name:    "every rule suppressed without rule ID",
+			ignore:  func(l *Linter) { l.Ignore("legacy_.*") },
+			current: []string{RuleHelpMissing, RuleCounterTotalSuffix},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			linter := NewLinter()
+			tc.ignore(linter)
+
+			if ids := issueIDs(linter.Lint(legacy)); !reflect.DeepEqual(ids, tc.legacy) {
+				t.Errorf("expected: %v, but got: %v", tc.legacy, ids)
+			}
+			if ids := issueIDs(linter.Lint(current)); !reflect.DeepEqual(ids, tc.current) {
+				t.Errorf("expected: %v, but got: %v", tc.current, ids)
+			}
+		})
+	}
+}
+
+func TestLinterIgnoreUnknownRule(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for unknown rule")
+		}
+	}()
+	NewLinter().Ignore("legacy_.*", "counter-total-sufix")
+}
+
+func TestWithSuppressions(t *testing.T) {
+	spec := MetricSpec{Name: "legacy_requests", Help: "Number of requests.", Type: MetricTypeCounter}
+
+	linter, err := NewLinterFromConfig(&Config{Suppressions: []Suppression{
+		{Metric: "legacy_.*", Rules: []string{RuleCounterTotalSuffix}, Reason: "renamed in v2"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := linter.Lint(spec); len(result.Findings) != 0 {
+		t.Errorf("expected no issue, but got: %v", result.Findings)
+	}
+
+	// An expired suppression no longer applies.
+	s := compileSuppression(Suppression{Metric: "legacy_.*", Expires: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)})
+	if !s.suppresses(spec.Name, Issue{ID: RuleCounterTotalSuffix}, time.Date(2020, 5, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the suppression to apply before it expires")
+	}
+	if s.suppresses(spec.Name, Issue{ID: RuleCounterTotalSuffix}, time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the suppression not to apply once expired")
+	}
+}
+
+func issueIDs(result *LintResult) []string {
+	var ids []string
+	for _, issue := range result.Findings {
+		ids = append(ids, issue.ID)
+	}
+
+	return ids
+}
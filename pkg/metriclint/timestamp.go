@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LintErrMsgTimestampUnit        = `timestamp metrics should be in seconds since the epoch, e.g. %q`
+	LintErrMsgTimestampNonStandard = `%q is a non-standard timestamp suffix, use "_timestamp_seconds", e.g. %q`
+)
+
+// timestampUnitSegments are the trailing segments replaced by "_timestamp_seconds" in timestamp names.
+var timestampUnitSegments = map[string]bool{
+	"unix":         true,
+	"epoch":        true,
+	"s":            true,
+	"ms":           true,
+	"us":           true,
+	"ns":           true,
+	"seconds":      true,
+	"milliseconds": true,
+	"microseconds": true,
+	"nanoseconds":  true,
+}
+
+// lintTimestamp checks that gauges which look like timestamps, with a "timestamp" or "unix" segment, a trailing
+// "time" segment or a "last" segment without unit, are expressed in seconds since the epoch with the
+// "_timestamp_seconds" suffix, or "_time_seconds" like process_start_time_seconds.
+func lintTimestamp(name string, metricType MetricType) (issues []string) {
+	if metricType != MetricTypeGauge && metricType != MetricTypeUntyped {
+		return nil
+	}
+
+	segments := strings.Split(name, "_")
+	_, _, hasUnit := DetectUnit(name)
+	looksLike := segments[len(segments)-1] == "time"
+	for _, s := range segments {
+		if s == "timestamp" || s == "unix" || s == "last" && !hasUnit {
+			looksLike = true
+		}
+	}
+	if !looksLike {
+		return nil
+	}
+
+	stem, suffix := splitTimestampName(name)
+	suggestion := suggestTimestampName(name)
+	switch {
+	case suggestion == name:
+		return nil
+	case len(suffix) > 0:
+		if stem[strings.LastIndex(stem, "_")+1:] == "timestamp" {
+			suffix = append([]string{"timestamp"}, suffix...)
+		}
+		return []string{fmt.Sprintf(LintErrMsgTimestampNonStandard, "_"+strings.Join(suffix, "_"), suggestion)}
+	default:
+		return []string{fmt.Sprintf(LintErrMsgTimestampUnit, suggestion)}
+	}
+}
+
+// splitTimestampName splits the trailing unit segments, such as "unix" or "ms", off the timestamp name.
+func splitTimestampName(name string) (stem string, suffix []string) {
+	segments := strings.Split(name, "_")
+	end := len(segments)
+	for end > 1 && timestampUnitSegments[segments[end-1]] {
+		end--
+	}
+
+	return strings.Join(segments[:end], "_"), segments[end:]
+}
+
+// suggestTimestampName returns the timestamp name with the standard suffix, e.g. "job_last_success_timestamp_seconds"
+// for "job_last_success_unix" and "start_time_seconds" for "start_time".
+func suggestTimestampName(name string) string {
+	stem, _ := splitTimestampName(name)
+	if last := stem[strings.LastIndex(stem, "_")+1:]; last != "time" && last != "timestamp" {
+		stem += "_timestamp"
+	}
+
+	return stem + "_seconds"
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintTimestamp(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         string
+		metricType     MetricType
+		expectedResult string
+	}{
+		{
+			name:       "standard timestamp",
+			metric:     "lint_job_last_success_timestamp_seconds",
+			metricType: MetricTypeGauge,
+		},
+		{
+			name:       "start time",
+			metric:     "lint_process_start_time_seconds",
+			metricType: MetricTypeGauge,
+		},
+		{
+			name:       "duration of the last run",
+			metric:     "lint_last_gc_duration_seconds",
+			metricType: MetricTypeGauge,
+		},
+		{
+			name:       "counter",
+			metric:     "lint_last_runs_total",
+			metricType: MetricTypeCounter,
+		},
+		{
+			name:           "last without unit",
+			metric:         "lint_job_last_success",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgTimestampUnit, "lint_job_last_success_timestamp_seconds"),
+		},
+		{
+			name:           "time without unit",
+			metric:         "lint_start_time",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgTimestampUnit, "lint_start_time_seconds"),
+		},
+		{
+			name:           "timestamp without unit",
+			metric:         "lint_build_timestamp",
+			metricType:     MetricTypeUntyped,
+			expectedResult: fmt.Sprintf(LintErrMsgTimestampUnit, "lint_build_timestamp_seconds"),
+		},
+		{
+			name:           "unix",
+			metric:         "lint_job_last_success_unix",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgTimestampNonStandard, "_unix", "lint_job_last_success_timestamp_seconds"),
+		},
+		{
+			name:           "milliseconds",
+			metric:         "lint_created_timestamp_ms",
+			metricType:     MetricTypeGauge,
+			expectedResult: fmt.Sprintf(LintErrMsgTimestampNonStandard, "_timestamp_ms", "lint_created_timestamp_seconds"),
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			result := strings.Join(lintTimestamp(tc.metric, tc.metricType), ",")
+			if result != tc.expectedResult {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "sort"
+
+// Tombstone marks a metric scheduled for removal, typically a badly named metric kept around until
+// the dashboards and alerts have moved to its replacement.
+type Tombstone struct {
+	// Fully-qualified name of the metric.
+	Metric string `json:"metric" yaml:"metric"`
+
+	// Name of the metric replacing it, if any.
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+
+	// Why the metric is removed.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Release the metric is removed in, informational.
+	RemovedIn string `json:"removedIn,omitempty" yaml:"removedIn,omitempty"`
+}
+
+// WithTombstones marks metrics scheduled for removal, see Linter.Tombstone.
+func WithTombstones(tombstones ...Tombstone) Option {
+	return func(l *Linter) {
+		if l.tombstones == nil {
+			l.tombstones = map[string]Tombstone{}
+		}
+		for _, t := range tombstones {
+			l.tombstones[t.Metric] = t
+		}
+	}
+}
+
+// Tombstone returns the tombstone of the metric, if it's scheduled for removal.
+func (l *Linter) Tombstone(metricName string) (Tombstone, bool) {
+	t, ok := l.tombstones[metricName]
+	return t, ok
+}
+
+// SplitTombstoned separates the results of the metrics scheduled for removal from the others,
+// so that the issues of retiring metrics don't count against the live ones. The tombstones of the
+// tombstoned metrics are returned in the order of the results.
+func (l *Linter) SplitTombstoned(results []*LintResult) (live []*LintResult, tombstoned []Tombstone) {
+	for _, result := range results {
+		if t, ok := l.Tombstone(result.MetricName); ok {
+			tombstoned = append(tombstoned, t)
+			continue
+		}
+		live = append(live, result)
+	}
+
+	return live, tombstoned
+}
+
+func sortTombstones(tombstones []Tombstone) {
+	sort.SliceStable(tombstones, func(i, j int) bool { return tombstones[i].Metric < tombstones[j].Metric })
+}
@@ -0,0 +1,54 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "testing"
+
+func TestSplitTombstoned(t *testing.T) {
+	linter, err := NewLinterFromConfig(&Config{
+		Tombstones: []Tombstone{{Metric: "legacy_requests", Replacement: "http_requests_total"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := linter.Tombstone("http_requests_total"); ok {
+		t.Errorf("expected http_requests_total not to be tombstoned")
+	}
+
+	results := []*LintResult{
+		{MetricName: "http_requests_total"},
+		{MetricName: "legacy_requests", Issues: []string{LintErrMsgCounterShouldHaveTotalSuffix}},
+	}
+	live, tombstoned := linter.SplitTombstoned(results)
+	if len(live) != 1 || live[0].MetricName != "http_requests_total" {
+		t.Errorf("expected: http_requests_total, but got: %v", live)
+	}
+	if len(tombstoned) != 1 || tombstoned[0].Replacement != "http_requests_total" {
+		t.Errorf("expected: legacy_requests, but got: %v", tombstoned)
+	}
+
+	builder := NewReportBuilder()
+	builder.Add(live...)
+	builder.AddTombstoned(Tombstone{Metric: "legacy_z"}, tombstoned[0])
+	if report := builder.Build(); len(report.Tombstoned) != 2 || report.Tombstoned[0].Metric != "legacy_requests" {
+		t.Errorf("expected tombstones sorted by metric, but got: %v", report.Tombstoned)
+	}
+}
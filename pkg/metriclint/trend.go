@@ -0,0 +1,190 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrendPoint summarizes the issue counts of a single stored report.
+type TrendPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Total number of issues in the report.
+	Total int `json:"total"`
+
+	// Number of issues keyed by rule ID, or by message for issues without ID.
+	ByRule map[string]int `json:"byRule"`
+
+	// Number of issues keyed by metric namespace.
+	ByNamespace map[string]int `json:"byNamespace"`
+}
+
+// Regression represents a rule or namespace whose issue count grew within the trend window.
+type Regression struct {
+	// Kind is either "rule" or "namespace".
+	Kind string `json:"kind"`
+
+	// The rule or the namespace that regressed.
+	Key string `json:"key"`
+
+	// Issue count at the start and at the end of the window.
+	Previous int `json:"previous"`
+	Current  int `json:"current"`
+}
+
+// Trend summarizes issue counts over a time window.
+type Trend struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	// One point per stored report in the window, oldest first.
+	Points []TrendPoint `json:"points"`
+
+	// Rules and namespaces having more issues at the end of the window than at the start.
+	Regressions []Regression `json:"regressions"`
+}
+
+// TrendReport summarizes the reports stored in the last window.
+func TrendReport(store Store, window time.Duration) (*Trend, error) {
+	until := time.Now()
+	since := until.Add(-window)
+
+	reports, err := store.LoadSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTrend(reports, since, until), nil
+}
+
+func buildTrend(reports []*Report, since, until time.Time) *Trend {
+	trend := &Trend{Since: since, Until: until}
+
+	for _, report := range reports {
+		if report.Timestamp.After(until) {
+			continue
+		}
+
+		point := TrendPoint{
+			Timestamp:   report.Timestamp,
+			ByRule:      map[string]int{},
+			ByNamespace: map[string]int{},
+		}
+		for _, result := range report.Results {
+			for _, issue := range result.findings() {
+				point.Total++
+				point.ByRule[issue.ruleKey()]++
+				point.ByNamespace[metricNamespace(result.MetricName)]++
+			}
+		}
+		trend.Points = append(trend.Points, point)
+	}
+
+	if len(trend.Points) > 1 {
+		first, last := trend.Points[0], trend.Points[len(trend.Points)-1]
+		trend.Regressions = append(trend.Regressions, regressions("rule", first.ByRule, last.ByRule)...)
+		trend.Regressions = append(trend.Regressions, regressions("namespace", first.ByNamespace, last.ByNamespace)...)
+	}
+
+	return trend
+}
+
+func regressions(kind string, previous, current map[string]int) (result []Regression) {
+	for _, key := range sortedKeys(current) {
+		if current[key] > previous[key] {
+			result = append(result, Regression{Kind: kind, Key: key, Previous: previous[key], Current: current[key]})
+		}
+	}
+
+	return result
+}
+
+// metricNamespace returns the first segment of a metric name.
+func metricNamespace(name string) string {
+	if i := strings.Index(name, "_"); i > 0 {
+		return name[:i]
+	}
+
+	return name
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// WriteJSON writes the trend as an indented JSON document.
+func (t *Trend) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(t)
+}
+
+// WriteMarkdown writes the trend as a Markdown document.
+func (t *Trend) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Metric lint trend\n\n")
+	fmt.Fprintf(&b, "From %s to %s.\n\n", t.Since.Format(time.RFC3339), t.Until.Format(time.RFC3339))
+
+	b.WriteString("| Report | Issues |\n|---|---|\n")
+	for _, p := range t.Points {
+		fmt.Fprintf(&b, "| %s | %d |\n", p.Timestamp.Format(time.RFC3339), p.Total)
+	}
+
+	if len(t.Points) > 0 {
+		last := t.Points[len(t.Points)-1]
+		b.WriteString("\n## Issues by rule\n\n| Rule | Issues |\n|---|---|\n")
+		for _, rule := range sortedKeys(last.ByRule) {
+			fmt.Fprintf(&b, "| %s | %d |\n", escapeMarkdownCell(rule), last.ByRule[rule])
+		}
+		b.WriteString("\n## Issues by namespace\n\n| Namespace | Issues |\n|---|---|\n")
+		for _, ns := range sortedKeys(last.ByNamespace) {
+			fmt.Fprintf(&b, "| %s | %d |\n", escapeMarkdownCell(ns), last.ByNamespace[ns])
+		}
+	}
+
+	b.WriteString("\n## Regressions\n\n")
+	if len(t.Regressions) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, r := range t.Regressions {
+		fmt.Fprintf(&b, "- %s `%s`: %d -> %d\n", r.Kind, r.Key, r.Previous, r.Current)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
@@ -0,0 +1,83 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildTrend(t *testing.T) {
+	base := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	reports := []*Report{
+		{
+			Timestamp: base,
+			Results: []*LintResult{
+				{MetricName: "apiserver_request_total", Issues: []string{LintErrMsgNoHelp}},
+			},
+		},
+		{
+			Timestamp: base.Add(24 * time.Hour),
+			Results: []*LintResult{
+				{MetricName: "apiserver_request_total", Issues: []string{LintErrMsgNoHelp}},
+				{MetricName: "kubelet_ms_total", Issues: []string{LintErrMsgNameShouldNotHaveAbbr}},
+			},
+		},
+	}
+
+	trend := buildTrend(reports, base, base.Add(7*24*time.Hour))
+	if len(trend.Points) != 2 {
+		t.Fatalf("expected 2 points, but got: %d", len(trend.Points))
+	}
+	if trend.Points[1].Total != 2 || trend.Points[1].ByNamespace["kubelet"] != 1 {
+		t.Errorf("unexpected last point: %+v", trend.Points[1])
+	}
+
+	expected := []Regression{
+		{Kind: "rule", Key: LintErrMsgNameShouldNotHaveAbbr, Previous: 0, Current: 1},
+		{Kind: "namespace", Key: "kubelet", Previous: 0, Current: 1},
+	}
+	if len(trend.Regressions) != len(expected) {
+		t.Fatalf("expected regressions: %v, but got: %v", expected, trend.Regressions)
+	}
+	for i := range expected {
+		if trend.Regressions[i] != expected[i] {
+			t.Errorf("expected: %v, but got: %v", expected[i], trend.Regressions[i])
+		}
+	}
+
+	var md bytes.Buffer
+	if err := trend.WriteMarkdown(&md); err != nil {
+		t.Fatalf("failed to render markdown: %v", err)
+	}
+	if !strings.Contains(md.String(), "- namespace `kubelet`: 0 -> 1") {
+		t.Errorf("markdown misses regression:\n%s", md.String())
+	}
+
+	var js bytes.Buffer
+	if err := trend.WriteJSON(&js); err != nil {
+		t.Fatalf("failed to render json: %v", err)
+	}
+	if !strings.Contains(js.String(), `"regressions"`) {
+		t.Errorf("json misses regressions:\n%s", js.String())
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+// MetricType is the type of a metric. It mirrors the Prometheus metric types without
+// depending on the client_model protobuf types, so the core rules stay lightweight.
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeSummary   MetricType = "summary"
+	MetricTypeUntyped   MetricType = "untyped"
+)
+
+// typedMetricTypes are the metric types a metric name should not mention.
+var typedMetricTypes = []MetricType{
+	MetricTypeCounter,
+	MetricTypeGauge,
+	MetricTypeHistogram,
+	MetricTypeSummary,
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	LintErrMsgSuffixTypo = `%q looks like a typo of %q`
+)
+
+// Well known suffixes which are checked for typos in addition to the units.
+var knownSuffixes = []string{
+	"total",
+	"count",
+	"sum",
+	"bucket",
+	"info",
+	"ratio",
+	"created",
+	"timestamp",
+}
+
+// commonWords are words of metric names within a typo of a unit or a suffix, which are not typos, e.g.
+// "hits" of "bits" or "files" of "miles".
+var commonWords = map[string]bool{
+	"bites": true,
+	"bolts": true,
+	"bots":  true,
+	"cards": true,
+	"files": true,
+	"hits":  true,
+	"into":  true,
+	"kits":  true,
+	"mount": true,
+	"radio": true,
+	"seeks": true,
+	"tiles": true,
+	"ways":  true,
+}
+
+// isSuffixPlural reports whether the segment is the plural of a known suffix, e.g. "buckets".
+func isSuffixPlural(segment string) bool {
+	word := strings.TrimSuffix(segment, "s")
+	for _, suffix := range knownSuffixes {
+		if word == suffix && word != segment {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintSuffixTypo detects near-misses of known suffixes and units, e.g. "_secconds" or "_totol".
+// Only the last segment, and the one before "_total", are checked since that's where units and
+// suffixes live; checking every segment would flag ordinary words. Common words and plurals of the
+// suffixes close to a unit or a suffix are not reported.
+func lintSuffixTypo(name string) (issues []string) {
+	segments := strings.Split(strings.ToLower(name), "_")
+	candidates := []string{segments[len(segments)-1]}
+	if len(segments) > 1 && candidates[0] == "total" {
+		candidates = []string{segments[len(segments)-2]}
+	}
+
+	units := defaultUnits
+	for _, segment := range candidates {
+		if len(segment) < 4 || units.isUnit(segment) || commonWords[segment] || isSuffixPlural(segment) {
+			continue
+		}
+		if word, ok := units.typo(segment); ok {
+			issues = append(issues, fmt.Sprintf(LintErrMsgSuffixTypo, segment, word))
+		}
+	}
+
+	return issues
+}
+
+// closestWord returns the word of the sorted vocabulary the segment most likely is a typo of, or "" if
+// the segment is a word of the vocabulary or isn't close to any.
+func closestWord(segment string, vocabulary []string) string {
+	if i := sort.SearchStrings(vocabulary, segment); i < len(vocabulary) && vocabulary[i] == segment {
+		return ""
+	}
+
+	maxDistance := 1
+	if len(segment) >= 8 {
+		maxDistance = 2
+	}
+	// Prefer the closest word, and among equally close words the one sharing the longest prefix,
+	// e.g. "milliseconds" rather than "mibiseconds" for "miliseconds".
+	best, bestDistance, bestPrefix := "", maxDistance+1, 0
+	for _, word := range vocabulary {
+		// The distance is at least the difference of the lengths, which rules out most words cheaply.
+		if len(word)-len(segment) > maxDistance || len(segment)-len(word) > maxDistance {
+			continue
+		}
+		d, p := editDistance(segment, word), commonPrefixLen(segment, word)
+		if d > maxDistance {
+			continue
+		}
+		if d < bestDistance || (d == bestDistance && p > bestPrefix) {
+			best, bestDistance, bestPrefix = word, d, p
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
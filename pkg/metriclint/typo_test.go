@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLintSuffixTypo(t *testing.T) {
+	tests := []struct {
+		metric         string
+		expectedResult string
+	}{
+		{metric: "lint_test_secconds", expectedResult: fmt.Sprintf(LintErrMsgSuffixTypo, "secconds", "seconds")},
+		{metric: "lint_test_totol", expectedResult: fmt.Sprintf(LintErrMsgSuffixTypo, "totol", "total")},
+		{metric: "lint_test_bytess_total", expectedResult: fmt.Sprintf(LintErrMsgSuffixTypo, "bytess", "bytes")},
+		{metric: "lint_test_miliseconds", expectedResult: fmt.Sprintf(LintErrMsgSuffixTypo, "miliseconds", "milliseconds")},
+		{metric: "lint_test_seconds"},
+		{metric: "lint_test_bytes_total"},
+		{metric: "lint_test_numbers"},
+		{metric: "lint_counter_total"},
+		{metric: "cache_hits_total"},
+		{metric: "open_files"},
+		{metric: "request_counts"},
+		{metric: "histogram_buckets"},
+		{metric: "job_totals"},
+		{metric: "value_ratios"},
+		{metric: "span_sums"},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.metric, func(t *testing.T) {
+			issues := strings.Join(lintSuffixTypo(tc.metric), ",")
+			if tc.expectedResult != issues {
+				t.Errorf("expected: %s, but got: %s", tc.expectedResult, issues)
+			}
+		})
+	}
+}
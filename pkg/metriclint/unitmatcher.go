@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"sort"
+	"sync"
+)
+
+// typoCacheSize bounds the number of name segments whose typo suggestion a unitMatcher remembers, so that
+// linting the names of arbitrary scrapes in a long running process doesn't grow its cache without bounds.
+const typoCacheSize = 4096
+
+// defaultUnits recognizes the built-in units and the ones added with AddUnit and AddUnitPrefix.
+var defaultUnits = newUnitMatcher(units)
+
+// compileUnits rebuilds defaultUnits after the units or their prefixes changed.
+func compileUnits() {
+	defaultUnits = newUnitMatcher(units)
+}
+
+// unitMatcher recognizes the units with any known prefix with a single lookup per name segment, instead
+// of trying every unit with every prefix, and remembers the typo suggestions of the segments it checked. It's
+// safe for concurrent use.
+type unitMatcher struct {
+	// bases maps every unit, with and without prefix, to its base unit.
+	bases map[string]string
+	// vocabulary is the known suffixes and the prefixed units, sorted, see lintSuffixTypo.
+	vocabulary []string
+
+	mu    sync.Mutex
+	typos map[string]string
+}
+
+// newUnitMatcher compiles the units, mapped to their base unit, with the current unit prefixes.
+func newUnitMatcher(units map[string]string) *unitMatcher {
+	m := &unitMatcher{
+		bases: make(map[string]string, len(units)*(len(unitPrefixes)+1)),
+		typos: map[string]string{},
+	}
+	for unit, base := range units {
+		for _, p := range unitPrefixes {
+			m.bases[p+unit] = base
+		}
+	}
+	// A unit spelled like a prefixed unit, e.g. a custom "kilometers", keeps its own base.
+	for unit, base := range units {
+		m.bases[unit] = base
+	}
+
+	m.vocabulary = append([]string{}, knownSuffixes...)
+	for unit := range m.bases {
+		m.vocabulary = append(m.vocabulary, unit)
+	}
+	sort.Strings(m.vocabulary)
+
+	return m
+}
+
+// detect returns the first "_" separated segment of the name which is a unit, see DetectUnit. Whole
+// segments are matched, as some words look like units when matching a suffix: "thermometers" should not
+// match "meters", but "kilometers" should.
+func (m *unitMatcher) detect(name string) (unit string, base string, ok bool) {
+	for start := 0; start <= len(name); {
+		end := start
+		for end < len(name) && name[end] != '_' {
+			end++
+		}
+		if base, ok := m.bases[name[start:end]]; ok {
+			return name[start:end], base, true
+		}
+		start = end + 1
+	}
+
+	return "", "", false
+}
+
+// isUnit reports whether s is a unit with an optional prefix, e.g. "milliseconds".
+func (m *unitMatcher) isUnit(s string) bool {
+	_, ok := m.bases[s]
+	return ok
+}
+
+// typo returns the word of the vocabulary the segment looks like a typo of, computed once per segment.
+func (m *unitMatcher) typo(segment string) (word string, ok bool) {
+	m.mu.Lock()
+	word, ok = m.typos[segment]
+	m.mu.Unlock()
+	if ok {
+		return word, word != ""
+	}
+
+	word = closestWord(segment, m.vocabulary)
+
+	m.mu.Lock()
+	if len(m.typos) >= typoCacheSize {
+		m.typos = map[string]string{}
+	}
+	m.typos[segment] = word
+	m.mu.Unlock()
+
+	return word, word != ""
+}
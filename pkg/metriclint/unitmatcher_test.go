@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUnitMatcher(t *testing.T) {
+	m := newUnitMatcher(map[string]string{"meters": "meters", "kilometers": "kilometers"})
+
+	var tests = []struct {
+		metric string
+		unit   string
+		base   string
+	}{
+		{metric: "distance_kilometers", unit: "kilometers", base: "kilometers"},
+		{metric: "distance_millimeters", unit: "millimeters", base: "meters"},
+		{metric: "thermometers_count", unit: "", base: ""},
+		{metric: "distance_meters_", unit: "meters", base: "meters"},
+		{metric: "", unit: "", base: ""},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.metric, func(t *testing.T) {
+			unit, base, ok := m.detect(tc.metric)
+			if unit != tc.unit || base != tc.base || ok != (tc.unit != "") {
+				t.Errorf("expected: %s/%s, but got: %s/%s/%v", tc.unit, tc.base, unit, base, ok)
+			}
+		})
+	}
+}
+
+func TestUnitMatcherTypoCache(t *testing.T) {
+	m := newUnitMatcher(units)
+	for i := 0; i < typoCacheSize+10; i++ {
+		m.typo(fmt.Sprintf("segment%d", i))
+	}
+	if len(m.typos) > typoCacheSize {
+		t.Errorf("expected: at most %d cached typos, but got: %d", typoCacheSize, len(m.typos))
+	}
+
+	for i := 0; i < 2; i++ {
+		if word, ok := m.typo("secconds"); !ok || word != "seconds" {
+			t.Errorf("expected: seconds, but got: %s", word)
+		}
+	}
+}
+
+// registrationSpecs returns the specs of a process registering many metrics at startup.
+func registrationSpecs() []MetricSpec {
+	var specs []MetricSpec
+	for i := 0; i < 100; i++ {
+		specs = append(specs,
+			MetricSpec{Name: fmt.Sprintf("component_%d_requests_total", i), Help: "Number of requests.", Type: MetricTypeCounter, VariableLabels: []string{"code", "method"}},
+			MetricSpec{Name: fmt.Sprintf("component_%d_request_duration_milliseconds", i), Help: "Duration of requests.", Type: MetricTypeHistogram},
+			MetricSpec{Name: fmt.Sprintf("component_%d_queue_lenght", i), Help: "Length of the queue.", Type: MetricTypeGauge},
+		)
+	}
+
+	return specs
+}
+
+func BenchmarkDetectUnit(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		DetectUnit("apiserver_request_duration_milliseconds_bucket")
+	}
+}
+
+func BenchmarkLintRegistration(b *testing.B) {
+	specs := registrationSpecs()
+	l := NewLinter()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, spec := range specs {
+			l.Lint(spec)
+		}
+	}
+}
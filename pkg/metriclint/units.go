@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "fmt"
+
+// AddUnit teaches the unit detector a domain-specific unit, such as "packets" or "cores", and the base unit it
+// should be expressed in. A base unit maps to itself, e.g. AddUnit("cores", "cores"). Units take the known
+// prefixes, "millicores" is recognized once "cores" is added.
+//
+// It's meant to be called from an init function, before any linting, and isn't safe for concurrent use with
+// the linters. It panics if the unit isn't a lowercase name segment, if the base is neither the unit nor a known
+// base unit, or if the unit is already known with another base. The WithUnits option adds units to a single linter.
+func AddUnit(unit, base string) {
+	if !unitRE.MatchString(unit) {
+		panic(fmt.Sprintf("metriclint: unit %q should be a lowercase name segment", unit))
+	}
+	if base != unit && units[base] != base {
+		panic(fmt.Sprintf("metriclint: unit %q maps to %q, which is not a base unit", unit, base))
+	}
+	if known, ok := units[unit]; ok && known != base {
+		panic(fmt.Sprintf("metriclint: unit %q is already known with base unit %q", unit, known))
+	}
+
+	units[unit] = base
+	compileUnits()
+}
+
+// AddUnitPrefix teaches the unit detector a prefix of the units, such as "mebi". It's meant to be called from
+// an init function like AddUnit, and panics if the prefix isn't a lowercase name segment.
+func AddUnitPrefix(prefix string) {
+	if !unitRE.MatchString(prefix) {
+		panic(fmt.Sprintf("metriclint: unit prefix %q should be a lowercase name segment", prefix))
+	}
+	for _, p := range unitPrefixes {
+		if p == prefix {
+			return
+		}
+	}
+
+	unitPrefixes = append(unitPrefixes, prefix)
+	compileUnits()
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "testing"
+
+func TestAddUnit(t *testing.T) {
+	defer func() {
+		delete(units, "lintcores")
+		unitPrefixes = unitPrefixes[:len(unitPrefixes)-1]
+		compileUnits()
+	}()
+
+	if _, _, ok := DetectUnit("container_cpu_lintcores"); ok {
+		t.Fatalf("expected unknown unit")
+	}
+
+	AddUnit("lintcores", "lintcores")
+	AddUnitPrefix("lintmilli")
+	AddUnitPrefix("lintmilli")
+
+	var tests = []struct {
+		metric string
+		unit   string
+		base   string
+	}{
+		{metric: "container_cpu_lintcores", unit: "lintcores", base: "lintcores"},
+		{metric: "container_cpu_millilintcores", unit: "millilintcores", base: "lintcores"},
+		{metric: "request_duration_lintmilliseconds", unit: "lintmilliseconds", base: "seconds"},
+	}
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.metric, func(t *testing.T) {
+			unit, base, ok := DetectUnit(tc.metric)
+			if !ok || unit != tc.unit || base != tc.base {
+				t.Errorf("expected: %s %s, but got: %s %s %v", tc.unit, tc.base, unit, base, ok)
+			}
+		})
+	}
+}
+
+func TestAddUnitInvalid(t *testing.T) {
+	var tests = []struct {
+		name string
+		add  func()
+	}{
+		{name: "not a segment", add: func() { AddUnit("Cores", "Cores") }},
+		{name: "not a base unit", add: func() { AddUnit("lintkibibytes", "kilobytes") }},
+		{name: "other base", add: func() { AddUnit("seconds", "bytes") }},
+		{name: "prefix not a segment", add: func() { AddUnitPrefix("mebi_") }},
+	}
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			tc.add()
+		})
+	}
+}
@@ -0,0 +1,233 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LintErrMsgPossiblyUnused  = "not referenced by any rule and constant over %s, it may be unused"
+	LintErrMsgConstantSeries  = "%d of %d series not referenced by any rule and constant over %s, e.g. %s"
+	maxConstantSeriesExamples = 3
+)
+
+var identifierPattern = regexp.MustCompile(`[a-zA-Z_:][a-zA-Z0-9_:]*`)
+
+// PrometheusAPI queries the HTTP API of a Prometheus server.
+type PrometheusAPI struct {
+	// URL of the server, e.g. http://prometheus:9090.
+	URL string
+
+	// Client sending the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// apiResponse is the envelope of the responses of the HTTP API.
+type apiResponse struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+	Error  string          `json:"error"`
+}
+
+// apiSample is a sample of an instant vector.
+type apiSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+// get sends a GET request to the API path and decodes the data of the response into v.
+func (a *PrometheusAPI) get(path string, params url.Values, v interface{}) error {
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	u := strings.TrimRight(a.URL, "/") + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	resp, err := client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return err
+	}
+
+	var r apiResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("%s: unexpected response with status %s", path, resp.Status)
+	}
+	if r.Status != "success" {
+		return fmt.Errorf("%s: %s", path, r.Error)
+	}
+
+	return json.Unmarshal(r.Data, v)
+}
+
+// RuleQueries returns the expressions of the recording and alerting rules loaded by the server.
+func (a *PrometheusAPI) RuleQueries() ([]string, error) {
+	var data struct {
+		Groups []struct {
+			Rules []struct {
+				Query string `json:"query"`
+			} `json:"rules"`
+		} `json:"groups"`
+	}
+	if err := a.get("/api/v1/rules", nil, &data); err != nil {
+		return nil, err
+	}
+
+	var queries []string
+	for _, group := range data.Groups {
+		for _, rule := range group.Rules {
+			queries = append(queries, rule.Query)
+		}
+	}
+
+	return queries, nil
+}
+
+// changes returns the number of value changes over the window of every series of the metric.
+// Histograms and summaries are queried by their _count series.
+func (a *PrometheusAPI) changes(spec MetricSpec, window time.Duration) ([]apiSample, error) {
+	name := spec.FQName()
+	if spec.Type == MetricTypeHistogram || spec.Type == MetricTypeSummary {
+		name += "_count"
+	}
+	query := fmt.Sprintf(`changes({__name__=%q}[%ds])`, name, int64(window/time.Second))
+
+	var data struct {
+		Result []apiSample `json:"result"`
+	}
+	if err := a.get("/api/v1/query", url.Values{"query": {query}}, &data); err != nil {
+		return nil, err
+	}
+
+	return data.Result, nil
+}
+
+// LintUsage reports the metrics of a lint target which look unused on a Prometheus server, with the
+// default rules, see Linter.LintUsage.
+func LintUsage(api *PrometheusAPI, specs []MetricSpec, window time.Duration) ([]*LintResult, error) {
+	return NewLinter().LintUsage(api, specs, window)
+}
+
+// LintUsage reports with RulePossiblyUnusedMetric the metrics, and the label combinations of the
+// metrics, which none of the recording or alerting rules of the server refers to and whose value
+// didn't change over the window. The server doesn't record the queries of dashboards, so the
+// findings are advisory. Metrics without series on the server are left out, as are the metrics
+// without issue.
+func (l *Linter) LintUsage(api *PrometheusAPI, specs []MetricSpec, window time.Duration) ([]*LintResult, error) {
+	queries, err := api.RuleQueries()
+	if err != nil {
+		return nil, err
+	}
+	referenced := map[string]bool{}
+	for _, query := range queries {
+		for _, name := range identifierPattern.FindAllString(query, -1) {
+			referenced[name] = true
+		}
+	}
+
+	var results []*LintResult
+	for _, spec := range specs {
+		name := spec.FQName()
+		if l.ignored(name) || isReferenced(referenced, name) {
+			continue
+		}
+
+		samples, err := api.changes(spec, window)
+		if err != nil {
+			return nil, err
+		}
+		var constant []string
+		for _, sample := range samples {
+			if s, ok := sample.Value[1].(string); ok {
+				if changes, err := strconv.ParseFloat(s, 64); err == nil && changes == 0 {
+					constant = append(constant, labelSet(sample.Metric))
+				}
+			}
+		}
+
+		var message string
+		switch {
+		case len(constant) == 0:
+			continue
+		case len(constant) == len(samples):
+			message = fmt.Sprintf(LintErrMsgPossiblyUnused, window)
+		default:
+			count := len(constant)
+			sort.Strings(constant)
+			if count > maxConstantSeriesExamples {
+				constant = constant[:maxConstantSeriesExamples]
+			}
+			message = fmt.Sprintf(LintErrMsgConstantSeries, count, len(samples), window, strings.Join(constant, ", "))
+		}
+
+		result := &LintResult{MetricName: name}
+		l.AddRuleMessages(result, RulePossiblyUnusedMetric, message)
+		if len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// isReferenced reports whether a rule refers to the metric or to one of its series.
+func isReferenced(referenced map[string]bool, name string) bool {
+	for _, suffix := range []string{"", "_bucket", "_sum", "_count"} {
+		if referenced[name+suffix] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// labelSet formats labels as a sorted {name="value"} set.
+func labelSet(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,87 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLintUsage(t *testing.T) {
+	changes := map[string]string{
+		"http_requests_total":            `[{"metric":{"code":"200"},"value":[1,"12"]}]`,
+		"legacy_cache_hits_total":        `[{"metric":{},"value":[1,"0"]}]`,
+		"queue_items":                    `[{"metric":{"queue":"a"},"value":[1,"3"]},{"metric":{"queue":"b"},"value":[1,"0"]}]`,
+		"request_duration_seconds_count": `[{"metric":{},"value":[1,"0"]}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/rules":
+			fmt.Fprint(w, `{"status":"success","data":{"groups":[{"rules":[{"query":"rate(request_duration_seconds_bucket[5m])"}]}]}}`)
+		case "/api/v1/query":
+			query := r.URL.Query().Get("query")
+			for name, result := range changes {
+				if strings.Contains(query, fmt.Sprintf("%q", name)) {
+					fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+					return
+				}
+			}
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status":"error","errorType":"not_found","error":"not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	specs := []MetricSpec{
+		{Name: "http_requests_total", Type: MetricTypeCounter},
+		{Name: "legacy_cache_hits_total", Type: MetricTypeCounter},
+		{Name: "queue_items", Type: MetricTypeGauge},
+		{Name: "request_duration_seconds", Type: MetricTypeHistogram},
+		{Name: "missing_total", Type: MetricTypeCounter},
+	}
+	window := 7 * 24 * time.Hour
+	results, err := LintUsage(&PrometheusAPI{URL: server.URL}, specs, window)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"legacy_cache_hits_total:" + fmt.Sprintf(LintErrMsgPossiblyUnused, window),
+		"queue_items:" + fmt.Sprintf(LintErrMsgConstantSeries, 1, 2, window, `{queue="b"}`),
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v, but got: %v", expected, results)
+	}
+	for i, result := range results {
+		if result.String() != expected[i] || result.Findings[0].ID != RulePossiblyUnusedMetric {
+			t.Errorf("expected: %s, but got: %v", expected[i], result)
+		}
+	}
+
+	if _, err := LintUsage(&PrometheusAPI{URL: server.URL + "/missing"}, specs, window); err == nil {
+		t.Errorf("expected an error for a failing API")
+	}
+}
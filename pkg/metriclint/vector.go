@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import "fmt"
+
+// DefaultMaxVectorLabels is the default number of label names a vector may have.
+const DefaultMaxVectorLabels = 10
+
+const (
+	LintErrMsgVectorNoLabels       = `vector has no label names, use a plain metric instead`
+	LintErrMsgVectorEmptyLabel     = `label name at index %d is empty`
+	LintErrMsgVectorLabelsBudget   = `vector has %d label names, more than the budget of %d`
+	LintErrMsgHistogramLeLabel     = `histogram vectors should not have "le" label, it's generated for the buckets`
+	LintErrMsgSummaryQuantileLabel = `summary vectors should not have "quantile" label, it's generated for the quantiles`
+)
+
+// VectorLabelsRule validates the label names passed to a vector constructor. It reports all
+// problems at once, with the index of every bad entry, rather than the first one only.
+type VectorLabelsRule struct {
+	// Maximum number of label names, DefaultMaxVectorLabels if zero.
+	MaxLabels int
+}
+
+// Lint returns the issues of the label names of a vector.
+func (r VectorLabelsRule) Lint(labelNames []string) (issues []string) {
+	if len(labelNames) == 0 {
+		return []string{LintErrMsgVectorNoLabels}
+	}
+
+	for i, name := range labelNames {
+		if name == "" {
+			issues = append(issues, fmt.Sprintf(LintErrMsgVectorEmptyLabel, i))
+		}
+	}
+
+	max := r.MaxLabels
+	if max == 0 {
+		max = DefaultMaxVectorLabels
+	}
+	if len(labelNames) > max {
+		issues = append(issues, fmt.Sprintf(LintErrMsgVectorLabelsBudget, len(labelNames), max))
+	}
+
+	return issues
+}
+
+// lintGeneratedLabels reports the variable labels of a histogram or summary vector named like the label
+// client_golang generates for their series, "le" and "quantile", on which their constructor panics.
+func lintGeneratedLabels(spec MetricSpec, result *LintResult) {
+	for _, name := range spec.VariableLabels {
+		switch {
+		case spec.Type == MetricTypeHistogram && name == LabelLe:
+			result.AddRuleMessages(RuleHistogramLeLabel, LintErrMsgHistogramLeLabel)
+		case spec.Type == MetricTypeSummary && name == LabelQuantile:
+			result.AddRuleMessages(RuleSummaryQuantileLabel, LintErrMsgSummaryQuantileLabel)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVectorLabelsRule(t *testing.T) {
+	tooMany := make([]string, DefaultMaxVectorLabels+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("l%d", i)
+	}
+
+	var tests = []struct {
+		name       string
+		rule       VectorLabelsRule
+		labelNames []string
+		expected   []string
+	}{
+		{
+			name:       "valid",
+			labelNames: []string{"code", "method"},
+		},
+		{
+			name:     "nil label names",
+			expected: []string{LintErrMsgVectorNoLabels},
+		},
+		{
+			name:       "empty label names",
+			labelNames: []string{},
+			expected:   []string{LintErrMsgVectorNoLabels},
+		},
+		{
+			name:       "all problems at once",
+			rule:       VectorLabelsRule{MaxLabels: 2},
+			labelNames: []string{"", "code", ""},
+			expected: []string{
+				fmt.Sprintf(LintErrMsgVectorEmptyLabel, 0),
+				fmt.Sprintf(LintErrMsgVectorEmptyLabel, 2),
+				fmt.Sprintf(LintErrMsgVectorLabelsBudget, 3, 2),
+			},
+		},
+		{
+			name:       "default budget",
+			labelNames: tooMany,
+			expected:   []string{fmt.Sprintf(LintErrMsgVectorLabelsBudget, DefaultMaxVectorLabels+1, DefaultMaxVectorLabels)},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			issues := tc.rule.Lint(tc.labelNames)
+			if fmt.Sprint(issues) != fmt.Sprint(tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, issues)
+			}
+		})
+	}
+}
+
+func TestLintVectorGeneratedLabels(t *testing.T) {
+	var tests = []struct {
+		name     string
+		spec     MetricSpec
+		expected []string
+	}{
+		{
+			name:     "histogram le",
+			spec:     MetricSpec{Name: "request_duration_seconds", Help: "Duration of requests.", Type: MetricTypeHistogram, VariableLabels: []string{"code", "le"}},
+			expected: []string{RuleHistogramLeLabel},
+		},
+		{
+			name:     "summary quantile",
+			spec:     MetricSpec{Name: "request_duration_seconds", Help: "Duration of requests.", Type: MetricTypeSummary, VariableLabels: []string{"quantile"}},
+			expected: []string{RuleSummaryQuantileLabel},
+		},
+		{
+			name:     "histogram quantile",
+			spec:     MetricSpec{Name: "request_duration_seconds", Help: "Duration of requests.", Type: MetricTypeHistogram, VariableLabels: []string{"quantile"}},
+			expected: []string{RuleNonSummaryQuantileLabel},
+		},
+		{
+			name:     "gauge le",
+			spec:     MetricSpec{Name: "queue_length", Help: "Length of the queue.", Type: MetricTypeGauge, VariableLabels: []string{"le"}},
+			expected: []string{RuleNonHistogramLeLabel},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			var ids []string
+			for _, issue := range NewLinter().LintVector(tc.spec).Findings {
+				ids = append(ids, issue.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tc.expected) {
+				t.Errorf("expected: %v, but got: %v", tc.expected, ids)
+			}
+		})
+	}
+}
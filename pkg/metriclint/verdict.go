@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Verdict is a set of results judged against the fail-on threshold of a linter, see FailOn.
+type Verdict struct {
+	Results Results
+
+	// Severity from which issues fail the results.
+	FailOn Severity
+
+	// Number of issues at or above FailOn.
+	Failing int
+}
+
+// Failed reports whether an issue is at or above the threshold.
+func (v Verdict) Failed() bool {
+	return v.Failing > 0
+}
+
+// Judge counts the issues of the results at or above the fail-on threshold of the linter, e.g. to
+// decide the exit code of a CI job.
+func (l *Linter) Judge(results []*LintResult) Verdict {
+	failOn := l.failOn
+	if failOn == "" {
+		failOn = SeverityError
+	}
+
+	v := Verdict{Results: results, FailOn: failOn}
+	for _, r := range results {
+		for _, issue := range r.findings() {
+			if issue.Severity.AtLeast(failOn) {
+				v.Failing++
+			}
+		}
+	}
+
+	return v
+}
+
+// Summary returns the verdict alone, e.g. "FAIL: 2 issues at or above warning".
+func (v Verdict) Summary() string {
+	if v.Failed() {
+		return fmt.Sprintf("FAIL: %d issues at or above %s", v.Failing, v.FailOn)
+	}
+
+	return fmt.Sprintf("PASS: no issue at or above %s", v.FailOn)
+}
+
+// String renders the results like Results.String, followed by the verdict, see Summary.
+func (v Verdict) String() string {
+	return v.Results.String() + "\n" + v.Summary()
+}
+
+// MarshalJSON encodes the verdict like Results, with the threshold and whether the results exceed it,
+// e.g. {"issueCount":1,"results":[...],"failOn":"error","failed":true}.
+func (v Verdict) MarshalJSON() ([]byte, error) {
+	results := []*LintResult(v.Results)
+	if results == nil {
+		results = []*LintResult{}
+	}
+
+	return json.Marshal(struct {
+		IssueCount int           `json:"issueCount"`
+		Results    []*LintResult `json:"results"`
+		FailOn     Severity      `json:"failOn"`
+		Failed     bool          `json:"failed"`
+	}{v.Results.IssueCount(), results, v.FailOn, v.Failed()})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriclint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLinterJudge(t *testing.T) {
+	specs := []MetricSpec{
+		{Name: "jobs_total", Help: "Number of jobs.", Type: MetricTypeCounter},
+		{Name: "job_success_percent", Help: "Share of successful jobs.", Type: MetricTypeGauge},
+	}
+
+	var tests = []struct {
+		name    string
+		opts    []Option
+		failOn  Severity
+		failing int
+	}{
+		{
+			name:   "default threshold",
+			failOn: SeverityError,
+		},
+		{
+			name:    "fail on warnings",
+			opts:    []Option{FailOn(SeverityWarning)},
+			failOn:  SeverityWarning,
+			failing: 1,
+		},
+		{
+			name:    "treated as error",
+			opts:    []Option{Treat(RulePercentName, SeverityError)},
+			failOn:  SeverityError,
+			failing: 1,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLinter(tc.opts...)
+			results, err := l.LintAll(specs...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			v := l.Judge(results)
+			if v.FailOn != tc.failOn || v.Failing != tc.failing || v.Failed() != (tc.failing > 0) {
+				t.Errorf("expected: %s %d, but got: %s %d", tc.failOn, tc.failing, v.FailOn, v.Failing)
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var decoded struct {
+				IssueCount int      `json:"issueCount"`
+				FailOn     Severity `json:"failOn"`
+				Failed     bool     `json:"failed"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decoded.IssueCount != 1 || decoded.FailOn != tc.failOn || decoded.Failed != v.Failed() {
+				t.Errorf("expected: %s %t, but got: %s", tc.failOn, v.Failed(), data)
+			}
+
+			verdict := "PASS: no issue at or above " + string(tc.failOn)
+			if v.Failed() {
+				verdict = "FAIL: 1 issues at or above " + string(tc.failOn)
+			}
+			if s := v.String(); !strings.HasSuffix(s, "\n"+verdict) {
+				t.Errorf("expected: %s, but got: %s", verdict, s)
+			}
+		})
+	}
+}
+
+func TestFailOnUnknownSeverity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for an unknown severity")
+		}
+	}()
+	NewLinter(FailOn("fatal"))
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	var tests = []struct {
+		severity  Severity
+		threshold Severity
+		expected  bool
+	}{
+		{SeverityError, SeverityError, true},
+		{SeverityError, SeverityWarning, true},
+		{SeverityWarning, SeverityError, false},
+		{SeverityWarning, SeverityWarning, true},
+		{"", SeverityWarning, false},
+	}
+
+	for _, tc := range tests {
+		if got := tc.severity.AtLeast(tc.threshold); got != tc.expected {
+			t.Errorf("%q at least %q: expected: %t, but got: %t", tc.severity, tc.threshold, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report renders metriclint reports into output formats.
+//
+// Formats are looked up by name from a registry, so third parties can add their own
+// format with RegisterFormat and have it selectable by name, e.g. by the CLI's --format flag.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// OutputWriter renders a report to w.
+type OutputWriter interface {
+	Write(w io.Writer, report *metriclint.Report) error
+}
+
+// OutputWriterFunc adapts an ordinary function to an OutputWriter.
+type OutputWriterFunc func(w io.Writer, report *metriclint.Report) error
+
+// Write calls f(w, report).
+func (f OutputWriterFunc) Write(w io.Writer, report *metriclint.Report) error {
+	return f(w, report)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]OutputWriter{}
+)
+
+// RegisterFormat makes an output format available by the provided name.
+// If RegisterFormat is called twice with the same name or if writer is nil, it panics.
+func RegisterFormat(name string, writer OutputWriter) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	if writer == nil {
+		panic("report: RegisterFormat writer is nil")
+	}
+	if _, dup := formats[name]; dup {
+		panic("report: RegisterFormat called twice for format " + name)
+	}
+	formats[name] = writer
+}
+
+// Lookup returns the writer registered for the format name.
+func Lookup(name string) (OutputWriter, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	writer, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (available: %v)", name, formatNames())
+	}
+
+	return writer, nil
+}
+
+// Formats returns a sorted list of the names of the registered formats.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	return formatNames()
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Write renders the report in the named format.
+func Write(w io.Writer, format string, report *metriclint.Report) error {
+	writer, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(w, report)
+}
+
+func init() {
+	RegisterFormat("text", OutputWriterFunc(writeText))
+	RegisterFormat("json", OutputWriterFunc(writeJSON))
+}
+
+// writeText writes one line per metric having issues, then one line per tombstoned metric, then the
+// verdict if the report was judged.
+func writeText(w io.Writer, report *metriclint.Report) error {
+	for _, result := range report.Results {
+		if len(result.Issues) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, result.String()); err != nil {
+			return err
+		}
+	}
+	for _, t := range report.Tombstoned {
+		if _, err := fmt.Fprintf(w, "%s:scheduled for removal\n", t.Metric); err != nil {
+			return err
+		}
+	}
+	if report.Verdict != nil {
+		if _, err := fmt.Fprintln(w, report.Verdict.Summary()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSON encodes the report, with the FailOn severity and whether it Failed if the report was judged.
+func writeJSON(w io.Writer, report *metriclint.Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if report.Verdict == nil {
+		return encoder.Encode(report)
+	}
+
+	return encoder.Encode(struct {
+		*metriclint.Report
+		FailOn metriclint.Severity
+		Failed bool
+	}{report, report.Verdict.FailOn, report.Verdict.Failed()})
+}
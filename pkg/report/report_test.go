@@ -0,0 +1,126 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("test-count", OutputWriterFunc(func(w io.Writer, report *metriclint.Report) error {
+		_, err := fmt.Fprintf(w, "%d", len(report.Results))
+		return err
+	}))
+
+	report := &metriclint.Report{
+		Results: []*metriclint.LintResult{
+			{MetricName: "lint_test_total"},
+			{MetricName: "lint_test", Issues: []string{metriclint.LintErrMsgCounterShouldHaveTotalSuffix}},
+		},
+	}
+
+	tests := []struct {
+		format         string
+		expectedResult string
+	}{
+		{
+			format:         "test-count",
+			expectedResult: "2",
+		},
+		{
+			format:         "text",
+			expectedResult: fmt.Sprintf("lint_test:%s\n", metriclint.LintErrMsgCounterShouldHaveTotalSuffix),
+		},
+	}
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tc.format, report); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tc.expectedResult {
+				t.Errorf("expected: %q, but got: %q", tc.expectedResult, buf.String())
+			}
+		})
+	}
+
+	if _, err := Lookup("unknown"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestWriteVerdict(t *testing.T) {
+	report := &metriclint.Report{
+		Results: []*metriclint.LintResult{
+			{MetricName: "lint_test", Findings: []metriclint.Issue{{ID: metriclint.RuleCounterTotalSuffix, Message: metriclint.LintErrMsgCounterShouldHaveTotalSuffix, Severity: metriclint.SeverityWarning}}},
+		},
+	}
+	report.Results[0].Issues = []string{metriclint.LintErrMsgCounterShouldHaveTotalSuffix}
+
+	tests := []struct {
+		name           string
+		format         string
+		failOn         metriclint.Severity
+		expectedResult string
+	}{
+		{
+			name:           "text pass",
+			format:         "text",
+			failOn:         metriclint.SeverityError,
+			expectedResult: fmt.Sprintf("lint_test:%s\nPASS: no issue at or above error\n", metriclint.LintErrMsgCounterShouldHaveTotalSuffix),
+		},
+		{
+			name:           "text fail",
+			format:         "text",
+			failOn:         metriclint.SeverityWarning,
+			expectedResult: fmt.Sprintf("lint_test:%s\nFAIL: 1 issues at or above warning\n", metriclint.LintErrMsgCounterShouldHaveTotalSuffix),
+		},
+		{
+			name:   "json fail",
+			format: "json",
+			failOn: metriclint.SeverityWarning,
+			expectedResult: `"FailOn": "warning",
+  "Failed": true
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			metriclint.NewLinter(metriclint.FailOn(tc.failOn)).JudgeReport(report)
+
+			var buf bytes.Buffer
+			if err := Write(&buf, tc.format, report); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasSuffix(buf.String(), tc.expectedResult) {
+				t.Errorf("expected: %q, but got: %q", tc.expectedResult, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+// SARIF 2.1.0 types, limited to the properties metriclint fills.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId,omitempty"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/promlint/promlint"
+)
+
+func init() {
+	RegisterFormat("sarif", OutputWriterFunc(writeSARIF))
+}
+
+// sarifLevel maps a severity to a SARIF level, issues without severity are warnings.
+func sarifLevel(severity metriclint.Severity) string {
+	if severity == metriclint.SeverityError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// writeSARIF writes the report as a SARIF 2.1.0 log with one run. The issues are located by the metric
+// name as logical location, and by the file and line declaring the metric when they were found in source
+// code. The rules reporting issues are described in the tool driver.
+func writeSARIF(w io.Writer, report *metriclint.Report) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "metriclint", InformationURI: sarifToolURI, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+
+	reported := map[string]bool{}
+	for _, result := range report.Results {
+		findings := result.Findings
+		if findings == nil {
+			findings = metriclint.IssuesFromMessages(result.Issues)
+		}
+		for _, issue := range findings {
+			key := issue.ID
+			if key == "" {
+				key = issue.Message
+			}
+			location := sarifLocation{
+				LogicalLocations: []sarifLogicalLocation{{Name: result.MetricName, Kind: "member"}},
+			}
+			if l := issue.Location; l != nil {
+				location.PhysicalLocation = &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(l.File)},
+					Region:           sarifRegion{StartLine: l.Line, StartColumn: l.Column},
+				}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:              issue.ID,
+				Level:               sarifLevel(issue.Severity),
+				Message:             sarifMessage{Text: issue.Message},
+				Locations:           []sarifLocation{location},
+				PartialFingerprints: map[string]string{"metriclint/v1": result.MetricName + "/" + key},
+			})
+			if issue.ID != "" {
+				reported[issue.ID] = true
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(reported))
+	for id := range reported {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleOf(id))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// sarifRuleOf describes a rule by its metadata. Rules unknown to metriclint, such as declarative
+// rules, are only described by their ID.
+func sarifRuleOf(id string) sarifRule {
+	info, ok := metriclint.RuleByID(id)
+	if !ok {
+		return sarifRule{ID: id, ShortDescription: sarifMessage{Text: id}, DefaultConfiguration: sarifConfiguration{Level: "warning"}}
+	}
+
+	rule := sarifRule{
+		ID:                   id,
+		ShortDescription:     sarifMessage{Text: info.Description},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(info.Severity)},
+	}
+	if info.Rationale != "" {
+		rule.FullDescription = &sarifMessage{Text: info.Rationale}
+	}
+	if info.Remediation != "" {
+		rule.Help = &sarifMessage{Text: info.Remediation}
+	}
+
+	return rule
+}
@@ -0,0 +1,100 @@
+//go:build !metriclint_minimal
+// +build !metriclint_minimal
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/promlint/promlint/pkg/metriclint"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	report := &metriclint.Report{
+		Results: []*metriclint.LintResult{
+			metriclint.LintSpec(metriclint.MetricSpec{Name: "lint_test", Type: metriclint.MetricTypeCounter}),
+			{MetricName: "lint_legacy", Issues: []string{"legacy problem"}},
+			{MetricName: "lint_test_total"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "sarif", report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, but got: %s", buf.String())
+	}
+
+	run := log.Runs[0]
+	var ruleIDs []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	expectedRules := []string{metriclint.RuleCounterTotalSuffix, metriclint.RuleHelpMissing}
+	if len(ruleIDs) != 2 || ruleIDs[0] != expectedRules[0] || ruleIDs[1] != expectedRules[1] {
+		t.Errorf("expected: %v, but got: %v", expectedRules, ruleIDs)
+	}
+	if run.Tool.Driver.Rules[0].DefaultConfiguration.Level != "error" || run.Tool.Driver.Rules[0].Help == nil {
+		t.Errorf("expected rule metadata, but got: %+v", run.Tool.Driver.Rules[0])
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("expected: 3 results, but got: %+v", run.Results)
+	}
+	legacy := run.Results[2]
+	if legacy.RuleID != "" || legacy.Level != "warning" || legacy.Message.Text != "legacy problem" ||
+		legacy.Locations[0].LogicalLocations[0].Name != "lint_legacy" || legacy.Locations[0].PhysicalLocation != nil || legacy.PartialFingerprints["metriclint/v1"] != "lint_legacy/legacy problem" {
+		t.Errorf("unexpected result: %+v", legacy)
+	}
+}
+
+func TestWriteSARIFPhysicalLocation(t *testing.T) {
+	result := &metriclint.LintResult{MetricName: "lint_requests"}
+	result.AddIssues(metriclint.Issue{
+		ID:       metriclint.RuleCounterTotalSuffix,
+		Message:  metriclint.LintErrMsgCounterShouldHaveTotalSuffix,
+		Location: &metriclint.SourceLocation{File: "pkg/server/metrics.go", Line: 14, Column: 9},
+	})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "sarif", &metriclint.Report{Results: []*metriclint.LintResult{result}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected a single result, but got: %s", buf.String())
+	}
+	location := log.Runs[0].Results[0].Locations[0]
+	expected := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "pkg/server/metrics.go"}, Region: sarifRegion{StartLine: 14, StartColumn: 9}}
+	if location.PhysicalLocation == nil || *location.PhysicalLocation != expected || location.LogicalLocations[0].Name != "lint_requests" {
+		t.Errorf("expected: %+v, but got: %+v", expected, location)
+	}
+}